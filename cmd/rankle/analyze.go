@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/output"
+	"github.com/javicosvml/rankle-go/pkg/runner"
+)
+
+// analysis runs one analyzer against a domain. The result holds only the
+// sections that analyzer fills.
+type analysis func(domain string, cfg *config.Config) (*models.ScanResult, error)
+
+// runDNS handles "rankle dns <domain>".
+func runDNS(args []string, formatter *output.Formatter) error {
+	return runAnalysis("dns", dnsAnalysis, args, formatter, formatter.TextReport)
+}
+
+// runTLS handles "rankle tls <domain>".
+func runTLS(args []string, formatter *output.Formatter) error {
+	return runAnalysis("tls", tlsAnalysis, args, formatter, formatter.TextReport)
+}
+
+// runTech handles "rankle tech <domain>".
+func runTech(args []string, formatter *output.Formatter) error {
+	return runAnalysis("tech", techAnalysis, args, formatter, formatter.TextReport)
+}
+
+// runSubdomains handles "rankle subdomains <domain>", which prints one
+// subdomain per line so the list can be piped to other tools.
+func runSubdomains(args []string, formatter *output.Formatter) error {
+	return runAnalysis("subdomains", subdomainAnalysis, args, nil, func(result *models.ScanResult) string {
+		if len(result.Subdomains) == 0 {
+			return ""
+		}
+		return strings.Join(result.Subdomains, "\n") + "\n"
+	})
+}
+
+// runAnalysis parses the arguments of a single-analysis command, runs it
+// and prints the result with text, or as JSON with --json. The banner is
+// printed when formatter is set.
+func runAnalysis(name string, run analysis, args []string, formatter *output.Formatter, text func(*models.ScanResult) string) error {
+	fs := newFlagSet(name)
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
+	domains, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(domains) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	result, err := run(domains[0], cfg)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return printJSON(result)
+	}
+	if formatter != nil {
+		printBanner(formatter)
+	}
+	fmt.Print(text(result))
+	return nil
+}
+
+// Analyses run by the single-analysis commands. The first module of each
+// is the one the command is about.
+var (
+	dnsAnalysis       = moduleAnalysis(runner.ModuleDNS)
+	tlsAnalysis       = moduleAnalysis(runner.ModuleTLS)
+	subdomainAnalysis = uncapped(moduleAnalysis(runner.ModuleSubdomains))
+	techAnalysis      = moduleAnalysis(runner.ModuleHTTP, runner.ModuleTech, runner.ModuleFavicon, runner.ModuleVulns)
+)
+
+// moduleAnalysis returns an analysis running only modules. It fails when
+// the first module does not succeed.
+func moduleAnalysis(modules ...string) analysis {
+	return func(domain string, cfg *config.Config) (*models.ScanResult, error) {
+		only := *cfg
+		only.Scanner.Modules = modules
+		only.Scanner.SkipModules = nil
+		r, err := runner.New(&only)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := r.Run(domain)
+		if err != nil {
+			return nil, err
+		}
+		if st := result.Stages[modules[0]]; st.Status != models.StageOK {
+			m, _ := runner.Find(modules[0])
+			return nil, fmt.Errorf("%s failed: %s", m.Title, st.Error)
+		}
+		return result, nil
+	}
+}
+
+// uncapped lifts the subdomain display limit for run, so the subdomains
+// command lists every name found.
+func uncapped(run analysis) analysis {
+	return func(domain string, cfg *config.Config) (*models.ScanResult, error) {
+		all := *cfg
+		all.Scanner.MaxSubdomainsDisplay = math.MaxInt
+		return run(domain, &all)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/javicosvml/rankle-go/pkg/output"
+)
+
+// registerLogFlags adds the output options to fs. Progress and diagnostics
+// go to stderr through slog, so stdout carries only results.
+func registerLogFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&quiet, "quiet", quiet, "Only log errors; no banner or closing message")
+	fs.BoolVar(&quiet, "q", quiet, "Only log errors (shorthand)")
+	fs.BoolVar(&noBanner, "no-banner", noBanner, "Leave out the banner and closing message")
+	fs.StringVar(&logLevel, "log-level", logLevel, "Log `LEVEL`: debug, info, warn or error")
+	fs.StringVar(&logFormat, "log-format", logFormat, "Log `FORMAT`: text or json")
+}
+
+// setupLogging points the default slog logger at stderr with the level and
+// format of the flags. --quiet raises the level to error.
+func setupLogging() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+		return fmt.Errorf("--log-level: %q is not debug, info, warn or error", logLevel)
+	}
+	if quiet && level < slog.LevelError {
+		level = slog.LevelError
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch logFormat {
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("--log-format: %q is not text or json", logFormat)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// printBanner shows the banner on stderr unless --quiet or --no-banner is
+// given.
+func printBanner(formatter *output.Formatter) {
+	if quiet || noBanner {
+		return
+	}
+	fmt.Fprintln(os.Stderr, formatter.Banner())
+}
+
+// printFarewell shows the closing message on stderr unless --quiet or
+// --no-banner is given.
+func printFarewell() {
+	if quiet || noBanner {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "\n🃏 Thank you for using Rankle!")
+	fmt.Fprintln(os.Stderr, `   "Master of Pranks knows all your secrets..."`)
+	fmt.Fprintln(os.Stderr)
+}
@@ -1,19 +1,16 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/javicosvml/rankle-go/internal/config"
-	"github.com/javicosvml/rankle-go/pkg/detector"
-	"github.com/javicosvml/rankle-go/pkg/dns"
-	"github.com/javicosvml/rankle-go/pkg/models"
 	"github.com/javicosvml/rankle-go/pkg/output"
-	"github.com/javicosvml/rankle-go/pkg/scanner"
-	"github.com/javicosvml/rankle-go/pkg/tls"
+	"github.com/javicosvml/rankle-go/pkg/runner"
 )
 
 const (
@@ -31,10 +28,27 @@ var (
 	jsonOutput  bool
 	textOutput  bool
 	outputType  string
+	formatList  string
+	outPath     string
+	configFile  string
 	showVersion bool
 	showHelp    bool
+	quiet       bool
+	noBanner    bool
+	logLevel    = "info"
+	logFormat   = "text"
+
+	// settings holds the configuration flags (--http-timeout,
+	// --nameserver, --js...), applied over the configuration file.
+	settings *config.Flags
+
+	// errUsage reports bad arguments once the command's usage is printed.
+	errUsage = errors.New("invalid arguments")
 )
 
+// scanFlags are the top-level flags that only the scan command uses.
+var scanFlags = map[string]bool{"json": true, "j": true, "text": true, "t": true, "output": true, "o": true, "format": true, "out": true}
+
 func init() {
 	flag.BoolVar(&jsonOutput, "json", false, "Save results as JSON")
 	flag.BoolVar(&jsonOutput, "j", false, "Save results as JSON (shorthand)")
@@ -42,10 +56,49 @@ func init() {
 	flag.BoolVar(&textOutput, "t", false, "Save results as text report (shorthand)")
 	flag.StringVar(&outputType, "output", "", "Save output (json/text/both)")
 	flag.StringVar(&outputType, "o", "", "Save output (json/text/both) (shorthand)")
+	flag.StringVar(&formatList, "format", "", "Output formats (json,html,csv,text,summary)")
+	flag.StringVar(&outPath, "out", "", "Output file or directory, - for stdout")
+	flag.StringVar(&configFile, "config", "", "Configuration file (YAML or TOML)")
 	flag.BoolVar(&showVersion, "version", false, "Show version information")
 	flag.BoolVar(&showVersion, "v", false, "Show version information (shorthand)")
 	flag.BoolVar(&showHelp, "help", false, "Show help message")
 	flag.BoolVar(&showHelp, "h", false, "Show help message (shorthand)")
+	registerLogFlags(flag.CommandLine)
+	settings = config.RegisterFlags(flag.CommandLine)
+}
+
+// command is a rankle subcommand. Usage is the synopsis after "rankle".
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string, formatter *output.Formatter) error
+}
+
+// commands lists the subcommands in help order.
+func commands() []command {
+	return []command{
+		{"scan", "scan <domain> [options]", "Run every analysis (also: rankle <domain>)", runScan},
+		{"dns", "dns <domain> [--json]", "Resolve and print the DNS records", runDNS},
+		{"tls", "tls <domain> [--json]", "Analyze the TLS certificate", runTLS},
+		{"tech", "tech <domain> [--json]", "Detect technologies, third parties and known CVEs", runTech},
+		{"subdomains", "subdomains <domain> [--json]", "List subdomains from Certificate Transparency", runSubdomains},
+		{"report", "report <scan.json> [--format LIST] [--out PATH]", "Render a saved JSON scan as text, HTML, CSV...", runReport},
+		{"diff", "diff <old.json> <new.json> [--json]", "Compare two saved JSON scans", runDiff},
+		{"related", "related <domain> [--dir DIR] [--json]", "Link stored scans by shared IDs, certs and IPs", runRelated},
+		{"serve", "serve [--addr ADDR] [--max-scans N]", "Serve scans as JSON over HTTP", runServe},
+		{"config", "config dump", "Print the effective configuration as YAML", runConfig},
+	}
+}
+
+// findCommand returns the subcommand called name.
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
 }
 
 func main() {
@@ -73,215 +126,272 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Get domain from arguments
-	domain := flag.Arg(0)
-
-	// Print banner
-	formatter.PrintBanner()
-
-	// Initialize configuration
-	cfg := config.Default()
-
-	// Run scan
-	result, err := performScan(domain, cfg)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "\n❌ Error during scan: %v\n", err)
-		os.Exit(1)
+	// "rankle <domain>" is a full scan.
+	cmd, ok := findCommand(flag.Arg(0))
+	args := flag.Args()[1:]
+	if !ok {
+		cmd, _ = findCommand("scan")
+		args = flag.Args()
 	}
 
-	// Print summary
-	formatter.PrintSummary(result)
+	if cmd.name != "scan" {
+		var misplaced []string
+		flag.Visit(func(f *flag.Flag) {
+			if scanFlags[f.Name] {
+				misplaced = append(misplaced, "--"+f.Name)
+			}
+		})
+		if len(misplaced) > 0 {
+			fmt.Fprintf(os.Stderr, "\n❌ %s: only valid for the scan command\n", strings.Join(misplaced, ", "))
+			os.Exit(2)
+		}
+	}
 
-	// Handle output saving
-	if err := handleOutput(result, domain, formatter); err != nil {
-		fmt.Fprintf(os.Stderr, "\n❌ Error saving output: %v\n", err)
+	if err := cmd.run(args, formatter); err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		slog.Error("command failed", "command", cmd.name, "error", err)
+		var failed stageFailure
+		if errors.As(err, &failed) {
+			os.Exit(3)
+		}
 		os.Exit(1)
 	}
-
-	fmt.Println("\n🃏 Thank you for using Rankle!")
-	fmt.Println(`   "Master of Pranks knows all your secrets..."`)
-	fmt.Println()
 }
 
-func performScan(domain string, cfg *config.Config) (*models.ScanResult, error) {
-	// Initialize components
-	scan := scanner.New(cfg)
-	det := detector.New()
-	dnsResolver := dns.New(cfg)
-	tlsAnalyzer := tls.New(cfg)
+// stageFailure reports the stages that failed under the --fail-on policy;
+// the scan itself completed and its results were written.
+type stageFailure []string
 
-	// Create result
-	result, err := scan.Scan(domain)
-	if err != nil {
-		return nil, err
-	}
-
-	// HTTP Analysis
-	fmt.Println("\n🌐 Analyzing HTTP Headers...")
-	httpAnalysis, resp, err := scan.AnalyzeHTTP(domain)
-	if err != nil {
-		fmt.Printf("⚠️  HTTP analysis failed: %v\n", err)
-	} else {
-		result.HTTP = httpAnalysis
-
-		// Get HTML body for technology detection
-		if body, err := scan.GetHTMLBody(resp); err == nil {
-			fmt.Println("🔍 Detecting Technologies...")
-			result.Technologies = det.DetectTechnologies(body, httpAnalysis.Headers)
+func (f stageFailure) Error() string {
+	return "failed stages: " + strings.Join(f, ", ")
+}
 
-			// Extract security headers
-			result.SecurityHeaders = extractSecurityHeaders(httpAnalysis.Headers)
+// newFlagSet creates the flag set of a subcommand. Besides the command's
+// own flags it takes --config, the output options and the settings flags,
+// so they can be given before or after the command name; -h lists the
+// command's own flags.
+func newFlagSet(name string) *flag.FlagSet {
+	cmd, _ := findCommand(name)
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(&configFile, "config", configFile, "Configuration file (YAML or TOML)")
+	registerLogFlags(fs)
+	settings.Register(fs)
+
+	global := make(map[string]bool)
+	fs.VisitAll(func(f *flag.Flag) { global[f.Name] = true })
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "usage: rankle %s\n\n  %s\n\n", cmd.usage, cmd.summary)
+		own := 0
+		fs.VisitAll(func(f *flag.Flag) {
+			if global[f.Name] {
+				return
+			}
+			name := "--" + f.Name
+			if len(f.Name) == 1 {
+				name = "-" + f.Name
+			}
+			arg, help := flag.UnquoteUsage(f)
+			fmt.Fprintf(out, "  %-22s %s\n", strings.TrimSpace(name+" "+arg), help)
+			own++
+		})
+		if own > 0 {
+			fmt.Fprintln(out)
 		}
+		fmt.Fprintln(out, "  --config, the output options and the settings flags listed by rankle --help")
+		fmt.Fprintln(out, "  are also accepted.")
 	}
+	return fs
+}
 
-	// DNS Analysis
-	fmt.Println("🔎 Analyzing DNS Records...")
-	dnsAnalysis, err := dnsResolver.Analyze(domain)
+// parseArgs parses args with fs, allowing flags after positional arguments,
+// and returns the positional arguments. Everything after "--" is
+// positional. Logging is set up from the parsed flags.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional, err := parseInterspersed(fs, args)
 	if err != nil {
-		fmt.Printf("⚠️  DNS analysis failed: %v\n", err)
-	} else {
-		result.DNS = dnsAnalysis
-
-		// Detect CDN and WAF
-		if result.HTTP != nil {
-			result.CDN = det.DetectCDN(result.HTTP.Headers, dnsAnalysis.CNAME)
-			result.WAF = det.DetectWAF(result.HTTP.Headers, nil)
-		}
+		return nil, err
 	}
-
-	// TLS Analysis
-	fmt.Println("🔐 Analyzing TLS Certificate...")
-	tlsAnalysis, err := tlsAnalyzer.Analyze(domain)
-	if err != nil {
-		fmt.Printf("⚠️  TLS analysis failed: %v\n", err)
-	} else {
-		result.TLS = tlsAnalysis
+	if err := setupLogging(); err != nil {
+		fmt.Fprintf(fs.Output(), "%v\n", err)
+		fs.Usage()
+		return nil, errUsage
 	}
+	return positional, nil
+}
 
-	// Subdomain Discovery
-	fmt.Println("🔍 Discovering Subdomains (Certificate Transparency)...")
-	subdomains, err := dnsResolver.EnumerateSubdomains(domain)
-	if err != nil {
-		fmt.Printf("⚠️  Subdomain discovery failed: %v\n", err)
-	} else {
-		if len(subdomains) > cfg.Scanner.MaxSubdomainsDisplay {
-			result.Subdomains = subdomains[:cfg.Scanner.MaxSubdomainsDisplay]
-		} else {
-			result.Subdomains = subdomains
+// parseInterspersed parses args with fs, collecting the positional
+// arguments between flags.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
 		}
-		fmt.Printf("   Found %d subdomains\n", len(subdomains))
-	}
-
-	// Geolocation (if we have an IP)
-	if result.DNS != nil && len(result.DNS.A) > 0 {
-		fmt.Println("🌍 Analyzing Geolocation...")
-		ip := result.DNS.A[0]
-
-		// Reverse DNS lookup
-		if hostnames, err := dnsResolver.ReverseLookup(ip); err == nil && len(hostnames) > 0 {
-			hostname := hostnames[0]
-
-			// Detect cloud provider
-			isp := ""
-			if result.Geolocation != nil {
-				isp = result.Geolocation.ISP
-			}
-			result.CloudProvider = det.DetectCloudProvider(ip, hostname, isp)
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
 		}
-
-		// Note: Geolocation API call would go here
-		// For now, we'll just store the IP
-		result.Geolocation = &models.Geolocation{
-			IP: ip,
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
 		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
 	}
-
-	return result, nil
 }
 
-func handleOutput(result *models.ScanResult, domain string, formatter *output.Formatter) error {
-	// Determine output directory
-	outputDir := "reports"
-	if _, err := os.Stat("/output/"); err == nil {
-		outputDir = "/output"
+// loadConfig builds the effective configuration: defaults, then the file
+// given with --config or found in the user's config directory, then
+// RANKLE_* environment variables, then the flags given on the command line.
+// It also returns the file used, if any.
+func loadConfig() (*config.Config, string, error) {
+	path := configFile
+	if path == "" {
+		path = config.DefaultPath()
 	}
-
-	// Sanitize domain for filename
-	safeDomain := strings.ReplaceAll(domain, ".", "_")
-
-	// Handle command-line flags
-	saveJSON := jsonOutput || outputType == "json" || outputType == "both"
-	saveText := textOutput || outputType == "text" || outputType == "both"
-
-	if saveJSON {
-		jsonPath := filepath.Join(outputDir, safeDomain+"_rankle.json")
-		if err := formatter.SaveJSON(result, jsonPath); err != nil {
-			return err
-		}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, "", err
 	}
 
-	if saveText {
-		textPath := filepath.Join(outputDir, safeDomain+"_rankle_report.txt")
-		if err := formatter.SaveText(result, textPath); err != nil {
-			return err
-		}
+	if err := settings.Apply(cfg); err != nil {
+		return nil, "", err
 	}
-
-	return nil
+	if err := cfg.Validate(); err != nil {
+		return nil, "", err
+	}
+	return cfg, path, nil
 }
 
-func extractSecurityHeaders(headers map[string]string) map[string]string {
-	securityHeaders := make(map[string]string)
-
-	securityHeaderKeys := []string{
-		"strict-transport-security",
-		"content-security-policy",
-		"x-frame-options",
-		"x-content-type-options",
-		"x-xss-protection",
-		"referrer-policy",
-		"permissions-policy",
+// runConfig handles "rankle config dump", which prints the effective
+// configuration as YAML.
+func runConfig(args []string, _ *output.Formatter) error {
+	fs := newFlagSet("config")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 || rest[0] != "dump" {
+		fs.Usage()
+		return errUsage
 	}
 
-	for _, key := range securityHeaderKeys {
-		if value, exists := headers[key]; exists {
-			securityHeaders[key] = value
-		}
+	cfg, path, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		path = "none"
 	}
+	fmt.Printf("# Effective rankle configuration (config file: %s)\n", path)
+	fmt.Println("# Defaults, overridden by the config file, RANKLE_* variables and flags.")
+	return cfg.WriteYAML(os.Stdout)
+}
 
-	return securityHeaders
+// printSettings lists the configuration flags, grouped by section.
+func printSettings() {
+	section := ""
+	for _, field := range config.Default().Fields() {
+		if field.Flag == "" {
+			continue
+		}
+		if sec, _, _ := strings.Cut(field.Key, "."); sec != section {
+			fmt.Printf("\n%s SETTINGS:\n", strings.ToUpper(sec))
+			section = sec
+		}
+		name := "--" + field.Flag
+		if arg := field.ArgName(); arg != "" {
+			name += " " + arg
+		}
+		fmt.Printf("  %-30s %s\n", name, field.Help)
+	}
+	fmt.Println("\n  Settings flags override the configuration file and RANKLE_* variables.")
+	fmt.Println("  LIST flags take comma-separated values and may be repeated.")
 }
 
 func printUsage(formatter *output.Formatter) {
-	formatter.PrintBanner()
+	printBanner(formatter)
 
 	fmt.Println("\n" + strings.Repeat("=", lineWidth))
 	fmt.Println("📖 USAGE")
 	fmt.Println(strings.Repeat("=", lineWidth))
-	fmt.Println("\n  rankle <domain> [options]")
+	fmt.Println("\n  rankle <domain> [options]            Full scan (same as rankle scan)")
+	fmt.Println("  rankle <command> [arguments] [options]")
+	fmt.Println("\nCOMMANDS:")
+	for _, cmd := range commands() {
+		fmt.Printf("  %-12s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Println("\n  Run rankle <command> -h for its arguments. Options may come before or")
+	fmt.Println("  after the command and its arguments.")
 	fmt.Println("\nEXAMPLES:")
 	fmt.Println("  rankle example.com")
 	fmt.Println("  rankle https://example.com")
 	fmt.Println("  rankle subdomain.example.com")
 	fmt.Println("  rankle example.com --json")
 	fmt.Println("  rankle example.com --output both")
+	fmt.Println("  rankle example.com --format json,html,csv --out ./out/")
+	fmt.Println("  rankle example.com --format json --out - --quiet | jq .dns")
+	fmt.Println("  rankle example.com --fingerprints ./wappalyzer/src/technologies")
+	fmt.Println("  rankle example.com --well-known")
+	fmt.Println("  rankle example.com --modules dns,tls --json")
+	fmt.Println("  rankle example.com --skip subdomains,cloud")
+	fmt.Println("  rankle example.com --nameserver 1.1.1.1 --http-timeout 60s --verify-tls")
+	fmt.Println("  rankle dns example.com --nameserver 9.9.9.9 --json")
+	fmt.Println("  rankle subdomains example.com > subdomains.txt")
+	fmt.Println("  rankle diff old/example_com_rankle.json reports/example_com_rankle.json")
+	fmt.Println("  rankle related example.com")
+	fmt.Println("  rankle serve --addr 127.0.0.1:8080")
 	fmt.Println("\nOPTIONS:")
-	fmt.Println("  -j, --json          Save results as JSON")
-	fmt.Println("  -t, --text          Save results as text report")
-	fmt.Println("  -o, --output TYPE   Save output (json/text/both)")
+	fmt.Println("  -j, --json          Save results as JSON (scan)")
+	fmt.Println("  -t, --text          Save results as text report (scan)")
+	fmt.Println("  -o, --output TYPE   Save output (json/text/both) (scan)")
+	fmt.Println("  --format LIST       Output formats: " + strings.Join(output.Formats(), ",") + " (scan)")
+	fmt.Println("  --out PATH          File or directory for --format, - for stdout (scan);")
+	fmt.Println("                      default: the reports directory")
+	fmt.Println("  --config FILE       Configuration file (YAML or TOML); default")
+	fmt.Println("                      $XDG_CONFIG_HOME/rankle/config.yaml")
+	fmt.Println("  -q, --quiet         Only log errors; no banner or closing message")
+	fmt.Println("  --no-banner         Leave out the banner and closing message")
+	fmt.Println("  --log-level LEVEL   debug, info, warn or error (default info)")
+	fmt.Println("  --log-format FMT    text or json (default text)")
 	fmt.Println("  -v, --version       Show version information")
 	fmt.Println("  -h, --help          Show this help message")
+	printSettings()
+	fmt.Println("\nSCAN MODULES (--modules, --skip):")
+	for _, m := range runner.Registry() {
+		description := m.Description
+		if m.Active {
+			description += " (opt-in)"
+		}
+		fmt.Printf("  %-16s %s\n", m.Name, description)
+	}
 	fmt.Println("\nFEATURES:")
 	fmt.Println("  • DNS enumeration and configuration analysis")
 	fmt.Println("  • Subdomain discovery via Certificate Transparency")
 	fmt.Println("  • Web technology stack detection (CMS, frameworks)")
+	fmt.Println("  • Third-party service inventory with tracking IDs (GA, GTM, Pixel)")
 	fmt.Println("  • TLS/SSL certificate analysis")
+	fmt.Println("  • HTTP/1.1, HTTP/2 (ALPN) and HTTP/3 (Alt-Svc, QUIC) support")
 	fmt.Println("  • HTTP security headers audit")
-	fmt.Println("  • CDN and WAF detection")
+	fmt.Println("  • Favicon hash (Shodan mmh3) product identification")
+	fmt.Println("  • Well-known file probing (robots.txt, security.txt, OpenID) (opt-in)")
+	fmt.Println("  • Exposed sensitive file and admin panel checks (opt-in)")
+	fmt.Println("  • HTTP method and CORS misconfiguration checks (opt-in)")
+	fmt.Println("  • Multi-CDN and WAF detection, with active WAF probing (opt-in)")
+	fmt.Println("  • Exposed origin server discovery behind CDNs (opt-in)")
+	fmt.Println("  • JavaScript endpoint and secret extraction (opt-in)")
+	fmt.Println("  • Offline known-vulnerability matching for detected versions")
 	fmt.Println("  • Cloud provider identification")
+	fmt.Println("  • Related domain clusters from saved JSON scans (shared IDs, certs, IPs)")
 	fmt.Println("  • JSON and text report export")
+	fmt.Println("  • Change tracking between saved scans (rankle diff)")
+	fmt.Println("  • HTTP JSON API for scans and single analyzers (rankle serve)")
 	fmt.Println("\nNOTE:")
-	fmt.Println("  All reconnaissance is passive and uses public data sources.")
-	fmt.Println("  No active scanning or intrusive techniques are employed.")
+	fmt.Println("  By default reconnaissance is passive and uses public data sources.")
+	fmt.Println("  Active checks (--well-known, --sensitive-files, --http-security,")
+	fmt.Println("  --waf-probe, --origin, --js) send extra requests; only use them against")
+	fmt.Println("  sites you are authorized to test.")
 	fmt.Println(strings.Repeat("=", lineWidth) + "\n")
 }
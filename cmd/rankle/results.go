@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/diff"
+	"github.com/javicosvml/rankle-go/pkg/output"
+	"github.com/javicosvml/rankle-go/pkg/related"
+)
+
+// runReport handles "rankle report <scan.json>", which renders a saved JSON
+// scan in another format, the text report by default, on stdout or into a
+// file.
+func runReport(args []string, _ *output.Formatter) error {
+	fs := newFlagSet("report")
+	format := fs.String("format", output.FormatText, "Render as each of `LIST`, e.g. text,html")
+	out := fs.String("out", stdoutPath, "Write to `PATH` instead of stdout")
+	files, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	formats, err := checkFormats(strings.Split(*format, ","))
+	if err != nil {
+		return err
+	}
+	if len(formats) == 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	result, err := diff.Load(files[0])
+	if err != nil {
+		return err
+	}
+	return writeResult(result, formats, *out)
+}
+
+// runDiff handles "rankle diff <old.json> <new.json>", which lists what
+// changed between two saved scans.
+func runDiff(args []string, formatter *output.Formatter) error {
+	fs := newFlagSet("diff")
+	asJSON := fs.Bool("json", false, "Print the changes as JSON")
+	files, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 2 {
+		fs.Usage()
+		return errUsage
+	}
+
+	old, err := diff.Load(files[0])
+	if err != nil {
+		return err
+	}
+	current, err := diff.Load(files[1])
+	if err != nil {
+		return err
+	}
+	changes := diff.Compare(old, current)
+
+	if *asJSON {
+		return printJSON(changes)
+	}
+	formatter.PrintDiff(changes)
+	fmt.Println()
+	return nil
+}
+
+// runRelated prints the domains linked to a domain through the scan results
+// stored in the reports directory.
+func runRelated(args []string, formatter *output.Formatter) error {
+	fs := newFlagSet("related")
+	dir := fs.String("dir", reportDir(), "Directory holding JSON scan results")
+	asJSON := fs.Bool("json", false, "Print the cluster as JSON")
+	domains, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(domains) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	index := related.New()
+	if _, err := index.Load(*dir); err != nil {
+		return err
+	}
+	cluster := index.Cluster(domains[0])
+
+	if *asJSON {
+		return printJSON(cluster)
+	}
+
+	printBanner(formatter)
+	formatter.PrintRelated(cluster)
+	fmt.Println()
+	return nil
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// reportDir returns the directory reports are saved to: /output when it
+// exists, reports otherwise.
+func reportDir() string {
+	if _, err := os.Stat("/output/"); err == nil {
+		return "/output"
+	}
+	return "reports"
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/output"
+	"github.com/javicosvml/rankle-go/pkg/runner"
+)
+
+// runScan handles "rankle scan <domain>", the full scan, which is also run
+// for "rankle <domain>".
+func runScan(args []string, formatter *output.Formatter) error {
+	fs := newFlagSet("scan")
+	fs.BoolVar(&jsonOutput, "json", jsonOutput, "Save results as JSON (same as --format json)")
+	fs.BoolVar(&jsonOutput, "j", jsonOutput, "Save results as JSON (shorthand)")
+	fs.BoolVar(&textOutput, "text", textOutput, "Save results as text report (same as --format text)")
+	fs.BoolVar(&textOutput, "t", textOutput, "Save results as text report (shorthand)")
+	fs.StringVar(&outputType, "output", outputType, "Save output as `TYPE` (json/text/both)")
+	fs.StringVar(&outputType, "o", outputType, "Save output as `TYPE` (shorthand)")
+	fs.StringVar(&formatList, "format", formatList, "Write the result in each of `LIST`, e.g. json,html,csv")
+	fs.StringVar(&outPath, "out", outPath, "Write to `PATH` instead of the reports directory, - for stdout")
+	domains, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(domains) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	domain := domains[0]
+
+	// Initialize configuration
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	formats, err := scanFormats()
+	if err != nil {
+		return err
+	}
+	r, err := runner.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Print banner
+	printBanner(formatter)
+
+	// Run scan
+	result, err := r.Run(domain)
+	if err != nil {
+		return fmt.Errorf("error during scan: %w", err)
+	}
+
+	// The summary is shown unless a format goes to stdout.
+	if outPath != stdoutPath {
+		formatter.PrintSummary(result)
+	}
+	if len(formats) > 0 {
+		out := outPath
+		if out == "" {
+			out = reportDir() + string(filepath.Separator)
+		}
+		if err := writeResult(result, formats, out); err != nil {
+			return fmt.Errorf("error saving output: %w", err)
+		}
+	}
+
+	printFarewell()
+
+	if failed := runner.Failures(result, cfg.Scanner.FailOn); len(failed) > 0 {
+		return stageFailure(failed)
+	}
+	return nil
+}
+
+// scanAnalysis runs the modules cfg selects against domain.
+func scanAnalysis(domain string, cfg *config.Config) (*models.ScanResult, error) {
+	r, err := runner.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return r.Run(domain)
+}
+
+// stdoutPath is the --out value that writes to stdout.
+const stdoutPath = "-"
+
+// errStdoutFormats rejects several formats written to stdout.
+var errStdoutFormats = errors.New("--out -: only one format can be written to stdout")
+
+// scanFormats returns the formats chosen with --format and the --json,
+// --text and --output shorthands, without duplicates. --out alone writes
+// JSON.
+func scanFormats() ([]string, error) {
+	var names []string
+	if formatList != "" {
+		names = strings.Split(formatList, ",")
+	}
+	if jsonOutput {
+		names = append(names, output.FormatJSON)
+	}
+	if textOutput {
+		names = append(names, output.FormatText)
+	}
+	switch outputType {
+	case "":
+	case "json", "text":
+		names = append(names, outputType)
+	case "both":
+		names = append(names, output.FormatJSON, output.FormatText)
+	default:
+		return nil, fmt.Errorf("--output: %q is not json, text or both", outputType)
+	}
+	if len(names) == 0 && outPath != "" {
+		names = append(names, output.FormatJSON)
+	}
+	formats, err := checkFormats(names)
+	if err == nil && outPath == stdoutPath && len(formats) != 1 {
+		err = errStdoutFormats
+	}
+	return formats, err
+}
+
+// checkFormats normalizes format names, drops duplicates and makes sure
+// each is registered.
+func checkFormats(names []string) ([]string, error) {
+	var formats []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, err := output.Lookup(name); err != nil {
+			return nil, err
+		}
+		seen[name] = true
+		formats = append(formats, name)
+	}
+	return formats, nil
+}
+
+// writeResult writes result in each format to out. "-" is stdout and takes
+// a single format. A path ending in a separator or naming a directory gets
+// one <domain>_rankle.<ext> file per format; otherwise it is the file of
+// the single format.
+func writeResult(result *models.ScanResult, formats []string, out string) error {
+	if out == stdoutPath {
+		if len(formats) != 1 {
+			return errStdoutFormats
+		}
+		w, err := output.Lookup(formats[0])
+		if err != nil {
+			return err
+		}
+		return w.Write(os.Stdout, result)
+	}
+
+	dir := ""
+	if info, err := os.Stat(out); (err == nil && info.IsDir()) || strings.HasSuffix(out, string(filepath.Separator)) {
+		dir = out
+	} else if len(formats) > 1 {
+		return fmt.Errorf("--out %s: several formats need a directory, got file path", out)
+	}
+
+	base := strings.ReplaceAll(result.Domain, ".", "_") + "_rankle"
+	used := make(map[string]bool)
+	for _, format := range formats {
+		path := out
+		if dir != "" {
+			w, err := output.Lookup(format)
+			if err != nil {
+				return err
+			}
+			name := base + "." + w.Extension()
+			if used[name] {
+				name = base + "_" + format + "." + w.Extension()
+			}
+			used[name] = true
+			path = filepath.Join(dir, name)
+		}
+		if err := output.Save(format, path, result); err != nil {
+			return err
+		}
+		slog.Info("report saved", "format", format, "path", path)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/output"
+)
+
+// readHeaderTimeout bounds how long a client may take to send its request
+// headers.
+const readHeaderTimeout = 10 * time.Second
+
+// runServe handles "rankle serve", which answers GET /<analysis>/<domain>
+// (scan, dns, tls, tech, subdomains) with the result as JSON. Scans beyond
+// --max-scans wait for a slot.
+func runServe(args []string, formatter *output.Formatter) error {
+	fs := newFlagSet("serve")
+	addr := fs.String("addr", "127.0.0.1:8080", "Address to listen on")
+	maxScans := fs.Int("max-scans", 2, "Analyses run at the same time")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 || *maxScans < 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	cfg, _, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	analyses := map[string]analysis{
+		"scan":       scanAnalysis,
+		"dns":        dnsAnalysis,
+		"tls":        tlsAnalysis,
+		"tech":       techAnalysis,
+		"subdomains": subdomainAnalysis,
+	}
+	slots := make(chan struct{}, *maxScans)
+	mux := http.NewServeMux()
+	for name, run := range analyses {
+		mux.Handle("GET /"+name+"/{domain}", analysisHandler(run, cfg, slots))
+	}
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": version})
+	})
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	printBanner(formatter)
+	slog.Info("serving", "url", "http://"+*addr, "endpoints", "/scan/, /dns/, /tls/, /tech/, /subdomains/")
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// analysisHandler runs an analysis for the {domain} path value once a slot
+// is free. Failures are answered with 502 and the error.
+func analysisHandler(run analysis, cfg *config.Config, slots chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		domain := strings.TrimSpace(r.PathValue("domain"))
+		if domain == "" || strings.ContainsAny(domain, ":/?# ") {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid domain"})
+			return
+		}
+
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		case <-r.Context().Done():
+			return
+		}
+
+		result, err := run(domain, cfg)
+		if err != nil {
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// writeJSON answers with v as JSON.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
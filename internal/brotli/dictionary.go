@@ -0,0 +1,235 @@
+package brotli
+
+import _ "embed"
+
+// dictionary is the static dictionary of RFC 7932 Appendix A: words of 4 to
+// 24 bytes, grouped by length.
+//
+//go:embed dictionary.bin
+var dictionary string
+
+// dictionarySizeBits is the log2 of the number of words of each length.
+var dictionarySizeBits = [25]uint{
+	0, 0, 0, 0, 10, 10, 11, 11, 10, 10, 10, 10, 10,
+	9, 9, 8, 7, 7, 8, 7, 7, 6, 6, 5, 5,
+}
+
+// dictionaryOffsets is the offset of the first word of each length.
+var dictionaryOffsets = [25]int{
+	0, 0, 0, 0, 0, 4096, 9216, 21504, 35840, 44032, 53248, 63488, 74752,
+	87040, 93696, 100864, 104704, 106752, 108928, 113536, 115968, 118528,
+	119872, 121280, 122016,
+}
+
+// Word transforms (RFC 7932 Appendix B).
+const (
+	identity = iota
+	omitLast1
+	omitLast2
+	omitLast3
+	omitLast4
+	omitLast5
+	omitLast6
+	omitLast7
+	omitLast8
+	omitLast9
+	uppercaseFirst
+	uppercaseAll
+	omitFirst1
+	omitFirst2
+	omitFirst3
+	omitFirst4
+	omitFirst5
+	omitFirst6
+	omitFirst7
+	omitFirst8
+	omitFirst9
+)
+
+// transform turns a dictionary word into prefix + kind(word) + suffix.
+type transform struct {
+	prefix string
+	kind   int
+	suffix string
+}
+
+var transforms = [...]transform{
+	{"", identity, ""},
+	{"", identity, " "},
+	{" ", identity, " "},
+	{"", omitFirst1, ""},
+	{"", uppercaseFirst, " "},
+	{"", identity, " the "},
+	{" ", identity, ""},
+	{"s ", identity, " "},
+	{"", identity, " of "},
+	{"", uppercaseFirst, ""},
+	{"", identity, " and "},
+	{"", omitFirst2, ""},
+	{"", omitLast1, ""},
+	{", ", identity, " "},
+	{"", identity, ", "},
+	{" ", uppercaseFirst, " "},
+	{"", identity, " in "},
+	{"", identity, " to "},
+	{"e ", identity, " "},
+	{"", identity, "\""},
+	{"", identity, "."},
+	{"", identity, "\">"},
+	{"", identity, "\n"},
+	{"", omitLast3, ""},
+	{"", identity, "]"},
+	{"", identity, " for "},
+	{"", omitFirst3, ""},
+	{"", omitLast2, ""},
+	{"", identity, " a "},
+	{"", identity, " that "},
+	{" ", uppercaseFirst, ""},
+	{"", identity, ". "},
+	{".", identity, ""},
+	{" ", identity, ", "},
+	{"", omitFirst4, ""},
+	{"", identity, " with "},
+	{"", identity, "'"},
+	{"", identity, " from "},
+	{"", identity, " by "},
+	{"", omitFirst5, ""},
+	{"", omitFirst6, ""},
+	{" the ", identity, ""},
+	{"", omitLast4, ""},
+	{"", identity, ". The "},
+	{"", uppercaseAll, ""},
+	{"", identity, " on "},
+	{"", identity, " as "},
+	{"", identity, " is "},
+	{"", omitLast7, ""},
+	{"", omitLast1, "ing "},
+	{"", identity, "\n\t"},
+	{"", identity, ":"},
+	{" ", identity, ". "},
+	{"", identity, "ed "},
+	{"", omitFirst9, ""},
+	{"", omitFirst7, ""},
+	{"", omitLast6, ""},
+	{"", identity, "("},
+	{"", uppercaseFirst, ", "},
+	{"", omitLast8, ""},
+	{"", identity, " at "},
+	{"", identity, "ly "},
+	{" the ", identity, " of "},
+	{"", omitLast5, ""},
+	{"", omitLast9, ""},
+	{" ", uppercaseFirst, ", "},
+	{"", uppercaseFirst, "\""},
+	{".", identity, "("},
+	{"", uppercaseAll, " "},
+	{"", uppercaseFirst, "\">"},
+	{"", identity, "=\""},
+	{" ", identity, "."},
+	{".com/", identity, ""},
+	{" the ", identity, " of the "},
+	{"", uppercaseFirst, "'"},
+	{"", identity, ". This "},
+	{"", identity, ","},
+	{".", identity, " "},
+	{"", uppercaseFirst, "("},
+	{"", uppercaseFirst, "."},
+	{"", identity, " not "},
+	{" ", identity, "=\""},
+	{"", identity, "er "},
+	{" ", uppercaseAll, " "},
+	{"", identity, "al "},
+	{" ", uppercaseAll, ""},
+	{"", identity, "='"},
+	{"", uppercaseAll, "\""},
+	{"", uppercaseFirst, ". "},
+	{" ", identity, "("},
+	{"", identity, "ful "},
+	{" ", uppercaseFirst, ". "},
+	{"", identity, "ive "},
+	{"", identity, "less "},
+	{"", uppercaseAll, "'"},
+	{"", identity, "est "},
+	{" ", uppercaseFirst, "."},
+	{"", uppercaseAll, "\">"},
+	{" ", identity, "='"},
+	{"", uppercaseFirst, ","},
+	{"", identity, "ize "},
+	{"", uppercaseAll, "."},
+	{"\xc2\xa0", identity, ""},
+	{" ", identity, ","},
+	{"", uppercaseFirst, "=\""},
+	{"", uppercaseAll, "=\""},
+	{"", identity, "ous "},
+	{"", uppercaseAll, ", "},
+	{"", uppercaseFirst, "='"},
+	{" ", uppercaseFirst, ","},
+	{" ", uppercaseAll, "=\""},
+	{" ", uppercaseAll, ", "},
+	{"", uppercaseAll, ","},
+	{"", uppercaseAll, "("},
+	{"", uppercaseAll, ". "},
+	{" ", uppercaseAll, "."},
+	{"", uppercaseAll, "='"},
+	{" ", uppercaseAll, ". "},
+	{" ", uppercaseFirst, "=\""},
+	{" ", uppercaseAll, "='"},
+	{" ", uppercaseFirst, "='"},
+}
+
+// dictionaryWord returns the transformed dictionary word of the given
+// length, or false if the reference is out of range.
+func dictionaryWord(length, wordID int) ([]byte, bool) {
+	if length < 4 || length > 24 {
+		return nil, false
+	}
+	bits := dictionarySizeBits[length]
+	index := wordID & (1<<bits - 1)
+	id := wordID >> bits
+	if id >= len(transforms) {
+		return nil, false
+	}
+	t := transforms[id]
+	start := dictionaryOffsets[length] + index*length
+	word := []byte(dictionary[start : start+length])
+
+	switch {
+	case t.kind >= omitLast1 && t.kind <= omitLast9:
+		word = word[:max(len(word)-t.kind, 0)]
+	case t.kind >= omitFirst1:
+		word = word[min(t.kind-omitFirst1+1, len(word)):]
+	case t.kind == uppercaseFirst:
+		toUpper(word)
+	case t.kind == uppercaseAll:
+		for i := 0; i < len(word); {
+			i += toUpper(word[i:])
+		}
+	}
+
+	out := make([]byte, 0, len(t.prefix)+len(word)+len(t.suffix))
+	out = append(out, t.prefix...)
+	out = append(out, word...)
+	return append(out, t.suffix...), true
+}
+
+// toUpper upper-cases the UTF-8 character at the start of p the way the
+// RFC does and returns its length in bytes.
+func toUpper(p []byte) int {
+	switch {
+	case p[0] < 0xc0:
+		if p[0] >= 'a' && p[0] <= 'z' {
+			p[0] ^= 32
+		}
+		return 1
+	case p[0] < 0xe0:
+		if len(p) > 1 {
+			p[1] ^= 32
+		}
+		return 2
+	default:
+		if len(p) > 2 {
+			p[2] ^= 5
+		}
+		return 3
+	}
+}
@@ -0,0 +1,238 @@
+package brotli
+
+import "io"
+
+// maxCodeLength is the longest prefix code Brotli allows.
+const maxCodeLength = 15
+
+// codeLengthOrder is the order in which code length code lengths are sent.
+var codeLengthOrder = [18]int{1, 2, 3, 4, 0, 5, 17, 6, 16, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// bitReader reads the least significant bits of each byte first.
+type bitReader struct {
+	r    io.ByteReader
+	bits uint64
+	n    uint
+}
+
+// readBits returns the next n (at most 32) bits.
+func (b *bitReader) readBits(n uint) int {
+	for b.n < n {
+		c, err := b.r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			panic(decodeError{err})
+		}
+		b.bits |= uint64(c) << b.n
+		b.n += 8
+	}
+	v := int(b.bits & (1<<n - 1))
+	b.bits >>= n
+	b.n -= n
+	return v
+}
+
+// align skips to the next byte boundary; the skipped bits must be zero.
+func (b *bitReader) align() {
+	if b.readBits(b.n%8) != 0 {
+		corrupt("non-zero padding")
+	}
+}
+
+// huffman is a canonical prefix code, decoded one bit at a time.
+type huffman struct {
+	count  [maxCodeLength + 1]int
+	symbol []int
+	single int // the only symbol of a zero-bit code, or -1
+}
+
+// newHuffman builds the canonical code for the given code lengths.
+func newHuffman(lengths []int) *huffman {
+	h := &huffman{single: -1}
+	used := 0
+	for sym, l := range lengths {
+		if l > 0 {
+			h.count[l]++
+			h.single = sym
+			used++
+		}
+	}
+	if used != 1 {
+		h.single = -1
+	}
+
+	var offsets [maxCodeLength + 2]int
+	for l := 1; l <= maxCodeLength; l++ {
+		offsets[l+1] = offsets[l] + h.count[l]
+	}
+	h.symbol = make([]int, used)
+	for sym, l := range lengths {
+		if l > 0 {
+			h.symbol[offsets[l]] = sym
+			offsets[l]++
+		}
+	}
+	return h
+}
+
+// decode reads one symbol.
+func (h *huffman) decode(br *bitReader) int {
+	if h.single >= 0 {
+		return h.single
+	}
+	code, first, index := 0, 0, 0
+	for l := 1; l <= maxCodeLength; l++ {
+		code |= br.readBits(1)
+		count := h.count[l]
+		if code-first < count {
+			return h.symbol[index+code-first]
+		}
+		index += count
+		first = (first + count) << 1
+		code <<= 1
+	}
+	corrupt("invalid prefix code")
+	return 0
+}
+
+// readHuffman reads a prefix code over an alphabet of the given size
+// (RFC 7932 section 3.4 and 3.5).
+func readHuffman(br *bitReader, alphabetSize int) *huffman {
+	lengths := make([]int, alphabetSize)
+	hskip := br.readBits(2)
+	if hskip == 1 {
+		readSimpleLengths(br, lengths)
+	} else {
+		readComplexLengths(br, lengths, hskip)
+	}
+	return newHuffman(lengths)
+}
+
+// readSimpleLengths reads a code of one to four explicitly listed symbols.
+func readSimpleLengths(br *bitReader, lengths []int) {
+	alphabetBits := uint(0)
+	for 1<<alphabetBits < len(lengths) {
+		alphabetBits++
+	}
+	nsym := br.readBits(2) + 1
+	symbols := make([]int, nsym)
+	for i := range symbols {
+		symbols[i] = br.readBits(alphabetBits)
+		if symbols[i] >= len(lengths) {
+			corrupt("simple code symbol out of range")
+		}
+		for _, prev := range symbols[:i] {
+			if prev == symbols[i] {
+				corrupt("duplicate simple code symbol")
+			}
+		}
+	}
+
+	var codeLengths []int
+	switch nsym {
+	case 1:
+		// Any non-zero length will do: newHuffman turns a code with a
+		// single symbol into one that reads no bits.
+		lengths[symbols[0]] = 1
+		return
+	case 2:
+		codeLengths = []int{1, 1}
+	case 3:
+		codeLengths = []int{1, 2, 2}
+	default:
+		if br.readBits(1) == 0 {
+			codeLengths = []int{2, 2, 2, 2}
+		} else {
+			codeLengths = []int{1, 2, 3, 3}
+		}
+	}
+	for i, sym := range symbols {
+		lengths[sym] = codeLengths[i]
+	}
+}
+
+// readComplexLengths reads code lengths that are themselves prefix coded.
+func readComplexLengths(br *bitReader, lengths []int, hskip int) {
+	var codeLengthLengths [18]int
+	space, codes := 32, 0
+	for i := hskip; i < len(codeLengthOrder); i++ {
+		l := readCodeLengthLength(br)
+		codeLengthLengths[codeLengthOrder[i]] = l
+		if l != 0 {
+			space -= 32 >> l
+			codes++
+			if space <= 0 {
+				break
+			}
+		}
+	}
+	if codes != 1 && space != 0 {
+		corrupt("invalid code length code")
+	}
+	lengthCode := newHuffman(codeLengthLengths[:])
+
+	prevLength, repeat, repeatLength := 8, 0, 0
+	space = 1 << 15
+	for sym := 0; sym < len(lengths) && space > 0; {
+		l := lengthCode.decode(br)
+		if l < 16 {
+			repeat = 0
+			lengths[sym] = l
+			sym++
+			if l != 0 {
+				prevLength = l
+				space -= (1 << 15) >> l
+			}
+			continue
+		}
+
+		extraBits, newLength := uint(2), prevLength
+		if l == 17 {
+			extraBits, newLength = 3, 0
+		}
+		if repeatLength != newLength {
+			repeat, repeatLength = 0, newLength
+		}
+		oldRepeat := repeat
+		if repeat > 0 {
+			repeat = (repeat - 2) << extraBits
+		}
+		repeat += br.readBits(extraBits) + 3
+		delta := repeat - oldRepeat
+		if sym+delta > len(lengths) {
+			corrupt("code length repeat out of range")
+		}
+		for i := 0; i < delta; i++ {
+			lengths[sym] = repeatLength
+			sym++
+		}
+		if repeatLength != 0 {
+			space -= delta << (15 - repeatLength)
+		}
+	}
+	if space != 0 {
+		corrupt("incomplete prefix code")
+	}
+}
+
+// readCodeLengthLength reads one code length code length with the fixed
+// code of RFC 7932 section 3.5.
+func readCodeLengthLength(br *bitReader) int {
+	switch br.readBits(2) {
+	case 0:
+		return 0
+	case 1:
+		return 4
+	case 2:
+		return 3
+	}
+	if br.readBits(1) == 0 {
+		return 2
+	}
+	if br.readBits(1) == 0 {
+		return 1
+	}
+	return 5
+}
@@ -0,0 +1,548 @@
+// Package brotli decodes the Brotli compressed data format (RFC 7932), the
+// "br" content coding many web servers prefer over gzip.
+package brotli
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+)
+
+// chunkSize is how much output is decoded ahead of the caller at most.
+const chunkSize = 32 << 10
+
+// decodeError carries a decoding failure up to Read.
+type decodeError struct {
+	err error
+}
+
+// corrupt aborts decoding with a format error.
+func corrupt(reason string) {
+	panic(decodeError{errors.New("brotli: corrupt input: " + reason)})
+}
+
+// Reader decompresses a Brotli stream.
+type Reader struct {
+	br  bitReader
+	err error
+
+	// window holds the most recent output. It starts small and doubles up
+	// to windowCap, so short bodies do not pay for a large window.
+	window    []byte
+	windowCap int
+	maxDist   int
+	pos       int  // bytes decoded
+	flushed   int  // bytes returned to the caller
+	started   bool // the stream header was read
+	last      bool // the current meta-block is the last one
+	remaining int  // bytes left in the current meta-block
+	raw       bool // the current meta-block is stored uncompressed
+	mb        *metaBlock
+	distances [4]int // ring of the last distances
+	distIndex int
+
+	// State of the current command.
+	insert   int
+	copyLen  int
+	copyDist int
+	needDist bool
+	implicit bool // the command reuses the last distance
+	word     []byte
+}
+
+// NewReader returns a Reader that decompresses r.
+func NewReader(r io.Reader) *Reader {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Reader{
+		br:        bitReader{r: br},
+		distances: [4]int{16, 15, 11, 4},
+		distIndex: 4,
+	}
+}
+
+// Read implements io.Reader.
+func (r *Reader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if r.pos == r.flushed && r.err == nil {
+		r.decode()
+	}
+	if r.pos == r.flushed {
+		return 0, r.err
+	}
+
+	n := 0
+	for n < len(p) && r.flushed < r.pos {
+		start := r.flushed & (len(r.window) - 1)
+		end := min(len(r.window), start+(r.pos-r.flushed))
+		c := copy(p[n:], r.window[start:end])
+		n += c
+		r.flushed += c
+	}
+	return n, nil
+}
+
+// decode produces the next chunk of output, or sets r.err.
+func (r *Reader) decode() {
+	defer func() {
+		if v := recover(); v != nil {
+			e, ok := v.(decodeError)
+			if !ok {
+				panic(v)
+			}
+			r.err = e.err
+		}
+	}()
+
+	if !r.started {
+		r.readWindowBits()
+		r.started = true
+	}
+	for r.err == nil && r.pos-r.flushed < min(chunkSize, r.windowCap) {
+		r.step()
+	}
+}
+
+// room returns how many bytes can be decoded before output the caller has
+// not read yet would be overwritten.
+func (r *Reader) room() int {
+	return r.windowCap - (r.pos - r.flushed)
+}
+
+// step decodes as much of the current command or meta-block as fits in
+// the window.
+func (r *Reader) step() {
+	switch {
+	case len(r.word) > 0:
+		n := min(len(r.word), r.room())
+		for _, c := range r.word[:n] {
+			r.put(c)
+		}
+		r.word = r.word[n:]
+	case r.insert > 0:
+		for n := min(r.insert, r.room()); n > 0; n-- {
+			r.put(r.literal())
+			r.insert--
+		}
+	case r.needDist:
+		r.readDistance()
+	case r.copyLen > 0:
+		for n := min(r.copyLen, r.room()); n > 0; n-- {
+			r.put(r.window[(r.pos-r.copyDist)&(len(r.window)-1)])
+			r.copyLen--
+		}
+	case r.remaining > 0 && r.raw:
+		for n := min(r.remaining, r.room()); n > 0; n-- {
+			r.put(byte(r.br.readBits(8)))
+		}
+	case r.remaining > 0:
+		r.readCommand()
+	case r.last:
+		r.err = io.EOF
+	default:
+		r.readHeader()
+	}
+}
+
+// put appends one byte to the output.
+func (r *Reader) put(c byte) {
+	if r.remaining == 0 {
+		corrupt("meta-block overflows its length")
+	}
+	if r.pos == len(r.window) && len(r.window) < r.windowCap {
+		grown := make([]byte, min(max(2*len(r.window), 1<<16), r.windowCap))
+		copy(grown, r.window)
+		r.window = grown
+	}
+	r.window[r.pos&(len(r.window)-1)] = c
+	r.pos++
+	r.remaining--
+}
+
+// prev returns the byte decoded n positions back, or zero before the start.
+func (r *Reader) prev(n int) byte {
+	if r.pos < n {
+		return 0
+	}
+	return r.window[(r.pos-n)&(len(r.window)-1)]
+}
+
+// readWindowBits reads the sliding window size from the stream header.
+func (r *Reader) readWindowBits() {
+	bits := 16
+	if r.br.readBits(1) == 1 {
+		if n := r.br.readBits(3); n != 0 {
+			bits = 17 + n
+		} else if n := r.br.readBits(3); n == 1 {
+			corrupt("large windows are not supported")
+		} else if n != 0 {
+			bits = 8 + n
+		} else {
+			bits = 17
+		}
+	}
+	r.windowCap = 1 << bits
+	r.maxDist = 1<<bits - 16
+}
+
+// readHeader reads the next meta-block header.
+func (r *Reader) readHeader() {
+	r.last = r.br.readBits(1) == 1
+	if r.last && r.br.readBits(1) == 1 {
+		return
+	}
+
+	nibbles := r.br.readBits(2) + 4
+	if nibbles == 7 {
+		r.skipMetadata()
+		return
+	}
+	length := 0
+	for i := 0; i < nibbles; i++ {
+		n := r.br.readBits(4)
+		if i == nibbles-1 && i > 3 && n == 0 {
+			corrupt("meta-block length has a leading zero nibble")
+		}
+		length |= n << (4 * i)
+	}
+	r.remaining = length + 1
+
+	r.raw = !r.last && r.br.readBits(1) == 1
+	if r.raw {
+		r.br.align()
+		return
+	}
+	r.mb = readMetaBlock(&r.br)
+}
+
+// skipMetadata skips a metadata meta-block, which produces no output.
+func (r *Reader) skipMetadata() {
+	if r.br.readBits(1) != 0 {
+		corrupt("reserved bit is set")
+	}
+	skipBytes := r.br.readBits(2)
+	skip := 0
+	for i := 0; i < skipBytes; i++ {
+		b := r.br.readBits(8)
+		if i == skipBytes-1 && i > 0 && b == 0 {
+			corrupt("metadata length has a leading zero byte")
+		}
+		skip |= b << (8 * i)
+	}
+	if skipBytes > 0 {
+		skip++
+	}
+	r.br.align()
+	for ; skip > 0; skip-- {
+		r.br.readBits(8)
+	}
+}
+
+// readCommand reads the next insert-and-copy command.
+func (r *Reader) readCommand() {
+	m := r.mb
+	commands := &m.blocks[blockCommand]
+	commands.next(&r.br)
+	sym := m.commands[commands.blockType].decode(&r.br)
+
+	cell := sym >> 6
+	insertCode := insertCellBase[cell] + sym>>3&7
+	copyCode := copyCellBase[cell] + sym&7
+	r.insert = insertLengths[insertCode].base + r.br.readBits(insertLengths[insertCode].extra)
+	r.copyLen = copyLengths[copyCode].base + r.br.readBits(copyLengths[copyCode].extra)
+	r.implicit = cell < 2
+	r.needDist = true
+	if r.insert > r.remaining {
+		corrupt("insert overflows the meta-block")
+	}
+}
+
+// literal decodes the next literal of an insert.
+func (r *Reader) literal() byte {
+	m := r.mb
+	literals := &m.blocks[blockLiteral]
+	literals.next(&r.br)
+	context := literalContext(m.contextModes[literals.blockType], r.prev(1), r.prev(2))
+	return byte(m.literals[m.literalMap[literals.blockType<<6|context]].decode(&r.br))
+}
+
+// readDistance resolves the distance of the current command into a
+// backward copy or a dictionary word.
+func (r *Reader) readDistance() {
+	r.needDist = false
+	if r.remaining == 0 {
+		// The meta-block ended with the insert; the copy is ignored.
+		r.copyLen = 0
+		return
+	}
+
+	code := 0
+	if !r.implicit {
+		m := r.mb
+		distances := &m.blocks[blockDistance]
+		distances.next(&r.br)
+		context := min(r.copyLen-2, 3)
+		code = m.distances[m.distanceMap[distances.blockType<<2|context]].decode(&r.br)
+	}
+	dist := r.distance(code)
+
+	if maxDist := min(r.maxDist, r.pos); dist > maxDist {
+		word, ok := dictionaryWord(r.copyLen, dist-maxDist-1)
+		if !ok {
+			corrupt("invalid dictionary reference")
+		}
+		if len(word) > r.remaining {
+			corrupt("dictionary word overflows the meta-block")
+		}
+		r.word = word
+		r.copyLen = 0
+		return
+	}
+
+	if code != 0 {
+		r.distances[r.distIndex&3] = dist
+		r.distIndex++
+	}
+	if r.copyLen > r.remaining {
+		corrupt("copy overflows the meta-block")
+	}
+	r.copyDist = dist
+}
+
+// distance turns a distance code into a distance (RFC 7932 section 4).
+func (r *Reader) distance(code int) int {
+	m := r.mb
+	last := func(n int) int { return r.distances[(r.distIndex-n)&3] }
+
+	var dist int
+	switch {
+	case code < 4:
+		dist = last(code + 1)
+	case code < 10:
+		dist = last(1) + shortDistanceDelta[code-4]
+	case code < 16:
+		dist = last(2) + shortDistanceDelta[code-10]
+	case code < 16+m.direct:
+		dist = code - 15
+	default:
+		x := code - m.direct - 16
+		bits := uint(1 + x>>(m.postfix+1))
+		high := x >> m.postfix
+		low := x & (1<<m.postfix - 1)
+		offset := (2+high&1)<<bits - 4
+		dist = (offset+r.br.readBits(bits))<<m.postfix + low + m.direct + 1
+	}
+	if dist <= 0 {
+		corrupt("invalid distance")
+	}
+	return dist
+}
+
+// shortDistanceDelta adjusts the last distances for codes 4-9 and 10-15.
+var shortDistanceDelta = [6]int{-1, 1, -2, 2, -3, 3}
+
+// Block categories.
+const (
+	blockLiteral = iota
+	blockCommand
+	blockDistance
+)
+
+// blockSwitch tracks the block type and remaining block length of one
+// category of symbols.
+type blockSwitch struct {
+	types      int
+	typeCode   *huffman
+	countCode  *huffman
+	blockType  int
+	count      int
+	last       int
+	secondLast int
+}
+
+// readBlockSwitch reads the block type header of one category.
+func readBlockSwitch(br *bitReader) blockSwitch {
+	b := blockSwitch{types: readVarLenUint8(br) + 1, count: math.MaxInt, secondLast: 1}
+	if b.types >= 2 {
+		b.typeCode = readHuffman(br, b.types+2)
+		b.countCode = readHuffman(br, 26)
+		b.count = readBlockCount(br, b.countCode)
+	}
+	return b
+}
+
+// next accounts for one symbol, switching blocks when the current one is
+// used up.
+func (b *blockSwitch) next(br *bitReader) {
+	if b.count == 0 {
+		var t int
+		switch sym := b.typeCode.decode(br); sym {
+		case 0:
+			t = b.secondLast
+		case 1:
+			t = b.last + 1
+		default:
+			t = sym - 2
+		}
+		if t >= b.types {
+			t -= b.types
+		}
+		b.secondLast, b.last, b.blockType = b.last, t, t
+		b.count = readBlockCount(br, b.countCode)
+	}
+	b.count--
+}
+
+func readBlockCount(br *bitReader, code *huffman) int {
+	c := blockCounts[code.decode(br)]
+	return c.base + br.readBits(c.extra)
+}
+
+// readVarLenUint8 reads a value from 0 to 255 (RFC 7932 section 9.2).
+func readVarLenUint8(br *bitReader) int {
+	if br.readBits(1) == 0 {
+		return 0
+	}
+	n := uint(br.readBits(3))
+	if n == 0 {
+		return 1
+	}
+	return 1<<n + br.readBits(n)
+}
+
+// metaBlock holds the prefix codes and context maps of a compressed
+// meta-block.
+type metaBlock struct {
+	blocks       [3]blockSwitch
+	postfix      uint
+	direct       int
+	contextModes []int
+	literalMap   []int
+	distanceMap  []int
+	literals     []*huffman
+	commands     []*huffman
+	distances    []*huffman
+}
+
+// readMetaBlock reads the header of a compressed meta-block.
+func readMetaBlock(br *bitReader) *metaBlock {
+	m := &metaBlock{}
+	for i := range m.blocks {
+		m.blocks[i] = readBlockSwitch(br)
+	}
+	m.postfix = uint(br.readBits(2))
+	m.direct = br.readBits(4) << m.postfix
+
+	m.contextModes = make([]int, m.blocks[blockLiteral].types)
+	for i := range m.contextModes {
+		m.contextModes[i] = br.readBits(2)
+	}
+	literalTrees, literalMap := readContextMap(br, 64*m.blocks[blockLiteral].types)
+	distanceTrees, distanceMap := readContextMap(br, 4*m.blocks[blockDistance].types)
+	m.literalMap, m.distanceMap = literalMap, distanceMap
+
+	m.literals = readHuffmans(br, literalTrees, 256)
+	m.commands = readHuffmans(br, m.blocks[blockCommand].types, 704)
+	m.distances = readHuffmans(br, distanceTrees, 16+m.direct+48<<m.postfix)
+	return m
+}
+
+func readHuffmans(br *bitReader, n, alphabetSize int) []*huffman {
+	codes := make([]*huffman, n)
+	for i := range codes {
+		codes[i] = readHuffman(br, alphabetSize)
+	}
+	return codes
+}
+
+// readContextMap reads the number of prefix codes and the map from
+// contexts to them (RFC 7932 section 7.3).
+func readContextMap(br *bitReader, size int) (int, []int) {
+	trees := readVarLenUint8(br) + 1
+	contextMap := make([]int, size)
+	if trees < 2 {
+		return trees, contextMap
+	}
+
+	maxRunLength := 0
+	if br.readBits(1) == 1 {
+		maxRunLength = br.readBits(4) + 1
+	}
+	code := readHuffman(br, trees+maxRunLength)
+	for i := 0; i < size; {
+		sym := code.decode(br)
+		switch {
+		case sym == 0:
+			i++
+		case sym <= maxRunLength:
+			run := 1<<sym + br.readBits(uint(sym))
+			if i+run > size {
+				corrupt("context map run out of range")
+			}
+			i += run
+		default:
+			contextMap[i] = sym - maxRunLength
+			i++
+		}
+	}
+
+	if br.readBits(1) == 1 {
+		inverseMoveToFront(contextMap)
+	}
+	for _, tree := range contextMap {
+		if tree >= trees {
+			corrupt("context map refers to a missing prefix code")
+		}
+	}
+	return trees, contextMap
+}
+
+func inverseMoveToFront(values []int) {
+	var mtf [256]int
+	for i := range mtf {
+		mtf[i] = i
+	}
+	for i, index := range values {
+		v := mtf[index]
+		values[i] = v
+		copy(mtf[1:index+1], mtf[:index])
+		mtf[0] = v
+	}
+}
+
+// lengthCode is the base value and extra bit count of a length symbol.
+type lengthCode struct {
+	base  int
+	extra uint
+}
+
+// insertCellBase and copyCellBase give the first insert and copy length
+// codes of each 64-symbol cell of the insert-and-copy alphabet.
+var (
+	insertCellBase = [11]int{0, 0, 0, 0, 8, 8, 0, 16, 8, 16, 16}
+	copyCellBase   = [11]int{0, 8, 0, 8, 0, 8, 16, 0, 16, 8, 16}
+)
+
+var insertLengths = [24]lengthCode{
+	{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}, {6, 1}, {8, 1},
+	{10, 2}, {14, 2}, {18, 3}, {26, 3}, {34, 4}, {50, 4}, {66, 5}, {98, 5},
+	{130, 6}, {194, 7}, {322, 8}, {578, 9}, {1090, 10}, {2114, 12}, {6210, 14}, {22594, 24},
+}
+
+var copyLengths = [24]lengthCode{
+	{2, 0}, {3, 0}, {4, 0}, {5, 0}, {6, 0}, {7, 0}, {8, 0}, {9, 0},
+	{10, 1}, {12, 1}, {14, 2}, {18, 2}, {22, 3}, {30, 3}, {38, 4}, {54, 4},
+	{70, 5}, {102, 5}, {134, 6}, {198, 7}, {326, 8}, {582, 9}, {1094, 10}, {2118, 24},
+}
+
+var blockCounts = [26]lengthCode{
+	{1, 2}, {5, 2}, {9, 2}, {13, 2}, {17, 3}, {25, 3}, {33, 3}, {41, 3},
+	{49, 4}, {65, 4}, {81, 4}, {97, 4}, {113, 5}, {145, 5}, {177, 5}, {209, 5},
+	{241, 6}, {305, 6}, {369, 7}, {497, 8}, {753, 9}, {1265, 10}, {2289, 11}, {4337, 12},
+	{8433, 13}, {16625, 24},
+}
@@ -0,0 +1,133 @@
+package brotli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// The fixtures in testdata were compressed with the reference encoder at
+// the quality and window size in their name.
+func TestReader(t *testing.T) {
+	page, err := os.ReadFile("testdata/page.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	random, err := os.ReadFile("testdata/random.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file string
+		want []byte
+	}{
+		{"page.q0.w10.br", page},
+		{"page.q1.w16.br", page},
+		{"page.q5.w18.br", page},
+		{"page.q9.w22.br", page},
+		{"page.q11.w10.br", page},
+		{"page.q11.w22.br", page},
+		{"random.br", random},
+		{"empty.br", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			compressed, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := io.ReadAll(NewReader(bytes.NewReader(compressed)))
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("decoded %d bytes, want %d bytes of the original", len(got), len(tt.want))
+			}
+
+			r := NewReader(iotest.OneByteReader(bytes.NewReader(compressed)))
+			if err := iotest.TestReader(r, tt.want); err != nil {
+				t.Errorf("TestReader() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	compressed, err := os.ReadFile("testdata/page.q11.w22.br")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+	}{
+		{"empty input", nil, io.ErrUnexpectedEOF.Error()},
+		{"truncated", compressed[:len(compressed)/2], io.ErrUnexpectedEOF.Error()},
+		// WBITS 0b0010001 selects a large window, which HTTP never uses.
+		{"large window", []byte{0x11, 0x00}, "large windows"},
+		// A last meta-block with a five-nibble length of zero.
+		{"non-minimal length", []byte{0x0a, 0x00, 0x00, 0x00}, "leading zero nibble"},
+		// An uncompressed meta-block of one byte followed by padding bits.
+		{"non-zero padding", []byte{0x00, 0x00, 0x90}, "non-zero padding"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := io.ReadAll(NewReader(bytes.NewReader(tt.input)))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ReadAll() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDictionary(t *testing.T) {
+	// SHA-256 of the dictionary as given in RFC 7932 Appendix A.
+	const want = "20e42eb1b511c21806d4d227d07e5dd06877d8ce7b3a817f378f313653f35c70"
+	sum := sha256.Sum256([]byte(dictionary))
+	if got := hex.EncodeToString(sum[:]); got != want {
+		t.Errorf("dictionary SHA-256 = %s, want %s", got, want)
+	}
+	if len(transforms) != 121 {
+		t.Errorf("len(transforms) = %d, want 121", len(transforms))
+	}
+}
+
+func TestDictionaryWord(t *testing.T) {
+	tests := []struct {
+		length int
+		wordID int
+		want   string
+		ok     bool
+	}{
+		{4, 0, "time", true},
+		{4, 1<<10 | 1, "down ", true},  // suffix
+		{4, 2 << 10, " time ", true},   // prefix and suffix
+		{4, 3 << 10, "ime", true},      // omit first 1
+		{4, 4 << 10, "Time ", true},    // uppercase first
+		{4, 44 << 10, "TIME", true},    // uppercase all
+		{4, 48 << 10, "", true},        // omit last 7 of a 4-byte word
+		{4, 49 << 10, "timing ", true}, // omit last 1
+		{3, 0, "", false},
+		{25, 0, "", false},
+		{4, 121 << 10, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := dictionaryWord(tt.length, tt.wordID)
+		if ok != tt.ok || string(got) != tt.want {
+			t.Errorf("dictionaryWord(%d, %d) = %q, %v; want %q, %v", tt.length, tt.wordID, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -4,66 +4,81 @@ import "time"
 
 const (
 	// Default timeout values.
-	defaultHTTPTimeout      = 45 * time.Second
-	defaultShortTimeout     = 15 * time.Second
-	defaultDNSTimeout       = 10 * time.Second
-	defaultTLSTimeout       = 5 * time.Second
-	defaultRetryDelay       = 2 * time.Second
-	
+	defaultHTTPTimeout  = 45 * time.Second
+	defaultShortTimeout = 15 * time.Second
+	defaultDNSTimeout   = 10 * time.Second
+	defaultTLSTimeout   = 5 * time.Second
+	defaultRetryDelay   = 2 * time.Second
+
+	// Default response body limit (10 MiB).
+	defaultMaxBodySize = 10 << 20
+
 	// Default retry settings.
 	defaultMaxRetries = 3
-	
-	// Default CMS detection thresholds.
-	defaultMinCMSIndicators       = 2
-	defaultMinCMSIndicatorsNoMeta = 3
-	
+
 	// Display limits.
 	defaultMaxSubdomainsDisplay = 50
-	
+
 	// Default User-Agent string.
 	defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) " +
 		"AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
-	
+
 	// Default DNS servers.
 	googleDNS1 = "8.8.8.8:53"
 	googleDNS2 = "8.8.4.4:53"
 )
 
-// Config holds application configuration.
+// Config holds application configuration. The config tags name each
+// setting in configuration files ("section.key") and environment variables
+// (RANKLE_SECTION_KEY); the flag and help tags define its command-line
+// flag. A "negate" flag sets the opposite of the setting.
 type Config struct {
-	HTTP    HTTPConfig
-	DNS     DNSConfig
-	TLS     TLSConfig
-	Scanner ScannerConfig
+	HTTP    HTTPConfig    `config:"http"`
+	DNS     DNSConfig     `config:"dns"`
+	TLS     TLSConfig     `config:"tls"`
+	Scanner ScannerConfig `config:"scanner"`
 }
 
 // HTTPConfig contains HTTP client configuration.
 type HTTPConfig struct {
-	Timeout        time.Duration
-	ShortTimeout   time.Duration
-	MaxRetries     int
-	RetryDelay     time.Duration
-	UserAgent      string
-	FollowRedirect bool
+	Timeout        time.Duration `config:"timeout" flag:"http-timeout" help:"Timeout for the main page request"`
+	ShortTimeout   time.Duration `config:"short_timeout" flag:"http-short-timeout" help:"Timeout for probe and asset requests"`
+	MaxRetries     int           `config:"max_retries" flag:"http-max-retries" help:"Retries for failed requests"`
+	RetryDelay     time.Duration `config:"retry_delay" flag:"http-retry-delay" help:"Delay between retries"`
+	UserAgent      string        `config:"user_agent" flag:"user-agent" help:"User-Agent header sent with every request"`
+	FollowRedirect bool          `config:"follow_redirect" flag:"no-follow-redirects,negate" help:"Do not follow HTTP redirects"`
+	MaxBodySize    int64         `config:"max_body_size" flag:"max-body-size" help:"Maximum response body size (e.g. 10MiB)"`
 }
 
 // DNSConfig contains DNS resolver configuration.
 type DNSConfig struct {
-	Timeout     time.Duration
-	Nameservers []string
+	Timeout     time.Duration `config:"timeout" flag:"dns-timeout" help:"Timeout for DNS queries"`
+	Nameservers []string      `config:"nameservers" flag:"nameserver" help:"DNS server host[:port] (repeatable)"`
 }
 
 // TLSConfig contains TLS connection configuration.
 type TLSConfig struct {
-	Timeout            time.Duration
-	InsecureSkipVerify bool
+	Timeout            time.Duration `config:"timeout" flag:"tls-timeout" help:"Timeout for TLS handshakes"`
+	InsecureSkipVerify bool          `config:"insecure_skip_verify" flag:"verify-tls,negate" help:"Verify TLS certificates during analysis"`
 }
 
 // ScannerConfig contains scanner-specific settings.
 type ScannerConfig struct {
-	MinCMSIndicators       int
-	MinCMSIndicatorsNoMeta int
-	MaxSubdomainsDisplay   int
+	Modules              []string `config:"modules" flag:"modules" help:"Modules to run instead of the default set"`
+	SkipModules          []string `config:"skip" flag:"skip" help:"Modules to leave out of the scan"`
+	FailOn               []string `config:"fail_on" flag:"fail-on" help:"Exit 3 when these modules fail, or \"any\""`
+	MaxSubdomainsDisplay int      `config:"max_subdomains_display" flag:"max-subdomains" help:"Maximum subdomains kept in the report"`
+	FingerprintFiles     []string `config:"fingerprint_files" flag:"fingerprints" help:"Extra Wappalyzer fingerprint files/directories"`
+	VulnerabilityFeeds   []string `config:"vulnerability_feeds" flag:"vuln-feeds" help:"Offline NVD/OSV/retire.js vulnerability feeds"`
+	WellKnown            bool     `config:"well_known" flag:"well-known" help:"Probe robots.txt, sitemap.xml, security.txt"`
+	SensitiveFiles       bool     `config:"sensitive_files" flag:"sensitive-files" help:"Check for exposed .git, .env and admin panels"`
+	SensitivePathFiles   []string `config:"sensitive_path_files" flag:"sensitive-paths" help:"Extra sensitive path rules (JSON)"`
+	QUICProbe            bool     `config:"quic_probe" flag:"quic" help:"Confirm HTTP/3 with a QUIC probe (UDP)"`
+	HTTPSecurity         bool     `config:"http_security" flag:"http-security" help:"Test HTTP methods and CORS misconfigurations"`
+	WAFProbe             bool     `config:"waf_probe" flag:"waf-probe" help:"Identify the WAF with attack-looking requests"`
+	OriginCheck          bool     `config:"origin_check" flag:"origin" help:"Look for origin servers that bypass the CDN"`
+	HistoricalIPs        []string `config:"historical_ips" flag:"origin-ips" help:"Historical A record IPs to test with --origin"`
+	ScriptAnalysis       bool     `config:"script_analysis" flag:"js" help:"Search same-origin scripts for endpoints, keys"`
 }
 
 // Default returns a configuration with sensible defaults.
@@ -76,6 +91,7 @@ func Default() *Config {
 			RetryDelay:     defaultRetryDelay,
 			UserAgent:      defaultUserAgent,
 			FollowRedirect: true,
+			MaxBodySize:    defaultMaxBodySize,
 		},
 		DNS: DNSConfig{
 			Timeout: defaultDNSTimeout,
@@ -89,9 +105,7 @@ func Default() *Config {
 			InsecureSkipVerify: true,
 		},
 		Scanner: ScannerConfig{
-			MinCMSIndicators:       defaultMinCMSIndicators,
-			MinCMSIndicatorsNoMeta: defaultMinCMSIndicatorsNoMeta,
-			MaxSubdomainsDisplay:   defaultMaxSubdomainsDisplay,
+			MaxSubdomainsDisplay: defaultMaxSubdomainsDisplay,
 		},
 	}
 }
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field is one setting of a Config, addressed by its "section.key" name.
+// Flag is its command-line flag; when Negate is set the flag turns the
+// setting off.
+type Field struct {
+	Key    string
+	Flag   string
+	Negate bool
+	Help   string
+	value  reflect.Value
+}
+
+// Fields lists the settings of c in declaration order. Setting a field
+// changes c.
+func (c *Config) Fields() []Field {
+	var fields []Field
+	root := reflect.ValueOf(c).Elem()
+	for i := 0; i < root.NumField(); i++ {
+		section := root.Type().Field(i).Tag.Get("config")
+		sv := root.Field(i)
+		for j := 0; j < sv.NumField(); j++ {
+			tag := sv.Type().Field(j).Tag
+			name, opts, _ := strings.Cut(tag.Get("flag"), ",")
+			fields = append(fields, Field{
+				Key:    section + "." + tag.Get("config"),
+				Flag:   name,
+				Negate: opts == "negate",
+				Help:   tag.Get("help"),
+				value:  sv.Field(j),
+			})
+		}
+	}
+	return fields
+}
+
+// Field returns the setting named key.
+func (c *Config) Field(key string) (Field, bool) {
+	for _, f := range c.Fields() {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// EnvName returns the environment variable that overrides f.
+func (f Field) EnvName() string {
+	return "RANKLE_" + strings.ToUpper(strings.ReplaceAll(f.Key, ".", "_"))
+}
+
+// IsList reports whether f holds a list of strings.
+func (f Field) IsList() bool {
+	return f.value.Kind() == reflect.Slice
+}
+
+// IsBool reports whether f is a switch.
+func (f Field) IsBool() bool {
+	return f.value.Kind() == reflect.Bool
+}
+
+// Value returns the field's current value.
+func (f Field) Value() interface{} {
+	return f.value.Interface()
+}
+
+// Set parses values into f. Lists take every value; other settings take
+// exactly one. Durations use Go syntax ("30s", "1m30s") and integers accept
+// a KB, MB, GB, KiB, MiB or GiB suffix.
+func (f Field) Set(values ...string) error {
+	if f.IsList() {
+		list := make([]string, 0, len(values))
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+		f.value.Set(reflect.ValueOf(list))
+		return nil
+	}
+	if len(values) != 1 {
+		return fmt.Errorf("%s takes a single value", f.Key)
+	}
+	raw := strings.TrimSpace(values[0])
+
+	if f.value.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%s: invalid duration %q (use a unit, e.g. 30s or 1m)", f.Key, raw)
+		}
+		f.value.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(raw)
+	case reflect.Bool:
+		b, err := parseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s: invalid boolean %q (use true or false)", f.Key, raw)
+		}
+		f.value.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := parseSize(raw)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number %q", f.Key, raw)
+		}
+		f.value.SetInt(n)
+	default:
+		return fmt.Errorf("%s: unsupported setting type %s", f.Key, f.value.Type())
+	}
+	return nil
+}
+
+// String formats the value the way Set parses it; lists are comma-separated.
+func (f Field) String() string {
+	switch v := f.value.Interface().(type) {
+	case time.Duration:
+		return v.String()
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseBool accepts Go booleans plus the YAML yes/no and on/off forms.
+func parseBool(raw string) (bool, error) {
+	switch strings.ToLower(raw) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(raw)
+}
+
+// sizeUnits are the suffixes accepted on integers, longest first.
+var sizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+	{"KB", 1000}, {"MB", 1000 * 1000}, {"GB", 1000 * 1000 * 1000},
+}
+
+// parseSize parses an integer with an optional size suffix.
+func parseSize(raw string) (int64, error) {
+	factor := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(raw, u.suffix) {
+			raw, factor = strings.TrimSpace(strings.TrimSuffix(raw, u.suffix)), u.factor
+			break
+		}
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * factor, nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Flags holds the settings given on the command line. Flags are parsed
+// before the configuration file is known, so values are recorded and
+// applied by Apply on top of the file and environment.
+type Flags struct {
+	overrides []override
+}
+
+// override is one flag occurrence.
+type override struct {
+	field Field
+	value string
+}
+
+// RegisterFlags defines a flag on fs for every setting that has one. List
+// flags may be repeated and take comma-separated values; the first
+// occurrence replaces the configured list.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	flags := &Flags{}
+	flags.Register(fs)
+	return flags
+}
+
+// Register defines the settings flags on another flag set, e.g. a
+// subcommand's. Values given on either set are recorded together.
+func (f *Flags) Register(fs *flag.FlagSet) {
+	for _, field := range Default().Fields() {
+		if field.Flag == "" {
+			continue
+		}
+		fs.Var(&flagValue{flags: f, field: field}, field.Flag, field.Help)
+	}
+}
+
+// Apply writes the recorded flag values into c, in command-line order.
+func (f *Flags) Apply(c *Config) error {
+	var errs []error
+	replaced := make(map[string]bool)
+	for _, o := range f.overrides {
+		field, ok := c.Field(o.field.Key)
+		if !ok {
+			continue
+		}
+		var err error
+		switch {
+		case field.IsList():
+			values := strings.Split(o.value, ",")
+			if replaced[field.Key] {
+				values = append(field.Value().([]string), values...)
+			}
+			replaced[field.Key] = true
+			err = field.Set(values...)
+		case field.IsBool() && field.Negate:
+			var b bool
+			if b, err = strconv.ParseBool(o.value); err == nil {
+				err = field.Set(strconv.FormatBool(!b))
+			}
+		default:
+			err = field.Set(o.value)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("--%s: %w", field.Flag, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flagValue records occurrences of one setting's flag.
+type flagValue struct {
+	flags *Flags
+	field Field
+}
+
+// String returns the default shown in flag usage: the built-in value, or
+// nothing for switches and empty settings.
+func (v *flagValue) String() string {
+	if v == nil || v.flags == nil || v.field.IsBool() {
+		return ""
+	}
+	return v.field.String()
+}
+
+// Set records one occurrence.
+func (v *flagValue) Set(value string) error {
+	if v.field.IsBool() {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return err
+		}
+	}
+	v.flags.overrides = append(v.flags.overrides, override{field: v.field, value: value})
+	return nil
+}
+
+// IsBoolFlag lets switches be given without a value.
+func (v *flagValue) IsBoolFlag() bool {
+	return v.field.IsBool()
+}
+
+// ArgName describes the value a setting's flag takes, for usage text. It
+// is empty for switches.
+func (f Field) ArgName() string {
+	switch f.Value().(type) {
+	case bool:
+		return ""
+	case time.Duration:
+		return "DURATION"
+	case int64:
+		return "SIZE"
+	case int:
+		return "N"
+	case []string:
+		return "LIST"
+	default:
+		return "TEXT"
+	}
+}
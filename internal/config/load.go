@@ -0,0 +1,219 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultDNSPort is added to name servers given without a port.
+const defaultDNSPort = "53"
+
+// Load returns the default configuration overlaid with the file at path,
+// when path is not empty, and then with RANKLE_* environment variables.
+// The file format follows the extension: .toml is TOML, anything else
+// YAML. The result is validated.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := cfg.LoadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// DefaultPath returns the configuration file looked for when none is given:
+// $XDG_CONFIG_HOME/rankle/config.yaml, or config.toml if only that exists.
+// XDG_CONFIG_HOME defaults to ~/.config. It returns "" if neither exists.
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml"} {
+		path := filepath.Join(dir, "rankle", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadFile applies the settings in a YAML or TOML file to c. Unknown keys
+// and malformed values are reported with their line number.
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var entries []entry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		entries, err = parseTOML(string(data))
+	default:
+		entries, err = parseYAML(string(data))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	var errs []error
+	for _, e := range entries {
+		field, ok := c.Field(e.key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s:%d: unknown setting %q", path, e.line, e.key))
+			continue
+		}
+		if err := field.Set(e.values...); err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", path, e.line, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ApplyEnv applies RANKLE_SECTION_KEY variables found by lookup, e.g.
+// RANKLE_DNS_NAMESERVERS=1.1.1.1,9.9.9.9. Lists are comma-separated.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, field := range c.Fields() {
+		value, ok := lookup(field.EnvName())
+		if !ok {
+			continue
+		}
+		values := []string{value}
+		if field.IsList() {
+			values = strings.Split(value, ",")
+		}
+		if err := field.Set(values...); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field.EnvName(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks that settings are usable and fills in the default DNS
+// port on name servers given without one. All problems are reported
+// together.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	for key, d := range map[string]int64{
+		"http.timeout":       int64(c.HTTP.Timeout),
+		"http.short_timeout": int64(c.HTTP.ShortTimeout),
+		"dns.timeout":        int64(c.DNS.Timeout),
+		"tls.timeout":        int64(c.TLS.Timeout),
+	} {
+		if d <= 0 {
+			fail(key, "must be positive")
+		}
+	}
+	if c.HTTP.RetryDelay < 0 {
+		fail("http.retry_delay", "must not be negative")
+	}
+	if c.HTTP.MaxRetries < 0 {
+		fail("http.max_retries", "must not be negative")
+	}
+	if strings.TrimSpace(c.HTTP.UserAgent) == "" {
+		fail("http.user_agent", "must not be empty")
+	}
+	if c.HTTP.MaxBodySize <= 0 {
+		fail("http.max_body_size", "must be positive")
+	}
+
+	if len(c.DNS.Nameservers) == 0 {
+		fail("dns.nameservers", "at least one name server is required")
+	}
+	for i, ns := range c.DNS.Nameservers {
+		host, port, err := net.SplitHostPort(ns)
+		if err != nil {
+			host, port = strings.Trim(ns, "[]"), defaultDNSPort
+		}
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			fail("dns.nameservers", "%q has an invalid port", ns)
+			continue
+		}
+		if host == "" {
+			fail("dns.nameservers", "%q has no host", ns)
+			continue
+		}
+		c.DNS.Nameservers[i] = net.JoinHostPort(host, port)
+	}
+
+	for key, n := range map[string]int{
+		"scanner.max_subdomains_display": c.Scanner.MaxSubdomainsDisplay,
+	} {
+		if n < 0 {
+			fail(key, "must not be negative")
+		}
+	}
+	for _, ip := range c.Scanner.HistoricalIPs {
+		if net.ParseIP(strings.TrimSpace(ip)) == nil {
+			fail("scanner.historical_ips", "%q is not an IP address", ip)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	// Map iteration above is unordered; keep the report stable.
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	sort.Strings(msgs)
+	return errors.New("invalid configuration:\n  " + strings.Join(msgs, "\n  "))
+}
+
+// WriteYAML writes every setting of c in the YAML format LoadFile reads.
+func (c *Config) WriteYAML(w io.Writer) error {
+	var sb strings.Builder
+	section := ""
+	for _, field := range c.Fields() {
+		sec, key, _ := strings.Cut(field.Key, ".")
+		if sec != section {
+			if section != "" {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(sec + ":\n")
+			section = sec
+		}
+		switch v := field.Value().(type) {
+		case []string:
+			if len(v) == 0 {
+				sb.WriteString(fmt.Sprintf("  %s: []\n", key))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("  %s:\n", key))
+			for _, item := range v {
+				sb.WriteString(fmt.Sprintf("    - %s\n", strconv.Quote(item)))
+			}
+		case string:
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", key, strconv.Quote(v)))
+		default:
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", key, field.String()))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,312 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// entry is one setting read from a configuration file.
+type entry struct {
+	key    string
+	values []string
+	line   int
+}
+
+// parseYAML reads the YAML subset configuration files use: nested
+// mappings by indentation, scalars (plain, single- or double-quoted), flow
+// lists ([a, b]), block lists ("- a") and # comments. Anchors, multi-line
+// strings and multiple documents are not supported.
+func parseYAML(data string) ([]entry, error) {
+	type level struct {
+		indent int
+		key    string
+	}
+	var (
+		entries []entry
+		stack   []level
+		list    *entry // block list being filled
+		listAt  int
+	)
+
+	for n, raw := range strings.Split(data, "\n") {
+		line := n + 1
+		text := strings.TrimRight(stripComment(raw), " \t\r")
+		if strings.TrimSpace(text) == "" || text == "---" {
+			continue
+		}
+		body := strings.TrimLeft(text, " ")
+		indent := len(text) - len(body)
+		if strings.HasPrefix(body, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", line)
+		}
+
+		if strings.HasPrefix(body, "- ") || body == "-" {
+			if list == nil || indent < listAt {
+				return nil, fmt.Errorf("line %d: list item without a key", line)
+			}
+			value, err := yamlScalar(strings.TrimSpace(strings.TrimPrefix(body, "-")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			list.values = append(list.values, value)
+			continue
+		}
+		list = nil
+
+		colon := mappingColon(body)
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line)
+		}
+		key := strings.TrimSpace(body[:colon])
+		if unquoted, err := yamlScalar(key); err == nil {
+			key = unquoted
+		}
+		value := strings.TrimSpace(body[colon+1:])
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		full := key
+		if len(stack) > 0 {
+			full = stack[len(stack)-1].key + "." + key
+		}
+
+		switch {
+		case value == "":
+			// A nested mapping or a block list follows.
+			stack = append(stack, level{indent: indent, key: full})
+			entries = append(entries, entry{key: full, line: line})
+			list, listAt = &entries[len(entries)-1], indent
+		case strings.HasPrefix(value, "["):
+			values, err := flowList(value, yamlScalar)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			entries = append(entries, entry{key: full, values: values, line: line})
+		default:
+			scalar, err := yamlScalar(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			entries = append(entries, entry{key: full, values: []string{scalar}, line: line})
+		}
+	}
+
+	// Keys that only opened a mapping are not settings.
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.values != nil || !hasChildren(entries, e.key) {
+			kept = append(kept, e)
+		}
+	}
+	return kept, nil
+}
+
+// parseTOML reads the TOML subset configuration files use: [table]
+// headers, key = value pairs with dotted keys, basic and literal strings,
+// booleans, integers and arrays (which may span lines), and # comments.
+func parseTOML(data string) ([]entry, error) {
+	var (
+		entries []entry
+		table   string
+		pending *entry // array continued on the next lines
+		buf     string
+	)
+
+	for n, raw := range strings.Split(data, "\n") {
+		line := n + 1
+		text := strings.TrimSpace(stripComment(raw))
+		if pending != nil {
+			buf += " " + text
+			if !strings.HasSuffix(buf, "]") {
+				continue
+			}
+			values, err := flowList(buf, tomlScalar)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", pending.line, err)
+			}
+			pending.values = values
+			entries = append(entries, *pending)
+			pending = nil
+			continue
+		}
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") {
+			if !strings.HasSuffix(text, "]") || strings.HasPrefix(text, "[[") {
+				return nil, fmt.Errorf("line %d: invalid table header %q", line, text)
+			}
+			table = strings.TrimSpace(text[1 : len(text)-1])
+			continue
+		}
+
+		eq := strings.IndexByte(text, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected \"key = value\"", line)
+		}
+		key := strings.TrimSpace(text[:eq])
+		if table != "" {
+			key = table + "." + key
+		}
+		value := strings.TrimSpace(text[eq+1:])
+
+		if strings.HasPrefix(value, "[") {
+			if !strings.HasSuffix(value, "]") {
+				pending, buf = &entry{key: key, line: line}, value
+				continue
+			}
+			values, err := flowList(value, tomlScalar)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			entries = append(entries, entry{key: key, values: values, line: line})
+			continue
+		}
+		scalar, err := tomlScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		entries = append(entries, entry{key: key, values: []string{scalar}, line: line})
+	}
+	if pending != nil {
+		return nil, fmt.Errorf("line %d: unterminated array", pending.line)
+	}
+	return entries, nil
+}
+
+// yamlScalar unquotes a YAML scalar. "~" and "null" are empty.
+func yamlScalar(s string) (string, error) {
+	switch {
+	case s == "~" || s == "null":
+		return "", nil
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid double-quoted string %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("invalid single-quoted string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	return s, nil
+}
+
+// tomlScalar unquotes a TOML string; other values are returned as written.
+func tomlScalar(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", s)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "'"):
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("invalid literal string %s", s)
+		}
+		return s[1 : len(s)-1], nil
+	case s == "":
+		return "", fmt.Errorf("missing value")
+	}
+	return s, nil
+}
+
+// flowList splits a one-line "[a, b]" list and unquotes its items.
+func flowList(s string, scalar func(string) (string, error)) ([]string, error) {
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("invalid list %s", s)
+	}
+	values := []string{}
+	for _, item := range splitUnquoted(s[1:len(s)-1], ',') {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		v, err := scalar(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// stripComment removes a # comment that is not inside quotes. In YAML a
+// comment must follow whitespace; TOML accepts that form too.
+func stripComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// mappingColon returns the index of the colon ending a YAML key, or -1.
+func mappingColon(s string) int {
+	for _, i := range indexesUnquoted(s, ':') {
+		if i == len(s)-1 || s[i+1] == ' ' {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitUnquoted splits s at sep outside quotes.
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for _, i := range indexesUnquoted(s, sep) {
+		parts = append(parts, s[start:i])
+		start = i + 1
+	}
+	return append(parts, s[start:])
+}
+
+// indexesUnquoted returns the positions of c in s outside quotes.
+func indexesUnquoted(s string, c byte) []int {
+	var idx []int
+	quote := byte(0)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == '\\' && quote == '"' {
+				i++
+			} else if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == c:
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// hasChildren reports whether any entry is nested under key.
+func hasChildren(entries []entry, key string) bool {
+	for _, e := range entries {
+		if strings.HasPrefix(e.key, key+".") {
+			return true
+		}
+	}
+	return false
+}
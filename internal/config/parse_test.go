@@ -0,0 +1,153 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []entry
+	}{
+		{
+			name: "nested mappings and scalars",
+			data: "http:\n  timeout: 30s\n  user_agent: \"rankle/1.0\"\ntls:\n  insecure_skip_verify: false\n",
+			want: []entry{
+				{key: "http.timeout", values: []string{"30s"}, line: 2},
+				{key: "http.user_agent", values: []string{"rankle/1.0"}, line: 3},
+				{key: "tls.insecure_skip_verify", values: []string{"false"}, line: 5},
+			},
+		},
+		{
+			name: "flow and block lists",
+			data: "dns:\n  nameservers: [1.1.1.1, '9.9.9.9']\nscanner:\n  modules:\n    - dns\n    - tls\n",
+			want: []entry{
+				{key: "dns.nameservers", values: []string{"1.1.1.1", "9.9.9.9"}, line: 2},
+				{key: "scanner.modules", values: []string{"dns", "tls"}, line: 4},
+			},
+		},
+		{
+			name: "empty flow list",
+			data: "scanner:\n  skip: []\n",
+			want: []entry{{key: "scanner.skip", values: []string{}, line: 2}},
+		},
+		{
+			name: "comments, quotes and document marker",
+			data: "---\n# settings\nhttp:\n  user_agent: 'it''s # not a comment' # a comment\n  timeout: 5s#kept\n",
+			want: []entry{
+				{key: "http.user_agent", values: []string{"it's # not a comment"}, line: 4},
+				{key: "http.timeout", values: []string{"5s#kept"}, line: 5},
+			},
+		},
+		{
+			name: "null and dedent",
+			data: "http:\n  user_agent: ~\ndns:\n  timeout: 2s\n",
+			want: []entry{
+				{key: "http.user_agent", values: []string{""}, line: 2},
+				{key: "dns.timeout", values: []string{"2s"}, line: 4},
+			},
+		},
+		{
+			name: "colons inside values",
+			data: "dns:\n  nameservers: [\"[2606:4700::1111]:53\"]\n",
+			want: []entry{{key: "dns.nameservers", values: []string{"[2606:4700::1111]:53"}, line: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML(tt.data)
+			if err != nil {
+				t.Fatalf("parseYAML() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"http:\n\ttimeout: 5s\n", "line 2: tabs are not allowed"},
+		{"- dns\n", "line 1: list item without a key"},
+		{"http\n", "line 1: expected \"key: value\""},
+		{"http:\n  user_agent: \"open\n", "line 2: invalid double-quoted string"},
+		{"dns:\n  nameservers: [1.1.1.1\n", "line 2: invalid list"},
+	}
+
+	for _, tt := range tests {
+		if _, err := parseYAML(tt.data); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseYAML(%q) error = %v, want %q", tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []entry
+	}{
+		{
+			name: "tables and scalars",
+			data: "[http]\ntimeout = \"30s\"\nmax_retries = 2\n\n[tls]\ninsecure_skip_verify = true\n",
+			want: []entry{
+				{key: "http.timeout", values: []string{"30s"}, line: 2},
+				{key: "http.max_retries", values: []string{"2"}, line: 3},
+				{key: "tls.insecure_skip_verify", values: []string{"true"}, line: 6},
+			},
+		},
+		{
+			name: "dotted keys and literal strings",
+			data: "http.user_agent = 'C:\\agent' # comment\n",
+			want: []entry{{key: "http.user_agent", values: []string{`C:\agent`}, line: 1}},
+		},
+		{
+			name: "multi-line array",
+			data: "[dns]\nnameservers = [\n  \"1.1.1.1\", # primary\n  \"9.9.9.9\",\n]\ntimeout = \"2s\"\n",
+			want: []entry{
+				{key: "dns.nameservers", values: []string{"1.1.1.1", "9.9.9.9"}, line: 2},
+				{key: "dns.timeout", values: []string{"2s"}, line: 6},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTOML(tt.data)
+			if err != nil {
+				t.Fatalf("parseTOML() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTOML() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"[[servers]]\n", "line 1: invalid table header"},
+		{"[http\n", "line 1: invalid table header"},
+		{"timeout\n", "line 1: expected \"key = value\""},
+		{"timeout =\n", "line 1: missing value"},
+		{"nameservers = [\n\"1.1.1.1\"\n", "line 1: unterminated array"},
+		{"user_agent = \"open\n", "line 1: invalid string"},
+	}
+
+	for _, tt := range tests {
+		if _, err := parseTOML(tt.data); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseTOML(%q) error = %v, want %q", tt.data, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package detector
+
+import (
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/rules"
+)
+
+// CDN rule priorities. When only headers identify several CDNs, security
+// proxies usually sit in front of delivery networks, which sit in front of
+// hosting platforms.
+const (
+	priorityEdgeProxy = 20
+	priorityCDN       = 10
+	priorityPlatform  = 0
+)
+
+// cdnRules identifies CDNs from response headers and CNAME targets.
+var cdnRules = []rules.Rule{
+	{
+		Name:     "Cloudflare",
+		Priority: priorityEdgeProxy,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "cloudflare.net"),
+			rules.Header("cf-ray", ""),
+			rules.Header("server", `(?i)^cloudflare`),
+		},
+	},
+	{
+		Name:     "Imperva",
+		Priority: priorityEdgeProxy,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "incapdns.net", "impervadns.net"),
+			rules.Header("x-cdn", `(?i)^(imperva|incapsula)`),
+			rules.Header("x-iinfo", ""),
+		},
+	},
+	{
+		Name:     "Sucuri",
+		Priority: priorityEdgeProxy,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "sucuri.net"),
+			rules.Header("x-sucuri-id", ""),
+			rules.Header("server", `(?i)^Sucuri`),
+		},
+	},
+	{
+		Name:     "Akamai",
+		Priority: priorityCDN,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "akamaiedge.net", "akamai.net", "akamaized.net", "edgekey.net", "edgesuite.net", "akamaihd.net"),
+			rules.Header("server", `(?i)^AkamaiGHost|^AkamaiNetStorage`),
+			rules.Header("x-akamai-transformed", ""),
+			rules.Header("akamai-grn", ""),
+		},
+	},
+	{
+		Name:     "Fastly",
+		Priority: priorityCDN,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "fastly.net", "fastlylb.net"),
+			rules.Header("x-fastly-request-id", ""),
+			rules.Header("x-served-by", `(?i)\bcache-[a-z0-9]+-[a-z]{3}\b`),
+			rules.Header("fastly-debug-digest", ""),
+		},
+	},
+	{
+		Name:     "Amazon CloudFront",
+		Priority: priorityCDN,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "cloudfront.net"),
+			rules.Header("x-amz-cf-id", ""),
+			rules.Header("x-amz-cf-pop", ""),
+			rules.Header("via", `(?i)\(CloudFront\)`),
+		},
+	},
+	{
+		Name:     "Azure Front Door",
+		Priority: priorityCDN,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "azurefd.net", "azureedge.net", "trafficmanager.net"),
+			rules.Header("x-azure-ref", ""),
+			rules.Header("x-fd-healthprobe", ""),
+		},
+	},
+	{
+		Name:     "Google Cloud CDN",
+		Priority: priorityCDN,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "googlevideo.com", "googleusercontent.com", "ghs.googlehosted.com"),
+			rules.Header("via", `(?i)\b1\.1 google\b`),
+		},
+	},
+	{
+		Name:     "TransparentEdge",
+		Priority: priorityCDN,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "transparentcdn.com", "edge2befaster.net"),
+			rules.Header("server", `(?i)transparentedge`),
+			rules.Header("x-tedge-cache", ""),
+		},
+	},
+	{
+		Name:     "StackPath",
+		Priority: priorityCDN,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "stackpathdns.com", "netdna-cdn.com", "maxcdn.com"),
+			rules.Header("server", `(?i)^NetDNA|^StackPath`),
+			rules.Header("x-hw", ""),
+		},
+	},
+	{
+		Name:     "KeyCDN",
+		Priority: priorityCDN,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "kxcdn.com", "keycdn.com"),
+			rules.Header("server", `(?i)^keycdn-engine`),
+		},
+	},
+	{
+		Name:     "BunnyCDN",
+		Priority: priorityCDN,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "b-cdn.net"),
+			rules.Header("server", `(?i)^BunnyCDN`),
+			rules.Header("cdn-pullzone", ""),
+		},
+	},
+	{
+		Name:     "Vercel",
+		Priority: priorityPlatform,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "vercel-dns.com"),
+			rules.Header("x-vercel-id", ""),
+			rules.Header("server", `(?i)^Vercel$`),
+		},
+	},
+	{
+		Name:     "Netlify",
+		Priority: priorityPlatform,
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldCNAME, "netlify.app", "netlify.com"),
+			rules.Header("x-nf-request-id", ""),
+			rules.Header("server", `(?i)^Netlify$`),
+		},
+	},
+}
+
+// DetectCDNs identifies every CDN in front of the site from headers
+// (lowercase keys) and the CNAME chain. CDNs matched through DNS come first,
+// in chain order, since the public name points at the outermost one; CDNs
+// only seen in headers follow by rule priority.
+func (d *Detector) DetectCDNs(headers map[string]string, cnames []string) []models.CDNDetection {
+	var detections []models.CDNDetection
+	index := make(map[string]int)
+	add := func(m rules.Match) {
+		if i, ok := index[m.Name]; ok {
+			detections[i].Evidence = append(detections[i].Evidence, m.Evidence...)
+			return
+		}
+		index[m.Name] = len(detections)
+		detections = append(detections, models.CDNDetection{Name: m.Name, Evidence: m.Evidence})
+	}
+
+	for _, cname := range cnames {
+		for _, m := range rules.Evaluate(cdnRules, rules.Input{rules.FieldCNAME: {cname}}) {
+			add(m)
+		}
+	}
+	for _, m := range rules.Evaluate(cdnRules, rules.Headers(headers)) {
+		add(m)
+	}
+	return detections
+}
+
+// DetectCDN returns the outermost CDN in front of the site, or "" if none
+// was found. See DetectCDNs.
+func (d *Detector) DetectCDN(headers map[string]string, cnames []string) string {
+	if cdns := d.DetectCDNs(headers, cnames); len(cdns) > 0 {
+		return cdns[0].Name
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package detector
+
+import "github.com/javicosvml/rankle-go/pkg/rules"
+
+// cloudRules identifies hosting providers from the reverse DNS name of the
+// site's address and the ISP name reported for it.
+var cloudRules = []rules.Rule{
+	{
+		Name: "Amazon AWS",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "amazonaws.com", "awsglobalaccelerator.com"),
+			rules.Field(rules.FieldISP, `(?i)\bamazon\b|\baws\b`),
+		},
+	},
+	{
+		Name: "Google Cloud",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "googleusercontent.com", "1e100.net"),
+			rules.Field(rules.FieldISP, `(?i)\bgoogle\b`),
+		},
+	},
+	{
+		Name: "Microsoft Azure",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "cloudapp.azure.com", "cloudapp.net", "azurewebsites.net"),
+			rules.Field(rules.FieldISP, `(?i)\bmicrosoft\b|\bazure\b`),
+		},
+	},
+	{
+		Name:     "DigitalOcean",
+		Matchers: []rules.Matcher{rules.Field(rules.FieldISP, `(?i)digitalocean`)},
+	},
+	{
+		Name: "Linode",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "linodeusercontent.com", "members.linode.com"),
+			rules.Field(rules.FieldISP, `(?i)\blinode\b`),
+		},
+	},
+	{
+		Name: "Vultr",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "vultrusercontent.com", "vultr.com"),
+			rules.Field(rules.FieldISP, `(?i)\bvultr\b|\bchoopa\b`),
+		},
+	},
+	{
+		Name: "Hetzner",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "your-server.de", "hetzner.com", "hetzner.cloud"),
+			rules.Field(rules.FieldISP, `(?i)\bhetzner\b`),
+		},
+	},
+	{
+		Name: "OVH",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "ovh.net", "ovh.ca", "ovh.us"),
+			rules.Field(rules.FieldISP, `(?i)\bovh`),
+		},
+	},
+	{
+		Name: "Alibaba Cloud",
+		Matchers: []rules.Matcher{
+			rules.Field(rules.FieldISP, `(?i)\balibaba\b|\baliyun\b`),
+		},
+	},
+	{
+		Name: "Oracle Cloud",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "oraclecloud.com", "oraclevcn.com"),
+			rules.Field(rules.FieldISP, `(?i)\boracle\b`),
+		},
+	},
+	{
+		Name: "IBM Cloud",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "softlayer.com", "sl-reverse.com"),
+			rules.Field(rules.FieldISP, `(?i)\bibm\b|\bsoftlayer\b`),
+		},
+	},
+	{
+		Name: "Scaleway",
+		Matchers: []rules.Matcher{
+			rules.Suffix(rules.FieldHostname, "scaleway.com", "scw.cloud", "poneytelecom.eu"),
+			rules.Field(rules.FieldISP, `(?i)\bscaleway\b|\bonline s\.a\.s\b`),
+		},
+	},
+}
@@ -2,22 +2,57 @@ package detector
 
 import (
 	"net/http"
-	"regexp"
 	"strings"
 
 	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/page"
+	"github.com/javicosvml/rankle-go/pkg/rules"
+	"github.com/javicosvml/rankle-go/pkg/waf"
 )
 
 // Detector handles technology detection.
-type Detector struct{}
+type Detector struct {
+	fingerprints *Fingerprints
+	favicons     *faviconTable
+	trackers     []*tracker
+}
 
-// New creates a new Detector instance.
+// New creates a new Detector instance using the embedded fingerprint set.
 func New() *Detector {
-	return &Detector{}
+	fps, err := defaultFingerprints()
+	if err != nil {
+		// The embedded set ships with the binary; failing to parse it is a
+		// packaging bug, not a runtime condition.
+		panic(err)
+	}
+	favicons, err := defaultFavicons()
+	if err != nil {
+		panic(err)
+	}
+	trackers, err := defaultTrackers()
+	if err != nil {
+		panic(err)
+	}
+	return &Detector{fingerprints: fps, favicons: favicons, trackers: trackers}
 }
 
-// DetectTechnologies analyzes HTML content and headers to detect technologies.
-func (d *Detector) DetectTechnologies(body string, headers map[string]string) *models.Technologies {
+// LoadFingerprints adds Wappalyzer-format technology definitions from a file
+// or directory on top of the embedded set.
+func (d *Detector) LoadFingerprints(path string) error {
+	return d.fingerprints.Load(path)
+}
+
+// Fingerprints returns the technology definitions used by the detector.
+func (d *Detector) Fingerprints() *Fingerprints {
+	return d.fingerprints
+}
+
+// DetectTechnologies matches the fingerprint database against the parsed
+// page, raw HTML, headers and cookies. Script, meta and URL patterns are
+// evaluated against structured page elements, so words that merely appear in
+// page prose do not produce detections. If pg is nil the body is parsed on
+// the fly.
+func (d *Detector) DetectTechnologies(body string, headers map[string]string, pg *models.Page) *models.Technologies {
 	tech := &models.Technologies{
 		Frameworks:  []string{},
 		Libraries:   []string{},
@@ -27,319 +62,63 @@ func (d *Detector) DetectTechnologies(body string, headers map[string]string) *m
 		Fingerprint: []string{},
 	}
 
-	bodyLower := strings.ToLower(body)
-
-	// Detect CMS
-	tech.CMS = d.detectCMS(bodyLower, headers)
-
-	// Detect JavaScript libraries
-	tech.Libraries = d.detectLibraries(bodyLower)
+	if pg == nil {
+		pg = page.Parse(body, "")
+	}
 
-	// Detect frameworks
-	tech.Frameworks = d.detectFrameworks(bodyLower, headers)
+	detections := d.fingerprints.analyze(&pageInput{
+		body:    body,
+		headers: headers,
+		cookies: parseCookies(headers),
+		page:    pg,
+	})
+	d.fingerprints.summarize(detections, tech)
 
-	// Detect web server from headers
-	if server, ok := headers["server"]; ok {
+	// Keep the raw Server header when no fingerprint recognizes it
+	if server, ok := headers["server"]; ok && len(tech.WebServers) == 0 {
 		tech.WebServers = append(tech.WebServers, server)
 	}
 
-	// Detect programming languages
-	tech.Languages = d.detectLanguages(bodyLower, headers)
-
-	// Detect analytics
-	tech.Analytics = d.detectAnalytics(bodyLower)
-
 	return tech
 }
 
-// detectCMS identifies Content Management Systems.
-func (d *Detector) detectCMS(body string, headers map[string]string) string {
-	indicators := make(map[string]int)
-
-	// WordPress
-	wpPatterns := []string{
-		"wp-content",
-		"wp-includes",
-		"wordpress",
-		"/wp-json/",
-		"wp-emoji",
-	}
-	for _, pattern := range wpPatterns {
-		if strings.Contains(body, pattern) {
-			indicators["WordPress"]++
-		}
-	}
-
-	// Drupal
-	drupalPatterns := []string{
-		"drupal",
-		"/sites/default/files",
-		"drupal.js",
-		"drupal-settings-json",
-		"/core/misc/drupal",
-	}
-	for _, pattern := range drupalPatterns {
-		if strings.Contains(body, pattern) {
-			indicators["Drupal"]++
-		}
-	}
-
-	// Joomla
-	joomlaPatterns := []string{
-		"joomla",
-		"/components/com_",
-		"/modules/mod_",
-		"option=com_",
-	}
-	for _, pattern := range joomlaPatterns {
-		if strings.Contains(body, pattern) {
-			indicators["Joomla"]++
-		}
-	}
-
-	// Magento
-	magentoPatterns := []string{
-		"magento",
-		"mage/cookies.js",
-		"/skin/frontend",
-	}
-	for _, pattern := range magentoPatterns {
-		if strings.Contains(body, pattern) {
-			indicators["Magento"]++
-		}
-	}
-
-	// Shopify
-	if strings.Contains(body, "shopify") || strings.Contains(body, "cdn.shopify.com") {
-		indicators["Shopify"] += 2
-	}
-
-	// Check headers for CMS clues
-	if xGenerator, ok := headers["x-generator"]; ok {
-		if strings.Contains(strings.ToLower(xGenerator), "drupal") {
-			indicators["Drupal"] += 2
-		}
-	}
-
-	// Return CMS with highest score
-	maxScore := 0
-	detectedCMS := ""
-	for cms, score := range indicators {
-		if score >= 2 && score > maxScore {
-			maxScore = score
-			detectedCMS = cms
-		}
-	}
-
-	return detectedCMS
-}
-
-// detectLibraries identifies JavaScript libraries.
-func (d *Detector) detectLibraries(body string) []string {
-	libraries := []string{}
-
-	libPatterns := map[string][]string{
-		"jQuery":    {"jquery", "jquery.min.js", "jquery.js"},
-		"React":     {"react", "react.min.js", "react-dom"},
-		"Vue.js":    {"vue.js", "vue.min.js", "vue.runtime"},
-		"Angular":   {"angular.js", "angular.min.js", "ng-"},
-		"Bootstrap": {"bootstrap.css", "bootstrap.min.css", "bootstrap.js"},
-		"D3.js":     {"d3.js", "d3.min.js", "d3.v"},
-		"Lodash":    {"lodash", "lodash.min.js"},
-		"Moment.js": {"moment.js", "moment.min.js"},
-	}
-
-	for lib, patterns := range libPatterns {
-		for _, pattern := range patterns {
-			if strings.Contains(body, pattern) {
-				libraries = append(libraries, lib)
-				break
-			}
-		}
-	}
-
-	return libraries
-}
-
-// detectFrameworks identifies web frameworks.
-func (d *Detector) detectFrameworks(body string, headers map[string]string) []string {
-	frameworks := []string{}
-
-	// Next.js
-	if strings.Contains(body, "__next") || strings.Contains(body, "_next/static") {
-		frameworks = append(frameworks, "Next.js")
-	}
-
-	// Nuxt.js
-	if strings.Contains(body, "__nuxt") || strings.Contains(body, "_nuxt/") {
-		frameworks = append(frameworks, "Nuxt.js")
-	}
-
-	// Laravel (from headers)
-	if xFramework, ok := headers["x-powered-by"]; ok {
-		if strings.Contains(strings.ToLower(xFramework), "laravel") {
-			frameworks = append(frameworks, "Laravel")
-		}
-	}
-
-	return frameworks
-}
-
-// detectLanguages identifies programming languages.
-func (d *Detector) detectLanguages(body string, headers map[string]string) []string {
-	languages := []string{}
-
-	if xPoweredBy, ok := headers["x-powered-by"]; ok {
-		xPoweredBy = strings.ToLower(xPoweredBy)
-		if strings.Contains(xPoweredBy, "php") {
-			languages = append(languages, "PHP")
-		}
-		if strings.Contains(xPoweredBy, "asp.net") {
-			languages = append(languages, "ASP.NET")
-		}
-	}
-
-	// Check for .php extensions in body
-	if matched, _ := regexp.MatchString(`\.php(\?|"|'| |$)`, body); matched {
-		if !contains(languages, "PHP") {
-			languages = append(languages, "PHP")
-		}
-	}
-
-	return languages
-}
-
-// detectAnalytics identifies analytics services.
-func (d *Detector) detectAnalytics(body string) []string {
-	analytics := []string{}
-
-	analyticsPatterns := map[string]string{
-		"Google Analytics":   "google-analytics.com",
-		"Google Tag Manager": "googletagmanager.com",
-		"Facebook Pixel":     "facebook.net/en_us/fbevents.js",
-		"Hotjar":             "hotjar.com",
-		"Mixpanel":           "mixpanel.com",
-	}
-
-	for service, pattern := range analyticsPatterns {
-		if strings.Contains(body, pattern) {
-			analytics = append(analytics, service)
-		}
-	}
-
-	return analytics
-}
-
-// DetectCDN identifies CDN providers from headers and CNAME records.
-func (d *Detector) DetectCDN(headers map[string]string, cnames []string) string {
-	cdnPatterns := map[string][]string{
-		"Cloudflare":        {"cloudflare", "cf-ray"},
-		"Akamai":            {"akamai", "akamaihd.net"},
-		"Fastly":            {"fastly", "x-fastly-request-id"},
-		"Amazon CloudFront": {"cloudfront.net", "x-amz-cf-id"},
-		"Azure CDN":         {"azureedge.net"},
-		"Google Cloud CDN":  {"googlevideo.com", "googleusercontent.com"},
-		"TransparentEdge":   {"transparentcdn.com", "transparentedge"},
-		"MaxCDN":            {"maxcdn.com"},
-		"KeyCDN":            {"keycdn.com"},
-		"Sucuri":            {"sucuri"},
-	}
-
-	// Check headers
-	for cdn, patterns := range cdnPatterns {
-		for headerKey, headerValue := range headers {
-			headerValue = strings.ToLower(headerValue)
-			headerKey = strings.ToLower(headerKey)
-			for _, pattern := range patterns {
-				if strings.Contains(headerValue, strings.ToLower(pattern)) ||
-					strings.Contains(headerKey, strings.ToLower(pattern)) {
-					return cdn
-				}
-			}
-		}
+// DetectWAF identifies Web Application Firewalls from response headers
+// (lowercase keys) and the cookies the response sets, read from resp when
+// given and from the folded Set-Cookie header otherwise.
+func (d *Detector) DetectWAF(headers map[string]string, resp *http.Response) string {
+	var cookies []string
+	if resp != nil {
+		cookies = waf.CookieNames(resp.Header)
+	} else {
+		cookies = sortedKeys(parseCookies(headers))
 	}
 
-	// Check CNAMEs
-	for _, cname := range cnames {
-		cnameLower := strings.ToLower(cname)
-		for cdn, patterns := range cdnPatterns {
-			for _, pattern := range patterns {
-				if strings.Contains(cnameLower, strings.ToLower(pattern)) {
-					return cdn
-				}
-			}
-		}
+	matches := waf.Identify(headers, cookies, "")
+	if len(matches) == 0 {
+		return ""
 	}
-
-	return ""
+	return WAFName(matches[0].Name)
 }
 
-// DetectWAF identifies Web Application Firewalls.
-func (d *Detector) DetectWAF(headers map[string]string, resp *http.Response) string {
-	wafPatterns := map[string][]string{
-		"Cloudflare":      {"cf-ray", "cloudflare"},
-		"Imperva":         {"x-iinfo", "incap"},
-		"Sucuri":          {"x-sucuri", "sucuri"},
-		"ModSecurity":     {"mod_security", "modsecurity"},
-		"F5 BIG-IP":       {"bigip", "f5"},
-		"Barracuda":       {"barracuda"},
-		"Akamai":          {"akamai"},
-		"AWS WAF":         {"awswaf", "x-amzn"},
-		"TransparentEdge": {"transparentedge", "tedge"},
-	}
-
-	for waf, patterns := range wafPatterns {
-		for headerKey, headerValue := range headers {
-			headerValue = strings.ToLower(headerValue)
-			headerKey = strings.ToLower(headerKey)
-			for _, pattern := range patterns {
-				if strings.Contains(headerValue, pattern) ||
-					strings.Contains(headerKey, pattern) {
-					return waf + " WAF"
-				}
-			}
-		}
+// WAFName formats a WAF vendor for display, e.g. "Cloudflare WAF".
+func WAFName(vendor string) string {
+	if strings.HasSuffix(vendor, "WAF") || strings.HasSuffix(vendor, "Firewall") {
+		return vendor
 	}
-
-	return ""
+	return vendor + " WAF"
 }
 
-// DetectCloudProvider identifies cloud/hosting providers.
+// DetectCloudProvider identifies the hosting provider of ip from its
+// reverse DNS hostname and ISP name. The hostname is checked first since
+// ISP names are shared across unrelated businesses.
 func (d *Detector) DetectCloudProvider(ip, hostname, isp string) string {
-	combinedInfo := strings.ToLower(ip + " " + hostname + " " + isp)
-
-	cloudPatterns := map[string][]string{
-		"Amazon AWS":      {"amazon", "aws", "ec2", "amazonaws"},
-		"Google Cloud":    {"google", "gcp", "googlecloud"},
-		"Microsoft Azure": {"azure", "microsoft"},
-		"DigitalOcean":    {"digitalocean"},
-		"Linode":          {"linode"},
-		"Vultr":           {"vultr"},
-		"Hetzner":         {"hetzner"},
-		"OVH":             {"ovh"},
-		"Alibaba Cloud":   {"alibaba", "alibabacloud"},
-		"Oracle Cloud":    {"oracle"},
-		"IBM Cloud":       {"ibm", "softlayer"},
-		"Scaleway":        {"scaleway"},
-	}
-
-	for provider, patterns := range cloudPatterns {
-		for _, pattern := range patterns {
-			if strings.Contains(combinedInfo, pattern) {
-				return provider
-			}
+	if hostname != "" {
+		if provider := rules.First(cloudRules, rules.Input{rules.FieldHostname: {hostname}}); provider != "" {
+			return provider
 		}
 	}
-
-	return ""
-}
-
-// contains checks if a string slice contains a specific item.
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
+	if isp != "" {
+		return rules.First(cloudRules, rules.Input{rules.FieldISP: {isp}})
 	}
-	return false
+	return ""
 }
@@ -0,0 +1,123 @@
+package detector
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+//go:embed favicons/favicons.json
+var embeddedFavicons embed.FS
+
+// faviconProduct is one entry of the favicon hash table. Categories use the
+// Wappalyzer category names so matches are summarized like fingerprints.
+type faviconProduct struct {
+	Product    string   `json:"product"`
+	Categories []string `json:"categories"`
+	Website    string   `json:"website"`
+	MMH3       []int32  `json:"mmh3"`
+	MD5        []string `json:"md5"`
+}
+
+// faviconTable indexes products by favicon hash.
+type faviconTable struct {
+	byMMH3 map[int32]*faviconProduct
+	byMD5  map[string]*faviconProduct
+}
+
+// defaultFavicons loads the hash table embedded in the binary.
+func defaultFavicons() (*faviconTable, error) {
+	data, err := embeddedFavicons.ReadFile("favicons/favicons.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded favicon hashes: %w", err)
+	}
+
+	var products []*faviconProduct
+	if err := json.Unmarshal(data, &products); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded favicon hashes: %w", err)
+	}
+
+	table := &faviconTable{
+		byMMH3: make(map[int32]*faviconProduct),
+		byMD5:  make(map[string]*faviconProduct),
+	}
+	for _, p := range products {
+		for _, h := range p.MMH3 {
+			table.byMMH3[h] = p
+		}
+		for _, h := range p.MD5 {
+			table.byMD5[strings.ToLower(h)] = p
+		}
+	}
+	return table, nil
+}
+
+// DetectFavicon looks the favicon hashes up in the bundled table and adds
+// the identified product to tech. A product that was already detected gains
+// the favicon as extra evidence.
+func (d *Detector) DetectFavicon(fav *models.Favicon, tech *models.Technologies) {
+	if fav == nil || tech == nil {
+		return
+	}
+
+	product, key, match := d.favicons.byMMH3[fav.MMH3], "mmh3", strconv.Itoa(int(fav.MMH3))
+	if product == nil {
+		product, key, match = d.favicons.byMD5[fav.MD5], "md5", fav.MD5
+	}
+	if product == nil {
+		return
+	}
+
+	evidence := models.Evidence{
+		Source:     models.EvidenceFavicon,
+		Key:        key,
+		Match:      match,
+		Confidence: maxConfidence,
+	}
+
+	for i := range tech.Items {
+		if tech.Items[i].Name == product.Product {
+			tech.Items[i].Confidence = maxConfidence
+			tech.Items[i].Evidence = append(tech.Items[i].Evidence, evidence)
+			sortItems(tech)
+			return
+		}
+	}
+
+	item := models.Technology{
+		Name:       product.Product,
+		Categories: product.Categories,
+		Confidence: maxConfidence,
+		Evidence:   []models.Evidence{evidence},
+		Website:    product.Website,
+	}
+	categories := d.fingerprints.categoryIDs(product.Categories)
+	if fp, ok := d.fingerprints.Get(product.Product); ok {
+		item.CPE = fp.CPE
+		categories = fp.Categories
+	}
+	tech.Items = append(tech.Items, item)
+	sortItems(tech)
+
+	addSummary(tech, summaryCategory(categories), product.Product)
+}
+
+// categoryIDs maps category names back to their IDs. Unknown names are
+// dropped.
+func (f *Fingerprints) categoryIDs(names []string) []int {
+	var ids []int
+	for _, name := range names {
+		for id, cat := range f.categories {
+			if strings.EqualFold(cat.Name, name) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,436 @@
+package detector
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed fingerprints/*.json
+var embeddedFingerprints embed.FS
+
+// categoriesFile is the file name Wappalyzer uses for category definitions.
+const categoriesFile = "categories.json"
+
+// Category is a Wappalyzer technology category.
+type Category struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+}
+
+// Fingerprint is a compiled technology definition.
+type Fingerprint struct {
+	Name       string
+	Categories []int
+	Website    string
+	CPE        string
+	Headers    map[string][]*Pattern
+	Cookies    map[string][]*Pattern
+	Meta       map[string][]*Pattern
+	HTML       []*Pattern
+	ScriptSrc  []*Pattern
+	Scripts    []*Pattern
+	URL        []*Pattern
+	Implies    []*Pattern
+	Excludes   []string
+}
+
+// Pattern is a single Wappalyzer pattern such as
+// "jquery-([\d.]+)\.js\;version:\1\;confidence:50". A nil Regex matches on
+// presence alone (an empty pattern in the source data).
+type Pattern struct {
+	Raw        string
+	Regex      *regexp.Regexp
+	Version    string
+	Confidence int
+}
+
+// rawFingerprint mirrors one entry of a Wappalyzer technologies file. The
+// js and dom keys need a browser and are only kept to report that they are
+// not evaluated; "script" is the legacy name of "scriptSrc".
+type rawFingerprint struct {
+	Cats      []int           `json:"cats"`
+	Website   string          `json:"website"`
+	CPE       string          `json:"cpe"`
+	Headers   patternMap      `json:"headers"`
+	Cookies   patternMap      `json:"cookies"`
+	Meta      patternMap      `json:"meta"`
+	HTML      stringList      `json:"html"`
+	ScriptSrc stringList      `json:"scriptSrc"`
+	Script    stringList      `json:"script"`
+	Scripts   stringList      `json:"scripts"`
+	URL       stringList      `json:"url"`
+	Implies   stringList      `json:"implies"`
+	Excludes  stringList      `json:"excludes"`
+	JS        json.RawMessage `json:"js"`
+	DOM       json.RawMessage `json:"dom"`
+}
+
+// needsBrowser reports whether the definition has js or dom patterns.
+func (raw rawFingerprint) needsBrowser() bool {
+	present := func(m json.RawMessage) bool { return len(m) > 0 && string(m) != "null" }
+	return present(raw.JS) || present(raw.DOM)
+}
+
+// rawFile is the legacy single-file layout (apps.json) that bundles
+// technologies and categories together.
+type rawFile struct {
+	Technologies map[string]rawFingerprint `json:"technologies"`
+	Apps         map[string]rawFingerprint `json:"apps"`
+	Categories   map[string]Category       `json:"categories"`
+}
+
+// stringList accepts either a JSON string or an array of strings.
+type stringList []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = stringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// patternMap maps a header, cookie or meta name to one or more patterns.
+type patternMap map[string]stringList
+
+// Fingerprints is a set of technology definitions in the Wappalyzer schema.
+type Fingerprints struct {
+	technologies map[string]*Fingerprint
+	categories   map[int]Category
+	warnings     []string
+	// browserOnly counts loaded definitions with js or dom patterns.
+	browserOnly int
+}
+
+// NewFingerprints returns an empty fingerprint set.
+func NewFingerprints() *Fingerprints {
+	return &Fingerprints{
+		technologies: make(map[string]*Fingerprint),
+		categories:   make(map[int]Category),
+	}
+}
+
+// defaultFingerprints loads the fingerprint set embedded in the binary.
+func defaultFingerprints() (*Fingerprints, error) {
+	fps := NewFingerprints()
+	entries, err := embeddedFingerprints.ReadDir("fingerprints")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded fingerprints: %w", err)
+	}
+	for _, entry := range entries {
+		data, err := embeddedFingerprints.ReadFile("fingerprints/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded fingerprints: %w", err)
+		}
+		if err := fps.add(entry.Name(), data); err != nil {
+			return nil, err
+		}
+	}
+	return fps, nil
+}
+
+// Load adds definitions from a file or from every .json file in a directory,
+// such as a checkout of Wappalyzer's src/technologies. A file named
+// categories.json is read as category definitions. Definitions replace
+// earlier ones with the same name. Definitions with js or dom patterns,
+// which need a browser, produce a single warning per call.
+func (f *Fingerprints) Load(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to open fingerprints: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return fmt.Errorf("failed to list fingerprints: %w", err)
+		}
+		sort.Strings(files)
+	}
+
+	browserOnly := f.browserOnly
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read fingerprints: %w", err)
+		}
+		if err := f.add(filepath.Base(file), data); err != nil {
+			return err
+		}
+	}
+	if n := f.browserOnly - browserOnly; n > 0 {
+		f.warnings = append(f.warnings, fmt.Sprintf("%s: js and dom patterns of %d technologies need a browser and are not evaluated", path, n))
+	}
+	return nil
+}
+
+// add parses one fingerprint file and merges it into the set.
+func (f *Fingerprints) add(name string, data []byte) error {
+	if name == categoriesFile {
+		var cats map[string]Category
+		if err := json.Unmarshal(data, &cats); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		return f.addCategories(name, cats)
+	}
+
+	var bundle rawFile
+	if err := json.Unmarshal(data, &bundle); err == nil &&
+		(bundle.Technologies != nil || bundle.Apps != nil) {
+		if err := f.addCategories(name, bundle.Categories); err != nil {
+			return err
+		}
+		f.addTechnologies(name, bundle.Technologies)
+		f.addTechnologies(name, bundle.Apps)
+		return nil
+	}
+
+	var techs map[string]rawFingerprint
+	if err := json.Unmarshal(data, &techs); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	f.addTechnologies(name, techs)
+	return nil
+}
+
+// addCategories merges category definitions keyed by numeric ID.
+func (f *Fingerprints) addCategories(source string, cats map[string]Category) error {
+	for key, cat := range cats {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("%s: invalid category id %q", source, key)
+		}
+		f.categories[id] = cat
+	}
+	return nil
+}
+
+// addTechnologies compiles and merges technology definitions.
+func (f *Fingerprints) addTechnologies(source string, techs map[string]rawFingerprint) {
+	names := make([]string, 0, len(techs))
+	for name := range techs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fp, warnings := compileFingerprint(name, techs[name])
+		for _, w := range warnings {
+			f.warnings = append(f.warnings, source+": "+w)
+		}
+		if techs[name].needsBrowser() {
+			f.browserOnly++
+		}
+		f.technologies[name] = fp
+	}
+}
+
+// Warnings returns the patterns and pattern kinds that were skipped while
+// loading.
+func (f *Fingerprints) Warnings() []string {
+	return f.warnings
+}
+
+// Len returns the number of technology definitions.
+func (f *Fingerprints) Len() int {
+	return len(f.technologies)
+}
+
+// Category returns the category with the given ID.
+func (f *Fingerprints) Category(id int) (Category, bool) {
+	cat, ok := f.categories[id]
+	return cat, ok
+}
+
+// Get returns the definition of a technology by name.
+func (f *Fingerprints) Get(name string) (*Fingerprint, bool) {
+	fp, ok := f.technologies[name]
+	return fp, ok
+}
+
+// Names returns all technology names in sorted order.
+func (f *Fingerprints) Names() []string {
+	names := make([]string, 0, len(f.technologies))
+	for name := range f.technologies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// compileFingerprint converts a raw definition into its compiled form.
+// Patterns that RE2 cannot compile (lookaheads, backreferences) are skipped
+// and reported as warnings rather than rejecting the whole definition.
+func compileFingerprint(name string, raw rawFingerprint) (*Fingerprint, []string) {
+	c := &patternCompiler{name: name}
+	fp := &Fingerprint{
+		Name:       name,
+		Categories: raw.Cats,
+		Website:    raw.Website,
+		CPE:        raw.CPE,
+		Excludes:   raw.Excludes,
+		Headers:    c.compileMap("headers", raw.Headers, true),
+		Cookies:    c.compileMap("cookies", raw.Cookies, false),
+		Meta:       c.compileMap("meta", raw.Meta, true),
+		HTML:       c.compileList("html", raw.HTML),
+		ScriptSrc:  c.compileList("scriptSrc", append(raw.ScriptSrc, raw.Script...)),
+		Scripts:    c.compileList("scripts", raw.Scripts),
+		URL:        c.compileList("url", raw.URL),
+	}
+
+	// Implied names carry the same ";confidence:" suffix as patterns but are
+	// not regular expressions.
+	for _, implied := range raw.Implies {
+		p := parsePattern(implied)
+		fp.Implies = append(fp.Implies, &Pattern{Raw: p.expr, Confidence: p.confidence})
+	}
+
+	return fp, c.warnings
+}
+
+// patternCompiler compiles the patterns of one technology and collects
+// warnings for the ones it has to skip.
+type patternCompiler struct {
+	name     string
+	warnings []string
+}
+
+// compileMap compiles keyed patterns. Header and meta names are
+// case-insensitive; cookie names are not.
+func (c *patternCompiler) compileMap(field string, raw patternMap, foldKeys bool) map[string][]*Pattern {
+	if len(raw) == 0 {
+		return nil
+	}
+	compiled := make(map[string][]*Pattern, len(raw))
+	for key, list := range raw {
+		if foldKeys {
+			key = strings.ToLower(key)
+		}
+		compiled[key] = append(compiled[key], c.compileList(field, list)...)
+	}
+	return compiled
+}
+
+// compileList compiles a list of patterns, skipping invalid ones.
+func (c *patternCompiler) compileList(field string, list []string) []*Pattern {
+	patterns := make([]*Pattern, 0, len(list))
+	for _, raw := range list {
+		p, err := compilePattern(raw)
+		if err != nil {
+			c.warnings = append(c.warnings, fmt.Sprintf("%s %s: %v", c.name, field, err))
+			continue
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// parsedPattern is a pattern split into its expression and tags.
+type parsedPattern struct {
+	expr       string
+	version    string
+	confidence int
+}
+
+// parsePattern splits "expr\;version:\1\;confidence:50" into its parts.
+func parsePattern(raw string) parsedPattern {
+	parts := strings.Split(raw, `\;`)
+	p := parsedPattern{expr: parts[0], confidence: 100}
+	for _, tag := range parts[1:] {
+		key, value, found := strings.Cut(tag, ":")
+		if !found {
+			continue
+		}
+		switch key {
+		case "version":
+			p.version = value
+		case "confidence":
+			if c, err := strconv.Atoi(value); err == nil {
+				p.confidence = c
+			}
+		}
+	}
+	return p
+}
+
+// compilePattern compiles a single pattern. Wappalyzer expressions are
+// JavaScript regular expressions matched case-insensitively; the subset
+// used by fingerprints is compatible with RE2 apart from escaped slashes.
+func compilePattern(raw string) (*Pattern, error) {
+	p := parsePattern(raw)
+	pattern := &Pattern{Raw: raw, Version: p.version, Confidence: p.confidence}
+	if p.expr == "" {
+		return pattern, nil
+	}
+
+	expr := strings.ReplaceAll(p.expr, `\/`, `/`)
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", raw, err)
+	}
+	pattern.Regex = re
+	return pattern, nil
+}
+
+// match applies the pattern to value. It reports whether it matched and the
+// version extracted from capture groups, if the pattern defines one.
+func (p *Pattern) match(value string) (bool, string) {
+	if p.Regex == nil {
+		return true, ""
+	}
+	groups := p.Regex.FindStringSubmatch(value)
+	if groups == nil {
+		return false, ""
+	}
+	return true, resolveVersion(p.Version, groups)
+}
+
+var (
+	versionGroupRegex   = regexp.MustCompile(`\\(\d)`)
+	versionTernaryRegex = regexp.MustCompile(`^(.*?)\?(.*?):(.*)$`)
+)
+
+// resolveVersion fills a version template such as "\1" or "\1?4:3" from
+// regex capture groups, following Wappalyzer's semantics.
+func resolveVersion(template string, groups []string) string {
+	if template == "" {
+		return ""
+	}
+
+	version := versionGroupRegex.ReplaceAllStringFunc(template, func(ref string) string {
+		idx := int(ref[1] - '0')
+		if idx < len(groups) {
+			return groups[idx]
+		}
+		return ""
+	})
+
+	// Ternary form: "value?ifSet:ifEmpty".
+	if !strings.Contains(template, "?") {
+		return strings.TrimSpace(version)
+	}
+	if m := versionTernaryRegex.FindStringSubmatch(version); m != nil {
+		if m[1] != "" {
+			version = m[2]
+		} else {
+			version = m[3]
+		}
+	}
+
+	return strings.TrimSpace(version)
+}
@@ -0,0 +1,66 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadWarnings(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.json": `{
+			"Alpha": {"js": {"Alpha.version": "([\\d.]+)\\;version:\\1"}, "html": "alpha"},
+			"Beta": {"dom": "#beta", "headers": {"X-Beta": ""}},
+			"Gamma": {"html": "gamma", "js": null}
+		}`,
+		"b.json": `{
+			"Delta": {"js": {"delta": ""}, "dom": {"#delta": {"exists": ""}}},
+			"Epsilon": {"html": "(?=lookahead)"}
+		}`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fps := NewFingerprints()
+	if err := fps.Load(dir); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if fps.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", fps.Len())
+	}
+
+	warnings := fps.Warnings()
+	if len(warnings) != 2 {
+		t.Fatalf("Warnings() = %q, want 2 warnings", warnings)
+	}
+	want := dir + ": js and dom patterns of 3 technologies need a browser and are not evaluated"
+	if warnings[1] != want {
+		t.Errorf("Warnings()[1] = %q, want %q", warnings[1], want)
+	}
+
+	// Definitions with js patterns still match on their other patterns.
+	alpha, _ := fps.Get("Alpha")
+	if len(alpha.HTML) != 1 {
+		t.Errorf("Alpha HTML patterns = %d, want 1", len(alpha.HTML))
+	}
+}
+
+func TestLoadWithoutBrowserPatterns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plain.json")
+	if err := os.WriteFile(path, []byte(`{"Plain": {"html": "plain"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fps := NewFingerprints()
+	if err := fps.Load(path); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got := fps.Warnings(); !reflect.DeepEqual(got, []string(nil)) {
+		t.Errorf("Warnings() = %q, want none", got)
+	}
+}
@@ -0,0 +1,122 @@
+package detector
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/page"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenResult is what a fixture is expected to produce.
+type goldenResult struct {
+	Technologies  *models.Technologies  `json:"technologies"`
+	CDNs          []models.CDNDetection `json:"cdns"`
+	WAF           string                `json:"waf"`
+	CloudProvider string                `json:"cloud_provider"`
+	TrackingIDs   []models.TrackingID   `json:"tracking_ids"`
+}
+
+// TestGolden runs the detectors over each fixture in testdata/golden and
+// compares the result with its golden.json. A fixture is a directory with
+// page.html, headers.txt ("Name: value" lines) and optionally dns.txt
+// ("cname:", "hostname:" and "isp:" lines). Run with -update after an
+// intended change to the signatures and review the diff.
+func TestGolden(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("testdata", "golden", "*"))
+	if err != nil || len(dirs) == 0 {
+		t.Fatalf("no fixtures found: %v", err)
+	}
+
+	d := New()
+	for _, dir := range dirs {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			body := readFixture(t, dir, "page.html")
+			headers := readHeaders(t, dir)
+			dns := readDNS(t, dir)
+
+			pg := page.Parse(body, "https://example.com/")
+			got := goldenResult{
+				Technologies:  d.DetectTechnologies(body, headers, pg),
+				CDNs:          d.DetectCDNs(headers, dns["cname"]),
+				WAF:           d.DetectWAF(headers, nil),
+				CloudProvider: d.DetectCloudProvider("", first(dns["hostname"]), first(dns["isp"])),
+				TrackingIDs:   d.ExtractTrackingIDs(body),
+			}
+			data, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = append(data, '\n')
+
+			golden := filepath.Join(dir, "golden.json")
+			if *update {
+				if err := os.WriteFile(golden, data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if !bytes.Equal(data, want) {
+				t.Errorf("result differs from %s; run go test -update and review the diff\n got: %s", golden, data)
+			}
+		})
+	}
+}
+
+func readFixture(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// readHeaders parses headers.txt into the lowercase, comma-joined form the
+// scanner stores in models.HTTPAnalysis.
+func readHeaders(t *testing.T, dir string) map[string]string {
+	headers := make(map[string]string)
+	for key, values := range readFields(t, dir, "headers.txt") {
+		headers[key] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+func readDNS(t *testing.T, dir string) map[string][]string {
+	return readFields(t, dir, "dns.txt")
+}
+
+// readFields reads "Name: value" lines, keyed by lowercase name.
+func readFields(t *testing.T, dir, name string) map[string][]string {
+	t.Helper()
+	fields := make(map[string][]string)
+	sc := bufio.NewScanner(strings.NewReader(readFixture(t, dir, name)))
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		fields[key] = append(fields[key], strings.TrimSpace(value))
+	}
+	return fields
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,135 @@
+package detector
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// npmAliases maps npm packages to fingerprint names that name normalization
+// does not find.
+var npmAliases = map[string]string{
+	"angular":                      "AngularJS",
+	"@angular/core":                "Angular",
+	"react-dom":                    "React",
+	"mixpanel-browser":             "Mixpanel",
+	"@sentry/browser":              "Sentry",
+	"@sentry/react":                "Sentry",
+	"@sentry/vue":                  "Sentry",
+	"@stripe/stripe-js":            "Stripe",
+	"@bugsnag/js":                  "Bugsnag",
+	"@datadog/browser-rum":         "Datadog RUM",
+	"@amplitude/analytics-browser": "Amplitude",
+	"amplitude-js":                 "Amplitude",
+	"@segment/analytics-next":      "Segment",
+	"@fullstory/browser":           "FullStory",
+	"launchdarkly-js-client-sdk":   "LaunchDarkly",
+	"@elastic/apm-rum":             "Elastic APM",
+}
+
+// AddPackages merges the npm packages found in source maps into tech.
+// Packages with a fingerprint update that technology, taking the source
+// map's version since it names the exact release bundled; other packages
+// are added under their npm name. Within a map the shallowest copy of a
+// package comes first and sets the version.
+func (d *Detector) AddPackages(maps []models.SourceMap, tech *models.Technologies) {
+	if tech == nil {
+		return
+	}
+	names := d.npmNames()
+	updated := make(map[string]bool)
+
+	for _, sm := range maps {
+		for _, pkg := range sm.Packages {
+			name := npmAliases[pkg.Name]
+			if name == "" {
+				name = names[normalizePackage(pkg.Name)]
+			}
+			if name == "" {
+				name = pkg.Name
+			}
+			d.addPackage(tech, name, pkg, sm.URL, updated)
+		}
+	}
+	sortItems(tech)
+}
+
+// addPackage records one package under the technology name.
+func (d *Detector) addPackage(tech *models.Technologies, name string, pkg models.Package, mapURL string, updated map[string]bool) {
+	evidence := models.Evidence{
+		Source:     models.EvidenceSourceMap,
+		Key:        mapURL,
+		Match:      pkg.Name,
+		Confidence: maxConfidence,
+	}
+	if pkg.Version != "" {
+		evidence.Match += "@" + pkg.Version
+	}
+
+	for i := range tech.Items {
+		item := &tech.Items[i]
+		if item.Name != name {
+			continue
+		}
+		item.Confidence = maxConfidence
+		item.Evidence = append(item.Evidence, evidence)
+		if item.Package == "" {
+			item.Package = pkg.Name
+		}
+		if pkg.Version != "" && !updated[name] {
+			item.Version = pkg.Version
+			updated[name] = true
+		}
+		return
+	}
+
+	item := models.Technology{
+		Name:       name,
+		Version:    pkg.Version,
+		Confidence: maxConfidence,
+		Evidence:   []models.Evidence{evidence},
+		Package:    pkg.Name,
+	}
+	categories := []int{categoryJavaScriptLibrary}
+	fp, known := d.fingerprints.Get(name)
+	if known {
+		item.CPE = fp.CPE
+		item.Website = fp.Website
+		categories = fp.Categories
+	}
+	for _, id := range categories {
+		if cat, ok := d.fingerprints.Category(id); ok {
+			item.Categories = append(item.Categories, cat.Name)
+		}
+	}
+	updated[name] = pkg.Version != ""
+	tech.Items = append(tech.Items, item)
+
+	// Only fingerprinted technologies make the summary; a bundle can hold
+	// hundreds of packages.
+	if known {
+		addSummary(tech, summaryCategory(categories), name)
+	}
+}
+
+// npmNames indexes fingerprint names by their normalized form.
+func (d *Detector) npmNames() map[string]string {
+	names := make(map[string]string)
+	for _, name := range d.fingerprints.Names() {
+		names[normalizePackage(name)] = name
+	}
+	return names
+}
+
+// normalizePackage reduces a package or technology name to lowercase
+// letters and digits without a "js" suffix, so "vue" matches "Vue.js".
+func normalizePackage(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "js")
+}
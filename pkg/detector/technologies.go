@@ -0,0 +1,333 @@
+package detector
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+const (
+	maxConfidence = 100
+	// maxEvidenceMatch caps the matched text stored in evidence records.
+	maxEvidenceMatch = 120
+)
+
+// Wappalyzer category IDs mapped onto the summary fields of
+// models.Technologies. Technologies in other categories are reported under
+// Fingerprint.
+const (
+	categoryCMS                 = 1
+	categoryEcommerce           = 6
+	categoryAnalytics           = 10
+	categoryBlogs               = 11
+	categoryJavaScriptFramework = 12
+	categoryWebFramework        = 18
+	categoryWebServer           = 22
+	categoryProgrammingLanguage = 27
+	categoryTagManager          = 42
+	categoryJavaScriptLibrary   = 59
+	categoryUIFramework         = 66
+)
+
+var setCookieRegex = regexp.MustCompile(`(?:^|,\s*)([^=;,\s]+)=([^;,]*)`)
+
+// cookieAttributes are Set-Cookie attribute names that must not be mistaken
+// for cookie names when parsing a folded header.
+var cookieAttributes = map[string]bool{
+	"path": true, "domain": true, "expires": true, "max-age": true,
+	"samesite": true, "secure": true, "httponly": true, "priority": true,
+}
+
+// pageInput is everything a fingerprint can be matched against.
+type pageInput struct {
+	body    string
+	headers map[string]string
+	cookies map[string]string
+	page    *models.Page
+}
+
+// detection is a technology matched against a page.
+type detection struct {
+	fp         *Fingerprint
+	version    string
+	confidence int
+	evidence   []models.Evidence
+	seen       map[string]bool
+}
+
+// add records a pattern hit, keeping the most specific version seen. Each
+// pattern contributes confidence once, however many elements it matches.
+func (d *detection) add(p *Pattern, source, key, match, version string) {
+	id := source + "\x00" + key + "\x00" + p.Raw
+	if d.seen == nil {
+		d.seen = make(map[string]bool)
+	}
+	if d.seen[id] {
+		return
+	}
+	d.seen[id] = true
+
+	d.confidence = min(d.confidence+p.Confidence, maxConfidence)
+	if len(version) > len(d.version) {
+		d.version = version
+	}
+	if len(match) > maxEvidenceMatch {
+		match = strings.ToValidUTF8(match[:maxEvidenceMatch], "") + "…"
+	}
+	d.evidence = append(d.evidence, models.Evidence{
+		Source:     source,
+		Key:        key,
+		Pattern:    p.Raw,
+		Match:      match,
+		Confidence: p.Confidence,
+	})
+}
+
+// analyze matches every fingerprint against the input, then applies
+// implies and excludes rules.
+func (f *Fingerprints) analyze(in *pageInput) map[string]*detection {
+	detections := make(map[string]*detection)
+
+	for _, name := range f.Names() {
+		fp := f.technologies[name]
+		if det := f.matchFingerprint(fp, in); det != nil {
+			detections[name] = det
+		}
+	}
+
+	f.resolveImplies(detections)
+
+	// Walk in name order so that mutual exclusions resolve the same way
+	// on every run: a technology removed earlier excludes nothing.
+	for _, name := range f.Names() {
+		det, ok := detections[name]
+		if !ok {
+			continue
+		}
+		for _, excluded := range det.fp.Excludes {
+			delete(detections, excluded)
+		}
+	}
+
+	return detections
+}
+
+// matchFingerprint evaluates all patterns of one technology.
+func (f *Fingerprints) matchFingerprint(fp *Fingerprint, in *pageInput) *detection {
+	det := &detection{fp: fp}
+
+	hit := func(p *Pattern, source, key, value string) {
+		if ok, version := p.match(value); ok {
+			det.add(p, source, key, matchedText(p, value), version)
+		}
+	}
+
+	for _, name := range sortedKeys(fp.Headers) {
+		if value, ok := in.headers[name]; ok {
+			for _, p := range fp.Headers[name] {
+				hit(p, models.EvidenceHeader, name, value)
+			}
+		}
+	}
+	for _, name := range sortedKeys(fp.Cookies) {
+		if value, ok := in.cookies[name]; ok {
+			for _, p := range fp.Cookies[name] {
+				hit(p, models.EvidenceCookie, name, value)
+			}
+		}
+	}
+	for _, p := range fp.HTML {
+		hit(p, models.EvidenceHTML, "", in.body)
+	}
+	for _, p := range fp.URL {
+		hit(p, models.EvidenceURL, "", in.page.URL)
+	}
+	for _, src := range in.page.Scripts {
+		for _, p := range fp.ScriptSrc {
+			hit(p, models.EvidenceScript, "", src)
+		}
+	}
+	for _, code := range in.page.InlineScripts {
+		for _, p := range fp.Scripts {
+			hit(p, models.EvidenceInlineScript, "", code)
+		}
+	}
+	for _, meta := range in.page.Meta {
+		for _, p := range fp.Meta[meta.Name] {
+			hit(p, models.EvidenceMeta, meta.Name, meta.Content)
+		}
+	}
+
+	if len(det.evidence) == 0 {
+		return nil
+	}
+	return det
+}
+
+// sortedKeys returns the keys of a map in sorted order, so evidence is
+// listed the same way on every run.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// matchedText returns the part of value a pattern matched, or the whole
+// value for presence-only patterns.
+func matchedText(p *Pattern, value string) string {
+	if p.Regex == nil {
+		return value
+	}
+	return p.Regex.FindString(value)
+}
+
+// resolveImplies adds technologies implied by detected ones until no new
+// technology is added.
+func (f *Fingerprints) resolveImplies(detections map[string]*detection) {
+	pending := make([]string, 0, len(detections))
+	for _, name := range f.Names() {
+		if _, ok := detections[name]; ok {
+			pending = append(pending, name)
+		}
+	}
+
+	for len(pending) > 0 {
+		det := detections[pending[0]]
+		pending = pending[1:]
+
+		for _, implied := range det.fp.Implies {
+			fp, ok := f.technologies[implied.Raw]
+			if !ok {
+				continue
+			}
+			confidence := min(det.confidence, implied.Confidence)
+			evidence := models.Evidence{
+				Source:     models.EvidenceImplied,
+				Key:        det.fp.Name,
+				Confidence: confidence,
+			}
+			if existing, ok := detections[fp.Name]; ok {
+				existing.confidence = max(existing.confidence, confidence)
+				existing.evidence = append(existing.evidence, evidence)
+				continue
+			}
+			detections[fp.Name] = &detection{
+				fp:         fp,
+				confidence: confidence,
+				evidence:   []models.Evidence{evidence},
+			}
+			pending = append(pending, fp.Name)
+		}
+	}
+}
+
+// parseCookies extracts cookie names and values from the folded Set-Cookie
+// header produced by the scanner.
+func parseCookies(headers map[string]string) map[string]string {
+	cookies := make(map[string]string)
+	for _, m := range setCookieRegex.FindAllStringSubmatch(headers["set-cookie"], -1) {
+		if cookieAttributes[strings.ToLower(m[1])] {
+			continue
+		}
+		cookies[m[1]] = m[2]
+	}
+	return cookies
+}
+
+// summarize fills models.Technologies from detections: one record per
+// technology, ordered by confidence then name, plus the category summary.
+func (f *Fingerprints) summarize(detections map[string]*detection, tech *models.Technologies) {
+	cmsConfidence := 0
+	for _, name := range f.Names() {
+		det, ok := detections[name]
+		if !ok {
+			continue
+		}
+
+		tech.Items = append(tech.Items, f.record(det))
+
+		category := summaryCategory(det.fp.Categories)
+		if category == categoryCMS {
+			if det.confidence > cmsConfidence {
+				cmsConfidence = det.confidence
+				tech.CMS = name
+			}
+			continue
+		}
+		addSummary(tech, category, name)
+	}
+
+	sortItems(tech)
+}
+
+// addSummary lists name under the summary field for category. A CMS is only
+// set if none was detected yet.
+func addSummary(tech *models.Technologies, category int, name string) {
+	switch category {
+	case categoryCMS:
+		if tech.CMS == "" {
+			tech.CMS = name
+		}
+	case categoryJavaScriptLibrary:
+		tech.Libraries = append(tech.Libraries, name)
+	case categoryWebFramework:
+		tech.Frameworks = append(tech.Frameworks, name)
+	case categoryWebServer:
+		tech.WebServers = append(tech.WebServers, name)
+	case categoryProgrammingLanguage:
+		tech.Languages = append(tech.Languages, name)
+	case categoryAnalytics:
+		tech.Analytics = append(tech.Analytics, name)
+	default:
+		tech.Fingerprint = append(tech.Fingerprint, name)
+	}
+}
+
+// sortItems orders detection records by confidence, keeping name order for
+// ties.
+func sortItems(tech *models.Technologies) {
+	sort.SliceStable(tech.Items, func(i, j int) bool {
+		return tech.Items[i].Confidence > tech.Items[j].Confidence
+	})
+}
+
+// record converts a detection into its report form.
+func (f *Fingerprints) record(det *detection) models.Technology {
+	item := models.Technology{
+		Name:       det.fp.Name,
+		Version:    det.version,
+		Confidence: det.confidence,
+		Evidence:   det.evidence,
+		CPE:        det.fp.CPE,
+		Website:    det.fp.Website,
+	}
+	for _, id := range det.fp.Categories {
+		if cat, ok := f.categories[id]; ok {
+			item.Categories = append(item.Categories, cat.Name)
+		}
+	}
+	return item
+}
+
+// summaryCategory picks the summary field a technology is reported under,
+// based on the first of its categories that has one.
+func summaryCategory(categories []int) int {
+	for _, cat := range categories {
+		switch cat {
+		case categoryCMS, categoryEcommerce, categoryBlogs:
+			return categoryCMS
+		case categoryJavaScriptLibrary, categoryJavaScriptFramework, categoryUIFramework:
+			return categoryJavaScriptLibrary
+		case categoryWebFramework, categoryWebServer, categoryProgrammingLanguage:
+			return cat
+		case categoryAnalytics, categoryTagManager:
+			return categoryAnalytics
+		}
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package detector
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+//go:embed trackers/trackers.json
+var embeddedTrackers embed.FS
+
+// tracker extracts account identifiers of one third-party service. Each
+// pattern has a single capture group holding the ID. Service names match
+// the fingerprint names so IDs can be tied to detected technologies.
+type tracker struct {
+	Service  string   `json:"service"`
+	Type     string   `json:"type"`
+	Patterns []string `json:"patterns"`
+
+	compiled []*regexp.Regexp
+}
+
+// defaultTrackers loads the tracker definitions embedded in the binary.
+func defaultTrackers() ([]*tracker, error) {
+	data, err := embeddedTrackers.ReadFile("trackers/trackers.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded trackers: %w", err)
+	}
+
+	var trackers []*tracker
+	if err := json.Unmarshal(data, &trackers); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded trackers: %w", err)
+	}
+	for _, t := range trackers {
+		for _, expr := range t.Patterns {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("tracker %s: invalid pattern %q: %w", t.Service, expr, err)
+			}
+			t.compiled = append(t.compiled, re)
+		}
+	}
+	return trackers, nil
+}
+
+// ExtractTrackingIDs finds analytics, tag manager, advertising pixel and
+// other third-party account IDs in the page body, including inline scripts
+// and script URLs. IDs are what ties sites run by the same owner together.
+// The result is sorted by service, then ID.
+func (d *Detector) ExtractTrackingIDs(body string) []models.TrackingID {
+	seen := make(map[string]bool)
+	var ids []models.TrackingID
+	for _, t := range d.trackers {
+		for _, re := range t.compiled {
+			for _, m := range re.FindAllStringSubmatch(body, -1) {
+				if len(m) < 2 || m[1] == "" || seen[t.Service+"\x00"+m[1]] {
+					continue
+				}
+				seen[t.Service+"\x00"+m[1]] = true
+				ids = append(ids, models.TrackingID{Service: t.Service, Type: t.Type, ID: m[1]})
+			}
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].Service != ids[j].Service {
+			return ids[i].Service < ids[j].Service
+		}
+		return ids[i].ID < ids[j].ID
+	})
+	return ids
+}
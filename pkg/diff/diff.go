@@ -0,0 +1,264 @@
+// Package diff compares two scan results and lists what changed between
+// them: DNS records, the certificate, technologies and their versions,
+// tracking IDs, CDN and WAF, security headers, subdomains, known
+// vulnerabilities and exposed paths.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/runner"
+)
+
+// Load reads a scan result saved as JSON.
+func Load(path string) (*models.ScanResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan result: %w", err)
+	}
+	var result models.ScanResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("%s: not a scan result: %w", path, err)
+	}
+	if result.Domain == "" {
+		return nil, fmt.Errorf("%s: not a scan result", path)
+	}
+	return &result, nil
+}
+
+// Compare lists the changes from old to new. Sections missing from either
+// scan, such as a check that failed or was not enabled, are not compared,
+// nor are subdomain lists cut short by the display limit. Sections that
+// may legitimately be empty are compared only when the module producing
+// them succeeded in both scans.
+// Changes are grouped by section in report order and sorted by item.
+func Compare(old, new *models.ScanResult) *models.ScanDiff {
+	d := &models.ScanDiff{
+		Domain:       new.Domain,
+		OldTimestamp: old.Timestamp,
+		NewTimestamp: new.Timestamp,
+		Changes:      []models.Change{},
+	}
+	if old.Domain != new.Domain {
+		d.OldDomain = old.Domain
+	}
+	c := &comparison{diff: d}
+
+	if old.HTTP != nil && new.HTTP != nil {
+		c.value("http.status", strconv.Itoa(old.HTTP.StatusCode), strconv.Itoa(new.HTTP.StatusCode))
+		c.value("http.server", old.HTTP.Server, new.HTTP.Server)
+		c.value("http.protocol", old.HTTP.Protocol, new.HTTP.Protocol)
+	}
+	if old.Page != nil && new.Page != nil {
+		c.value("page.title", old.Page.Title, new.Page.Title)
+	}
+	if old.Favicon != nil && new.Favicon != nil {
+		c.value("favicon.mmh3", strconv.Itoa(int(old.Favicon.MMH3)), strconv.Itoa(int(new.Favicon.MMH3)))
+	}
+
+	if old.DNS != nil && new.DNS != nil {
+		c.set("dns.a", old.DNS.A, new.DNS.A)
+		c.set("dns.aaaa", old.DNS.AAAA, new.DNS.AAAA)
+		c.set("dns.cname", old.DNS.CNAME, new.DNS.CNAME)
+		c.set("dns.mx", old.DNS.MX, new.DNS.MX)
+		c.set("dns.ns", old.DNS.NS, new.DNS.NS)
+		c.set("dns.txt", old.DNS.TXT, new.DNS.TXT)
+	}
+
+	if old.TLS != nil && new.TLS != nil {
+		c.value("tls.version", old.TLS.Version, new.TLS.Version)
+		if old.TLS.Fingerprint != new.TLS.Fingerprint {
+			c.add("tls.certificate", models.ChangeChanged, new.TLS.Subject,
+				describeCertificate(old.TLS), describeCertificate(new.TLS))
+		}
+		c.set("tls.sans", old.TLS.SANs, new.TLS.SANs)
+	}
+
+	if old.Technologies != nil && new.Technologies != nil {
+		c.versions("technologies", technologyVersions(old.Technologies), technologyVersions(new.Technologies))
+	}
+	if bothRan(old, new, runner.ModuleVulns) {
+		c.versions("vulnerabilities", vulnerabilities(old.Vulnerabilities), vulnerabilities(new.Vulnerabilities))
+	}
+	if bothRan(old, new, runner.ModuleTech) {
+		c.set("tracking_ids", trackingIDs(old.TrackingIDs), trackingIDs(new.TrackingIDs))
+	}
+
+	if bothRan(old, new, runner.ModuleHTTP, runner.ModuleDNS) {
+		c.set("cdns", cdnNames(old), cdnNames(new))
+	}
+	if bothRan(old, new, runner.ModuleHTTP) {
+		c.value("waf", old.WAF, new.WAF)
+		c.versions("security_headers", old.SecurityHeaders, new.SecurityHeaders)
+	}
+	if bothRan(old, new, runner.ModuleCloud) {
+		c.value("cloud_provider", old.CloudProvider, new.CloudProvider)
+	}
+
+	// A capped list only holds the first names found, so comparing it
+	// would report the names past the cap as removed or added.
+	if len(old.Subdomains) > 0 && len(new.Subdomains) > 0 && !subdomainsCapped(old) && !subdomainsCapped(new) {
+		c.set("subdomains", old.Subdomains, new.Subdomains)
+	}
+	if bothRan(old, new, runner.ModuleSensitiveFiles) {
+		c.set("exposures", exposures(old.Exposures), exposures(new.Exposures))
+	}
+	return d
+}
+
+// bothRan reports whether modules succeeded in both scans. An empty
+// section then means nothing was found rather than nothing was checked.
+func bothRan(old, new *models.ScanResult, modules ...string) bool {
+	return ran(old, modules) && ran(new, modules)
+}
+
+// ran reports whether modules succeeded in result. Reports saved before
+// stages were recorded have no status to go by and count as complete.
+func ran(result *models.ScanResult, modules []string) bool {
+	if result.Stages == nil {
+		return true
+	}
+	for _, name := range modules {
+		if st := result.Stages[name]; st == nil || st.Status != models.StageOK {
+			return false
+		}
+	}
+	return true
+}
+
+// subdomainsCapped reports whether result lists fewer subdomains than it
+// found, because of Scanner.MaxSubdomainsDisplay.
+func subdomainsCapped(result *models.ScanResult) bool {
+	return result.SubdomainsFound > len(result.Subdomains)
+}
+
+// comparison accumulates changes.
+type comparison struct {
+	diff *models.ScanDiff
+}
+
+func (c *comparison) add(section, kind, item, old, new string) {
+	c.diff.Changes = append(c.diff.Changes, models.Change{
+		Section: section,
+		Kind:    kind,
+		Item:    item,
+		Old:     old,
+		New:     new,
+	})
+}
+
+// value records a change of a single value.
+func (c *comparison) value(section, old, new string) {
+	switch {
+	case old == new:
+	case old == "":
+		c.add(section, models.ChangeAdded, new, "", new)
+	case new == "":
+		c.add(section, models.ChangeRemoved, old, old, "")
+	default:
+		c.add(section, models.ChangeChanged, new, old, new)
+	}
+}
+
+// set records the values added to and removed from a list; order and
+// duplicates are ignored.
+func (c *comparison) set(section string, old, new []string) {
+	m := make(map[string]string, len(old))
+	for _, v := range old {
+		m[v] = ""
+	}
+	n := make(map[string]string, len(new))
+	for _, v := range new {
+		n[v] = ""
+	}
+	c.versions(section, m, n)
+}
+
+// versions records the keys added and removed and the keys whose value
+// changed, e.g. technologies and their versions.
+func (c *comparison) versions(section string, old, new map[string]string) {
+	keys := make([]string, 0, len(old)+len(new))
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		o, inOld := old[k]
+		n, inNew := new[k]
+		switch {
+		case !inOld:
+			c.add(section, models.ChangeAdded, k, "", n)
+		case !inNew:
+			c.add(section, models.ChangeRemoved, k, o, "")
+		case o != n:
+			c.add(section, models.ChangeChanged, k, o, n)
+		}
+	}
+}
+
+// technologyVersions maps detected technologies to their versions.
+func technologyVersions(tech *models.Technologies) map[string]string {
+	m := make(map[string]string, len(tech.Items))
+	for _, item := range tech.Items {
+		m[item.Name] = item.Version
+	}
+	return m
+}
+
+// vulnerabilities maps vulnerability IDs to the affected technology.
+func vulnerabilities(vulns []models.Vulnerability) map[string]string {
+	m := make(map[string]string, len(vulns))
+	for _, v := range vulns {
+		m[v.ID] = strings.TrimSpace(v.Technology + " " + v.Version)
+	}
+	return m
+}
+
+func trackingIDs(ids []models.TrackingID) []string {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, id.Service+" "+id.ID)
+	}
+	return values
+}
+
+func exposures(list []models.Exposure) []string {
+	values := make([]string, 0, len(list))
+	for _, e := range list {
+		values = append(values, e.Path)
+	}
+	return values
+}
+
+// cdnNames returns the CDNs of a scan, falling back to the single CDN field
+// of older results.
+func cdnNames(result *models.ScanResult) []string {
+	if len(result.CDNs) == 0 {
+		if result.CDN == "" {
+			return nil
+		}
+		return []string{result.CDN}
+	}
+	names := make([]string, 0, len(result.CDNs))
+	for _, cdn := range result.CDNs {
+		names = append(names, cdn.Name)
+	}
+	return names
+}
+
+// describeCertificate summarizes a certificate for a change record.
+func describeCertificate(t *models.TLSAnalysis) string {
+	return fmt.Sprintf("issued by %s, expires %s", t.Issuer, t.NotAfter.Format("2006-01-02"))
+}
@@ -0,0 +1,149 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+func TestCompareSubdomains(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new *models.ScanResult
+		want     []string
+	}{
+		{
+			name: "complete lists",
+			old:  &models.ScanResult{Subdomains: []string{"a.example.com", "b.example.com"}, SubdomainsFound: 2},
+			new:  &models.ScanResult{Subdomains: []string{"b.example.com", "c.example.com"}, SubdomainsFound: 2},
+			want: []string{"removed a.example.com", "added c.example.com"},
+		},
+		{
+			name: "reports without a count",
+			old:  &models.ScanResult{Subdomains: []string{"a.example.com"}},
+			new:  &models.ScanResult{Subdomains: []string{"b.example.com"}},
+			want: []string{"removed a.example.com", "added b.example.com"},
+		},
+		{
+			name: "old list capped",
+			old:  &models.ScanResult{Subdomains: []string{"a.example.com"}, SubdomainsFound: 40},
+			new:  &models.ScanResult{Subdomains: []string{"b.example.com"}, SubdomainsFound: 1},
+		},
+		{
+			name: "new list capped",
+			old:  &models.ScanResult{Subdomains: []string{"a.example.com"}, SubdomainsFound: 1},
+			new:  &models.ScanResult{Subdomains: []string{"b.example.com"}, SubdomainsFound: 40},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range Compare(tt.old, tt.new).Changes {
+				if c.Section == "subdomains" {
+					got = append(got, c.Kind+" "+c.Item)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subdomain changes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// stages marks modules as run with status.
+func stages(status string, modules ...string) map[string]*models.Stage {
+	m := make(map[string]*models.Stage, len(modules))
+	for _, name := range modules {
+		m[name] = &models.Stage{Status: status}
+	}
+	return m
+}
+
+func fullScan() *models.ScanResult {
+	return &models.ScanResult{
+		Domain: "example.com",
+		DNS:    &models.DNSAnalysis{A: []string{"192.0.2.1"}},
+		Stages: stages(models.StageOK, "http", "tech", "vulns", "dns", "tls", "cloud", "sensitive-files"),
+		TrackingIDs: []models.TrackingID{
+			{Service: "Google Analytics", ID: "G-ABC123"},
+		},
+		CDNs:            []models.CDNDetection{{Name: "Cloudflare"}},
+		WAF:             "Cloudflare",
+		CloudProvider:   "AWS",
+		SecurityHeaders: map[string]string{"strict-transport-security": "max-age=31536000"},
+		Exposures:       []models.Exposure{{Path: "/.git/HEAD"}},
+		Vulnerabilities: []models.Vulnerability{{ID: "CVE-2024-0001", Technology: "jQuery"}},
+	}
+}
+
+func TestComparePartialScan(t *testing.T) {
+	partial := &models.ScanResult{
+		Domain: "example.com",
+		DNS:    &models.DNSAnalysis{A: []string{"192.0.2.2"}},
+		Stages: stages(models.StageOK, "dns", "tls"),
+	}
+	for name, st := range stages(models.StageSkipped, "http", "tech", "vulns", "cloud", "sensitive-files") {
+		partial.Stages[name] = st
+	}
+
+	tests := []struct {
+		name     string
+		old, new *models.ScanResult
+		want     []string
+	}{
+		{
+			name: "partial scan against full scan",
+			old:  fullScan(),
+			new:  partial,
+			want: []string{"dns.a removed 192.0.2.1", "dns.a added 192.0.2.2"},
+		},
+		{
+			name: "full scan against partial scan",
+			old:  partial,
+			new:  fullScan(),
+			want: []string{"dns.a added 192.0.2.1", "dns.a removed 192.0.2.2"},
+		},
+		{
+			name: "full scans that found nothing",
+			old:  fullScan(),
+			new: &models.ScanResult{
+				Domain: "example.com",
+				DNS:    &models.DNSAnalysis{A: []string{"192.0.2.1"}},
+				Stages: stages(models.StageOK, "http", "tech", "vulns", "dns", "tls", "cloud", "sensitive-files"),
+			},
+			want: []string{
+				"vulnerabilities removed CVE-2024-0001",
+				"tracking_ids removed Google Analytics G-ABC123",
+				"cdns removed Cloudflare",
+				"waf removed Cloudflare",
+				"security_headers removed strict-transport-security",
+				"cloud_provider removed AWS",
+				"exposures removed /.git/HEAD",
+			},
+		},
+		{
+			name: "failed module",
+			old:  fullScan(),
+			new: func() *models.ScanResult {
+				r := fullScan()
+				r.Stages["sensitive-files"].Status = models.StageFailed
+				r.Exposures = nil
+				return r
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range Compare(tt.old, tt.new).Changes {
+				got = append(got, c.Section+" "+c.Kind+" "+c.Item)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,15 +3,18 @@ package dns
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/javicosvml/rankle-go/internal/config"
 	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/stage"
 )
 
 // Resolver handles DNS operations.
@@ -46,55 +49,121 @@ func New(cfg *config.Config) *Resolver {
 	}
 }
 
-// Analyze performs comprehensive DNS analysis.
-func (r *Resolver) Analyze(domain string) (*models.DNSAnalysis, error) {
+// Record types looked up by Analyze, in lookup order.
+var recordTypes = []string{"a", "aaaa", "cname", "mx", "ns", "txt"}
+
+// Analyze looks up the A, AAAA, CNAME, MX, NS and TXT records of domain.
+// The outcome of each lookup is returned by record type; a name without
+// records of a type is not a failure. The error is set when every lookup
+// failed, e.g. because the name server cannot be reached, or when the
+// name has no A, AAAA or CNAME record, so there is nothing to scan.
+func (r *Resolver) Analyze(domain string) (*models.DNSAnalysis, map[string]*models.Stage, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), r.config.DNS.Timeout)
 	defer cancel()
 
 	analysis := &models.DNSAnalysis{}
+	stages := make(map[string]*models.Stage, len(recordTypes))
+	// missing holds the failed stage of lookups that found no records.
+	missing := make(map[string]*models.Stage)
+	var firstErr, missingErr error
+	for _, rtype := range recordTypes {
+		start := time.Now()
+		err := r.lookup(ctx, domain, rtype, analysis)
+		st := stage.Done(start, err)
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			missing[rtype] = st
+			if missingErr == nil {
+				missingErr = err
+			}
+			st = &models.Stage{Status: models.StageOK, DurationMS: st.DurationMS, Reason: dnsErr.Err}
+		}
+		stages[rtype] = st
+		if stage.Failed(st) && firstErr == nil {
+			firstErr = err
+		}
+	}
 
-	// Resolve A records
-	if ips, err := r.resolver.LookupIP(ctx, "ip4", domain); err == nil {
-		for _, ip := range ips {
-			analysis.A = append(analysis.A, ip.String())
+	// The resolver reports a missing name (NXDOMAIN) and a name without
+	// records of the type alike; with no address records either way the
+	// domain does not resolve.
+	if missing["a"] != nil && missing["aaaa"] != nil && missing["cname"] != nil {
+		for _, rtype := range []string{"a", "aaaa", "cname"} {
+			stages[rtype] = missing[rtype]
 		}
+		return analysis, stages, fmt.Errorf("%s has no A, AAAA or CNAME record: %w", domain, missingErr)
 	}
 
-	// Resolve AAAA records
-	if ips, err := r.resolver.LookupIP(ctx, "ip6", domain); err == nil {
-		for _, ip := range ips {
-			analysis.AAAA = append(analysis.AAAA, ip.String())
+	for _, st := range stages {
+		if !stage.Failed(st) {
+			return analysis, stages, nil
 		}
 	}
+	return analysis, stages, firstErr
+}
+
+// lookup resolves the records of one type into analysis.
+func (r *Resolver) lookup(ctx context.Context, domain, rtype string, analysis *models.DNSAnalysis) error {
+	switch rtype {
+	case "a":
+		ips, err := r.resolver.LookupIP(ctx, "ip4", domain)
+		if err != nil {
+			return err
+		}
+		analysis.A = ipStrings(ips)
+
+	case "aaaa":
+		ips, err := r.resolver.LookupIP(ctx, "ip6", domain)
+		if err != nil {
+			return err
+		}
+		analysis.AAAA = ipStrings(ips)
 
-	// Resolve CNAME
-	if cname, err := r.resolver.LookupCNAME(ctx, domain); err == nil {
+	case "cname":
+		cname, err := r.resolver.LookupCNAME(ctx, domain)
+		if err != nil {
+			return err
+		}
 		if cname != domain+"." && cname != "" {
 			analysis.CNAME = append(analysis.CNAME, strings.TrimSuffix(cname, "."))
 		}
-	}
 
-	// Resolve MX records
-	if mxs, err := r.resolver.LookupMX(ctx, domain); err == nil {
+	case "mx":
+		mxs, err := r.resolver.LookupMX(ctx, domain)
+		if err != nil {
+			return err
+		}
 		for _, mx := range mxs {
 			analysis.MX = append(analysis.MX, fmt.Sprintf("%s (priority: %d)",
 				strings.TrimSuffix(mx.Host, "."), mx.Pref))
 		}
-	}
 
-	// Resolve NS records
-	if nss, err := r.resolver.LookupNS(ctx, domain); err == nil {
+	case "ns":
+		nss, err := r.resolver.LookupNS(ctx, domain)
+		if err != nil {
+			return err
+		}
 		for _, ns := range nss {
 			analysis.NS = append(analysis.NS, strings.TrimSuffix(ns.Host, "."))
 		}
-	}
 
-	// Resolve TXT records
-	if txts, err := r.resolver.LookupTXT(ctx, domain); err == nil {
+	case "txt":
+		txts, err := r.resolver.LookupTXT(ctx, domain)
+		if err != nil {
+			return err
+		}
 		analysis.TXT = txts
 	}
+	return nil
+}
 
-	return analysis, nil
+// ipStrings formats IP addresses.
+func ipStrings(ips []net.IP) []string {
+	list := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		list = append(list, ip.String())
+	}
+	return list
 }
 
 // LookupIP resolves domain to IP addresses.
@@ -115,6 +184,23 @@ func (r *Resolver) LookupIP(domain string) ([]string, error) {
 	return result, nil
 }
 
+// LookupCNAME returns the canonical name of domain without the trailing
+// dot, or "" when domain is not an alias.
+func (r *Resolver) LookupCNAME(domain string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.DNS.Timeout)
+	defer cancel()
+
+	cname, err := r.resolver.LookupCNAME(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	cname = strings.TrimSuffix(cname, ".")
+	if strings.EqualFold(cname, strings.TrimSuffix(domain, ".")) {
+		return "", nil
+	}
+	return cname, nil
+}
+
 // ReverseLookup performs reverse DNS lookup.
 func (r *Resolver) ReverseLookup(ip string) ([]string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), r.config.DNS.Timeout)
@@ -184,6 +270,7 @@ func (r *Resolver) EnumerateSubdomains(domain string) ([]string, error) {
 	for subdomain := range subdomainMap {
 		subdomains = append(subdomains, subdomain)
 	}
+	sort.Strings(subdomains)
 
 	return subdomains, nil
 }
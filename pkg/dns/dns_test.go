@@ -0,0 +1,117 @@
+package dns
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/stage"
+)
+
+const (
+	typeA    = 1
+	rcodeOK  = 0
+	rcodeNX  = 3
+	flagsAns = 0x8180 // response, recursion desired and available
+)
+
+// fakeServer answers DNS queries over UDP. With exists set, A queries get
+// 192.0.2.1 and other types no records; otherwise every name is NXDOMAIN.
+func fakeServer(t *testing.T, exists bool) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if reply := answer(buf[:n], exists); reply != nil {
+				conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// answer builds the reply to query.
+func answer(query []byte, exists bool) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+	end := 12
+	for end < len(query) && query[end] != 0 {
+		end += int(query[end]) + 1
+	}
+	end += 5 // root label, type and class
+	if end > len(query) {
+		return nil
+	}
+	qtype := binary.BigEndian.Uint16(query[end-4:])
+
+	reply := make([]byte, 12, 64)
+	copy(reply, query[:2])
+	rcode := uint16(rcodeNX)
+	if exists {
+		rcode = rcodeOK
+	}
+	binary.BigEndian.PutUint16(reply[2:], flagsAns|rcode)
+	binary.BigEndian.PutUint16(reply[4:], 1)
+	reply = append(reply, query[12:end]...)
+
+	if exists && qtype == typeA {
+		binary.BigEndian.PutUint16(reply[6:], 1)
+		reply = append(reply,
+			0xc0, 12, // name: pointer to the question
+			0, typeA, 0, 1, // type A, class IN
+			0, 0, 0, 60, // TTL
+			0, 4, 192, 0, 2, 1)
+	}
+	return reply
+}
+
+func TestAnalyzeMissingName(t *testing.T) {
+	tests := []struct {
+		name       string
+		exists     bool
+		wantErr    bool
+		wantStatus string
+		wantClass  string
+	}{
+		{"name resolves", true, false, models.StageOK, ""},
+		{"NXDOMAIN", false, true, models.StageFailed, stage.ClassNoSuchHost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.DNS.Nameservers = []string{fakeServer(t, tt.exists)}
+
+			analysis, stages, err := New(cfg).Analyze("example.test")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Analyze() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && stage.Classify(err) != stage.ClassNoSuchHost {
+				t.Errorf("Analyze() error class = %s, want %s", stage.Classify(err), stage.ClassNoSuchHost)
+			}
+			for _, rtype := range []string{"a", "aaaa", "cname"} {
+				if st := stages[rtype]; st.Status != tt.wantStatus || st.ErrorClass != tt.wantClass {
+					t.Errorf("stage %s = %s/%s, want %s/%s", rtype, st.Status, st.ErrorClass, tt.wantStatus, tt.wantClass)
+				}
+			}
+			if st := stages["mx"]; st.Status != models.StageOK {
+				t.Errorf("stage mx = %s, want %s", st.Status, models.StageOK)
+			}
+			if tt.exists && (len(analysis.A) != 1 || analysis.A[0] != "192.0.2.1") {
+				t.Errorf("A = %v, want [192.0.2.1]", analysis.A)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// Package exposure checks a site for sensitive files and admin interfaces
+// left reachable: version control metadata, environment files, debug
+// endpoints, backups and management panels.
+package exposure
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+)
+
+//go:embed data/paths.json
+var embeddedRules embed.FS
+
+const (
+	// maxProbeBody is how much of each candidate file is read; the
+	// signatures all appear near the start.
+	maxProbeBody = 64 << 10
+	// maxEvidence caps the matched text kept as evidence.
+	maxEvidence = 80
+	// concurrency bounds the number of requests in flight.
+	concurrency = 4
+)
+
+// Rule describes one path to check. A hit needs an accepted status (200 by
+// default), a response that differs from the site's soft-404 baseline and,
+// when set, a body matching Match and a media type matching ContentType.
+// "{host}" in Path is replaced with the site's host name.
+type Rule struct {
+	Path        string `json:"path"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Severity    string `json:"severity"`
+	Status      []int  `json:"status,omitempty"`
+	Match       string `json:"match,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+
+	match       *regexp.Regexp
+	contentType *regexp.Regexp
+}
+
+// Checker probes a site with a list of rules.
+type Checker struct {
+	scan  *scanner.Scanner
+	rules []*Rule
+}
+
+// New returns a Checker with the embedded rule list.
+func New(scan *scanner.Scanner) *Checker {
+	data, err := embeddedRules.ReadFile("data/paths.json")
+	if err != nil {
+		panic(err)
+	}
+	c := &Checker{scan: scan}
+	if err := c.add(data); err != nil {
+		// The embedded list ships with the binary; failing to parse it is a
+		// packaging bug, not a runtime condition.
+		panic(err)
+	}
+	return c
+}
+
+// Load adds rules from a JSON file in the embedded list's format. A rule
+// for a path that is already listed replaces it.
+func (c *Checker) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read sensitive path list: %w", err)
+	}
+	if err := c.add(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Len returns the number of rules.
+func (c *Checker) Len() int {
+	return len(c.rules)
+}
+
+// add parses and compiles a rule list.
+func (c *Checker) add(data []byte) error {
+	var rules []*Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("failed to parse sensitive path list: %w", err)
+	}
+
+	for _, r := range rules {
+		if !strings.HasPrefix(r.Path, "/") {
+			return fmt.Errorf("path %q must start with /", r.Path)
+		}
+		if r.Severity == "" {
+			r.Severity = models.SeverityInfo
+		}
+		if len(r.Status) == 0 {
+			r.Status = []int{200}
+		}
+		var err error
+		if r.Match != "" {
+			if r.match, err = regexp.Compile(r.Match); err != nil {
+				return fmt.Errorf("invalid match for %s: %w", r.Path, err)
+			}
+		}
+		if r.ContentType != "" {
+			if r.contentType, err = regexp.Compile(r.ContentType); err != nil {
+				return fmt.Errorf("invalid content_type for %s: %w", r.Path, err)
+			}
+		}
+		c.replace(r)
+	}
+	return nil
+}
+
+// replace adds r, replacing any rule with the same path.
+func (c *Checker) replace(r *Rule) {
+	for i, existing := range c.rules {
+		if existing.Path == r.Path {
+			c.rules[i] = r
+			return
+		}
+	}
+	c.rules = append(c.rules, r)
+}
+
+// Check probes every rule under origin (scheme and host) and returns the
+// hits, most severe first.
+func (c *Checker) Check(origin string) []models.Exposure {
+	origin = strings.TrimSuffix(origin, "/")
+	host := origin
+	if u, err := url.Parse(origin); err == nil {
+		host = u.Hostname()
+	}
+
+	// Build the soft-404 baseline before the workers need it.
+	c.scan.Soft404Baseline(origin)
+
+	results := make([]*models.Exposure, len(c.rules))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, rule := range c.rules {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, rule *Rule) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = c.check(origin, host, rule)
+		}(i, rule)
+	}
+	wg.Wait()
+
+	var exposures []models.Exposure
+	for _, e := range results {
+		if e != nil {
+			exposures = append(exposures, *e)
+		}
+	}
+	sort.SliceStable(exposures, func(i, j int) bool {
+		ri, rj := models.SeverityRank(exposures[i].Severity), models.SeverityRank(exposures[j].Severity)
+		if ri != rj {
+			return ri > rj
+		}
+		return exposures[i].Path < exposures[j].Path
+	})
+	return exposures
+}
+
+// check probes a single rule.
+func (c *Checker) check(origin, host string, rule *Rule) *models.Exposure {
+	path := strings.ReplaceAll(rule.Path, "{host}", host)
+	resp, err := c.scan.FetchLimited(origin+path, maxProbeBody)
+	if err != nil || !slices.Contains(rule.Status, resp.StatusCode) || c.scan.IsSoft404(resp) {
+		return nil
+	}
+
+	exposure := &models.Exposure{
+		Path:       path,
+		URL:        resp.URL,
+		Name:       rule.Name,
+		Category:   rule.Category,
+		Severity:   rule.Severity,
+		StatusCode: resp.StatusCode,
+	}
+
+	if rule.contentType != nil {
+		mediaType := resp.MediaType()
+		if !rule.contentType.MatchString(mediaType) {
+			return nil
+		}
+		exposure.Evidence = "Content-Type: " + mediaType
+	}
+	if rule.match != nil {
+		match := rule.match.FindString(resp.Body)
+		if match == "" {
+			// Panels behind basic auth name themselves in the realm.
+			match = rule.match.FindString(resp.Header.Get("WWW-Authenticate"))
+		}
+		if match == "" {
+			return nil
+		}
+		exposure.Evidence = evidence(match)
+	}
+	return exposure
+}
+
+// evidence trims a match for display.
+func evidence(match string) string {
+	match = strings.TrimSpace(match)
+	if len(match) > maxEvidence {
+		match = strings.ToValidUTF8(match[:maxEvidence], "") + "…"
+	}
+	return match
+}
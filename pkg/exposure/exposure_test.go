@@ -0,0 +1,185 @@
+package exposure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+)
+
+// testRules covers each way a rule can gate a hit.
+const testRules = `[
+  { "path": "/.git/HEAD", "name": "Git repository", "severity": "high", "match": "^ref: refs/" },
+  { "path": "/.env", "name": "Environment file", "severity": "critical", "match": "(?m)^[A-Z_]+=" },
+  { "path": "/{host}.zip", "name": "Site backup", "severity": "high", "content_type": "^application/(?:zip|octet-stream)$" },
+  { "path": "/backup.sql", "name": "Database dump", "severity": "high", "content_type": "^application/sql$" },
+  { "path": "/manage", "name": "Jenkins", "severity": "medium", "status": [200, 401], "match": "(?i)jenkins" },
+  { "path": "/server-status", "name": "Apache status", "severity": "medium" },
+  { "path": "/robots.txt", "name": "Robots file" }
+]`
+
+// newChecker returns a Checker holding only rules.
+func newChecker(t *testing.T, rules string) *Checker {
+	cfg := config.Default()
+	cfg.HTTP.MaxRetries = 0
+	c := &Checker{scan: scanner.New(cfg)}
+	if err := c.add([]byte(rules)); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// site serves pages by path and 404 for anything else.
+func site(t *testing.T, pages map[string]http.HandlerFunc) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if page, ok := pages[r.URL.Path]; ok {
+			page(w, r)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// text returns a handler answering with status, content type and body.
+func text(status int, contentType, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr string
+	}{
+		{"valid", `[{"path": "/.env"}]`, ""},
+		{"not JSON", `{`, "failed to parse sensitive path list"},
+		{"relative path", `[{"path": ".env"}]`, `path ".env" must start with /`},
+		{"bad match", `[{"path": "/.env", "match": "("}]`, "invalid match for /.env"},
+		{"bad content type", `[{"path": "/x.zip", "content_type": "["}]`, "invalid content_type for /x.zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Checker{}).add([]byte(tt.rules))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("add() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("add() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddDefaults(t *testing.T) {
+	c := &Checker{}
+	if err := c.add([]byte(`[{"path": "/.env"}]`)); err != nil {
+		t.Fatal(err)
+	}
+	r := c.rules[0]
+	if r.Severity != models.SeverityInfo || !reflect.DeepEqual(r.Status, []int{200}) {
+		t.Errorf("defaults = severity %q, status %v; want %q, [200]", r.Severity, r.Status, models.SeverityInfo)
+	}
+}
+
+func TestLoadReplacesPath(t *testing.T) {
+	c := &Checker{}
+	if err := c.add([]byte(`[{"path": "/.env", "name": "Old"}, {"path": "/.git/HEAD"}]`)); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "paths.json")
+	if err := os.WriteFile(file, []byte(`[{"path": "/.env", "name": "New"}, {"path": "/debug"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Load(file); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	var got []string
+	for _, r := range c.rules {
+		got = append(got, r.Path+" "+r.Name)
+	}
+	want := []string{"/.env New", "/.git/HEAD ", "/debug "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rules = %q, want %q", got, want)
+	}
+
+	if err := c.Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load() of a missing file succeeded")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := site(t, map[string]http.HandlerFunc{
+		"/.git/HEAD":     text(200, "text/plain", "ref: refs/heads/main\n"),
+		"/.env":          text(200, "text/html", "<html><title>Not here</title></html>"),
+		"/127.0.0.1.zip": text(200, "application/zip", "PK\x03\x04"),
+		"/backup.sql":    text(200, "text/html", "<html>dump</html>"),
+		"/server-status": text(403, "text/html", "Forbidden"),
+		"/robots.txt":    text(200, "text/plain", "User-agent: *\n"),
+		"/manage": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Jenkins"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		},
+	})
+
+	var got []string
+	for _, e := range newChecker(t, testRules).Check(srv.URL) {
+		got = append(got, e.Severity+" "+e.Path+" "+e.Evidence)
+	}
+	want := []string{
+		"high /.git/HEAD ref: refs/",
+		"high /127.0.0.1.zip Content-Type: application/zip",
+		"medium /manage Jenkins",
+		"info /robots.txt ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() = %q\nwant %q", got, want)
+	}
+}
+
+func TestCheckCatchAll(t *testing.T) {
+	// Every path answers with content that satisfies the rules' patterns.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("ref: refs/heads/main\nAPP_KEY=secret\nJenkins\n"))
+	}))
+	defer srv.Close()
+
+	if got := newChecker(t, testRules).Check(srv.URL); len(got) != 0 {
+		t.Errorf("Check() on a catch-all site = %+v, want no hits", got)
+	}
+}
+
+func TestEvidence(t *testing.T) {
+	long := strings.Repeat("é", 50)
+	if got := evidence("  short  "); got != "short" {
+		t.Errorf("evidence() = %q, want %q", got, "short")
+	}
+	got := evidence(long)
+	if !strings.HasSuffix(got, "…") || len(got) > maxEvidence+len("…") || !strings.HasPrefix(long, strings.TrimSuffix(got, "…")) {
+		t.Errorf("evidence(long) = %q", got)
+	}
+}
+
+func TestNewLoadsEmbeddedRules(t *testing.T) {
+	if n := New(scanner.New(nil)).Len(); n == 0 {
+		t.Error("New() has no rules")
+	}
+}
@@ -0,0 +1,101 @@
+package httpsec
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// attackerDomain is the domain used for crafted origins. It is reserved
+// (RFC 2606) and can never belong to anyone. The suffix probe cannot use it
+// and sends "rankle<domain>" instead; origins are never contacted.
+const attackerDomain = "rankle-cors.example"
+
+// corsProbe is one crafted Origin header and the severity of trusting it.
+type corsProbe struct {
+	name     string
+	origin   string
+	severity string
+	title    string
+}
+
+// corsProbes builds the origins tested against domain: an arbitrary site,
+// the null origin, look-alike domains that defeat prefix or suffix checks,
+// and the plain-HTTP version of the site itself.
+func corsProbes(domain string) []corsProbe {
+	return []corsProbe{
+		{"arbitrary", "https://" + attackerDomain, models.SeverityHigh, "arbitrary origins are trusted"},
+		{"null", "null", models.SeverityHigh, "the null origin is trusted"},
+		{"prefix", "https://" + domain + "." + attackerDomain, models.SeverityHigh, "origins starting with the domain are trusted"},
+		{"suffix", "https://rankle" + domain, models.SeverityHigh, "origins ending with the domain are trusted"},
+		{"insecure", "http://" + domain, models.SeverityLow, "the plain-HTTP origin is trusted"},
+	}
+}
+
+// checkCORS sends each crafted origin and flags policies that reflect it.
+// Reflection with credentials allowed lets another site read authenticated
+// responses; without credentials the impact is limited to public data.
+func (c *Checker) checkCORS(origin, domain string, result *models.HTTPSecurity) {
+	url := origin + "/"
+	wildcardReported := false
+
+	for _, probe := range corsProbes(domain) {
+		header := http.Header{}
+		header.Set("Origin", probe.origin)
+		resp, err := c.scan.Request(http.MethodGet, url, header, maxResponseBody)
+		if err != nil {
+			continue
+		}
+
+		test := models.CORSTest{
+			Name:             probe.name,
+			Origin:           probe.origin,
+			AllowOrigin:      resp.Header.Get("Access-Control-Allow-Origin"),
+			AllowCredentials: strings.EqualFold(resp.Header.Get("Access-Control-Allow-Credentials"), "true"),
+		}
+		result.CORS = append(result.CORS, test)
+
+		evidence := fmt.Sprintf("Origin: %s -> Access-Control-Allow-Origin: %s", test.Origin, test.AllowOrigin)
+		if test.AllowCredentials {
+			evidence += ", Access-Control-Allow-Credentials: true"
+		}
+
+		switch {
+		case test.AllowOrigin == probe.origin:
+			severity := probe.severity
+			if !test.AllowCredentials {
+				severity = lowerSeverity(severity)
+			}
+			result.Findings = append(result.Findings, models.Finding{
+				Check:    "cors-" + probe.name,
+				Severity: severity,
+				Title:    "CORS policy reflects the Origin header: " + probe.title,
+				Evidence: evidence,
+			})
+		case test.AllowOrigin == "*" && !wildcardReported:
+			wildcardReported = true
+			result.Findings = append(result.Findings, models.Finding{
+				Check:    "cors-wildcard",
+				Severity: models.SeverityInfo,
+				Title:    "CORS allows any origin without credentials (Access-Control-Allow-Origin: *)",
+				Evidence: evidence,
+			})
+		}
+	}
+}
+
+// lowerSeverity returns the next lower severity level.
+func lowerSeverity(severity string) string {
+	switch severity {
+	case models.SeverityCritical:
+		return models.SeverityHigh
+	case models.SeverityHigh:
+		return models.SeverityMedium
+	case models.SeverityMedium:
+		return models.SeverityLow
+	default:
+		return models.SeverityInfo
+	}
+}
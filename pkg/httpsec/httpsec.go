@@ -0,0 +1,153 @@
+// Package httpsec actively tests a site's HTTP method handling and CORS
+// policy. Write methods are only sent to a random path that does not exist,
+// and anything created there is deleted again.
+package httpsec
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+)
+
+const (
+	// maxResponseBody bounds the bodies read during the checks.
+	maxResponseBody = 64 << 10
+
+	methodPropfind = "PROPFIND"
+	traceHeader    = "X-Rankle-Trace"
+)
+
+// Checker runs the HTTP method and CORS checks.
+type Checker struct {
+	scan *scanner.Scanner
+}
+
+// New creates a Checker using the scanner's HTTP client.
+func New(scan *scanner.Scanner) *Checker {
+	return &Checker{scan: scan}
+}
+
+// Check tests the methods accepted by origin (scheme and host) and how it
+// answers cross-origin requests from crafted origins. domain is the target
+// host name used to build the look-alike origins.
+func (c *Checker) Check(origin, domain string) *models.HTTPSecurity {
+	origin = strings.TrimSuffix(origin, "/")
+	result := &models.HTTPSecurity{}
+
+	c.checkMethods(origin, result)
+	c.checkCORS(origin, domain, result)
+
+	models.SortFindings(result.Findings)
+	return result
+}
+
+// checkMethods sends OPTIONS, TRACE and PROPFIND to the site root and PUT
+// and DELETE to a random path.
+func (c *Checker) checkMethods(origin string, result *models.HTTPSecurity) {
+	root := origin + "/"
+
+	if resp, ok := c.send(http.MethodOptions, root, nil, result); ok {
+		for _, header := range []string{"Allow", "Public"} {
+			for _, method := range strings.Split(resp.Header.Get(header), ",") {
+				if method = strings.ToUpper(strings.TrimSpace(method)); method != "" && !slices.Contains(result.AllowedMethods, method) {
+					result.AllowedMethods = append(result.AllowedMethods, method)
+				}
+			}
+		}
+	}
+
+	token := scanner.RandomToken()
+	traced := http.Header{}
+	traced.Set(traceHeader, token)
+	if resp, ok := c.send(http.MethodTrace, root, traced, result); ok && resp.StatusCode == http.StatusOK {
+		enabled := strings.Contains(resp.Body, token)
+		result.Methods[len(result.Methods)-1].Enabled = enabled
+		if enabled {
+			result.Findings = append(result.Findings, models.Finding{
+				Check:    "trace",
+				Severity: models.SeverityMedium,
+				Title:    "TRACE is enabled and echoes request headers (cross-site tracing)",
+				Evidence: fmt.Sprintf("TRACE %s returned the %s header", root, traceHeader),
+			})
+		}
+	}
+
+	depth := http.Header{}
+	depth.Set("Depth", "0")
+	if resp, ok := c.send(methodPropfind, root, depth, result); ok && resp.StatusCode == http.StatusMultiStatus {
+		result.Methods[len(result.Methods)-1].Enabled = true
+		result.Findings = append(result.Findings, models.Finding{
+			Check:    "webdav",
+			Severity: models.SeverityMedium,
+			Title:    "WebDAV is enabled (PROPFIND answered with 207 Multi-Status)",
+			Evidence: "PROPFIND " + root,
+		})
+	}
+
+	// Catch-all sites answer 200 to any method, so a write only counts once
+	// a GET confirms its effect.
+	target := origin + "/rankle-" + scanner.RandomToken() + ".txt"
+	created := false
+	if resp, ok := c.send(http.MethodPut, target, nil, result); ok && isSuccess(resp.StatusCode) && c.exists(target) {
+		created = true
+		result.Methods[len(result.Methods)-1].Enabled = true
+		result.Findings = append(result.Findings, models.Finding{
+			Check:    "put",
+			Severity: models.SeverityHigh,
+			Title:    "PUT creates files on the server",
+			Evidence: fmt.Sprintf("PUT %s returned %d and the file is now served", target, resp.StatusCode),
+		})
+	}
+	// DELETE also cleans up the file a successful PUT created.
+	if resp, ok := c.send(http.MethodDelete, target, nil, result); ok && created && isSuccess(resp.StatusCode) && !c.exists(target) {
+		result.Methods[len(result.Methods)-1].Enabled = true
+		result.Findings = append(result.Findings, models.Finding{
+			Check:    "delete",
+			Severity: models.SeverityHigh,
+			Title:    "DELETE removes files from the server",
+			Evidence: fmt.Sprintf("DELETE %s returned %d and the file is gone", target, resp.StatusCode),
+		})
+	}
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete, http.MethodTrace, methodPropfind} {
+		if slices.Contains(result.AllowedMethods, method) {
+			result.Findings = append(result.Findings, models.Finding{
+				Check:    "options",
+				Severity: models.SeverityInfo,
+				Title:    method + " is listed in the Allow header",
+				Evidence: "Allow: " + strings.Join(result.AllowedMethods, ", "),
+			})
+		}
+	}
+}
+
+// send issues one method test and records it. Methods are only marked
+// enabled by the specific checks above.
+func (c *Checker) send(method, url string, header http.Header, result *models.HTTPSecurity) (*scanner.Response, bool) {
+	resp, err := c.scan.Request(method, url, header, maxResponseBody)
+	if err != nil {
+		return nil, false
+	}
+	result.Methods = append(result.Methods, models.MethodTest{
+		Method:     method,
+		URL:        url,
+		StatusCode: resp.StatusCode,
+		Enabled:    method == http.MethodOptions && resp.StatusCode < 400,
+	})
+	return resp, true
+}
+
+// exists reports whether url is served as a real resource.
+func (c *Checker) exists(url string) bool {
+	resp, err := c.scan.FetchLimited(url, maxResponseBody)
+	return err == nil && resp.StatusCode == http.StatusOK && !c.scan.IsSoft404(resp)
+}
+
+// isSuccess reports whether a write method succeeded.
+func isSuccess(status int) bool {
+	return status == http.StatusOK || status == http.StatusCreated || status == http.StatusNoContent
+}
@@ -0,0 +1,258 @@
+package httpsec
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+)
+
+const testDomain = "example.com"
+
+// newChecker returns a Checker for a test server, without retries.
+func newChecker() *Checker {
+	cfg := config.Default()
+	cfg.HTTP.MaxRetries = 0
+	return New(scanner.New(cfg))
+}
+
+// findings renders findings as "check severity" strings in a stable order.
+func findings(list []models.Finding) []string {
+	out := make([]string, 0, len(list))
+	for _, f := range list {
+		out = append(out, f.Check+" "+f.Severity)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// corsServer answers every request with the CORS headers policy returns for
+// the request's Origin.
+func corsServer(t *testing.T, policy func(origin string) (allow string, credentials bool)) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allow, credentials := policy(r.Header.Get("Origin"))
+		if allow != "" {
+			w.Header().Set("Access-Control-Allow-Origin", allow)
+		}
+		if credentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		w.Write([]byte("<html><title>Home</title></html>"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCheckCORS(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy func(origin string) (string, bool)
+		want   []string
+	}{
+		{
+			name:   "reflects any origin with credentials",
+			policy: func(origin string) (string, bool) { return origin, true },
+			want: []string{
+				"cors-arbitrary high", "cors-insecure low", "cors-null high",
+				"cors-prefix high", "cors-suffix high",
+			},
+		},
+		{
+			name:   "reflects any origin without credentials",
+			policy: func(origin string) (string, bool) { return origin, false },
+			want: []string{
+				"cors-arbitrary medium", "cors-insecure info", "cors-null medium",
+				"cors-prefix medium", "cors-suffix medium",
+			},
+		},
+		{
+			name:   "wildcard is reported once",
+			policy: func(string) (string, bool) { return "*", false },
+			want:   []string{"cors-wildcard info"},
+		},
+		{
+			name: "suffix check",
+			policy: func(origin string) (string, bool) {
+				if strings.HasSuffix(origin, testDomain) {
+					return origin, true
+				}
+				return "", false
+			},
+			want: []string{"cors-insecure low", "cors-suffix high"},
+		},
+		{
+			name: "exact origin only",
+			policy: func(origin string) (string, bool) {
+				if origin == "https://"+testDomain {
+					return origin, true
+				}
+				return "https://" + testDomain, true
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := corsServer(t, tt.policy)
+			result := &models.HTTPSecurity{}
+			newChecker().checkCORS(srv.URL, testDomain, result)
+
+			if len(result.CORS) != len(corsProbes(testDomain)) {
+				t.Errorf("got %d CORS tests, want %d", len(result.CORS), len(corsProbes(testDomain)))
+			}
+			if got := findings(result.Findings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fileServer is a minimal WebDAV-like server: PUT stores a file, GET serves
+// it, DELETE removes it and unknown paths are 404.
+type fileServer struct {
+	mu    sync.Mutex
+	files map[string]bool
+
+	storePut  bool // PUT stores the file
+	deleteOK  bool // DELETE removes it
+	echoTrace bool // TRACE echoes the request headers
+	allow     string
+}
+
+func (f *fileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodOptions:
+		if f.allow != "" {
+			w.Header().Set("Allow", f.allow)
+		}
+	case http.MethodTrace:
+		if !f.echoTrace {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		r.Header.Write(w)
+	case methodPropfind:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	case http.MethodPut:
+		if f.storePut {
+			f.files[r.URL.Path] = true
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if f.deleteOK {
+			delete(f.files, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		if r.URL.Path != "/" && !f.files[r.URL.Path] {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("<html><title>Page</title></html>"))
+	}
+}
+
+func TestCheckMethods(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.Handler
+		want    []string
+	}{
+		{
+			name: "catch-all answers 200 to every method",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("<html><title>Welcome</title><p>Anything goes</p></html>"))
+			}),
+			want: []string{},
+		},
+		{
+			name: "catch-all with 207 to PROPFIND",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == methodPropfind {
+					w.WriteHeader(http.StatusMultiStatus)
+				}
+				w.Write([]byte("<html><title>Welcome</title></html>"))
+			}),
+			want: []string{"webdav medium"},
+		},
+		{
+			name:    "TRACE echoes headers",
+			handler: &fileServer{files: map[string]bool{}, echoTrace: true},
+			want:    []string{"trace medium"},
+		},
+		{
+			name:    "PUT and DELETE work",
+			handler: &fileServer{files: map[string]bool{}, storePut: true, deleteOK: true},
+			want:    []string{"delete high", "put high"},
+		},
+		{
+			name:    "PUT reports success without storing",
+			handler: &fileServer{files: map[string]bool{}, deleteOK: true},
+			want:    []string{},
+		},
+		{
+			name:    "DELETE does not remove the file",
+			handler: &fileServer{files: map[string]bool{}, storePut: true},
+			want:    []string{"put high"},
+		},
+		{
+			name:    "Allow header lists risky methods",
+			handler: &fileServer{files: map[string]bool{}, allow: "GET, HEAD, put, DELETE"},
+			want:    []string{"options info", "options info"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			result := &models.HTTPSecurity{}
+			newChecker().checkMethods(srv.URL, result)
+			if got := findings(result.Findings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSortsFindings(t *testing.T) {
+	srv := httptest.NewServer(&fileServer{files: map[string]bool{}, storePut: true, echoTrace: true, allow: "PUT"})
+	defer srv.Close()
+
+	result := newChecker().Check(srv.URL, testDomain)
+	var got []string
+	for _, f := range result.Findings {
+		got = append(got, f.Check)
+	}
+	want := []string{"put", "trace", "options"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("finding order = %q, want %q", got, want)
+	}
+}
+
+func TestLowerSeverity(t *testing.T) {
+	tests := map[string]string{
+		models.SeverityCritical: models.SeverityHigh,
+		models.SeverityHigh:     models.SeverityMedium,
+		models.SeverityMedium:   models.SeverityLow,
+		models.SeverityLow:      models.SeverityInfo,
+		models.SeverityInfo:     models.SeverityInfo,
+	}
+	for in, want := range tests {
+		if got := lowerSeverity(in); got != want {
+			t.Errorf("lowerSeverity(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+// Package jsscan downloads the same-origin script files a page references
+// and searches them for API endpoints, relative paths, source map
+// references and credentials in known formats. Source maps are fetched and
+// mined for the original file layout and the npm packages bundled. Nothing
+// is executed; the files are scanned as text.
+package jsscan
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+)
+
+const (
+	// maxScriptBody bounds each script download; larger bundles are
+	// analyzed up to the limit.
+	maxScriptBody = 2 << 20
+	// maxScripts caps the files downloaded per page.
+	maxScripts = 30
+	// maxPerKind caps the endpoints and paths reported; secrets are never
+	// capped.
+	maxPerKind = 200
+	// maxValue caps the length of reported values.
+	maxValue = 200
+	// concurrency bounds the downloads in flight.
+	concurrency = 4
+)
+
+var (
+	// sourceMapComment is the trailing comment naming a script's source map.
+	sourceMapComment = regexp.MustCompile(`(?m)^[ \t]*//[#@][ \t]*sourceMappingURL=(\S+)`)
+
+	// urlLiteral is an absolute http(s) URL in a string literal.
+	urlLiteral = regexp.MustCompile("[\"'`](https?://[^\\s\"'`<>\\\\]{4,})[\"'`]")
+
+	// pathLiteral is a root- or dot-relative path in a string literal.
+	pathLiteral = regexp.MustCompile("[\"'`]((?:\\.{1,2})?/[A-Za-z0-9_\\-.~%]+(?:/[A-Za-z0-9_\\-.~%{}:$]*)*(?:\\?[^\\s\"'`<>\\\\]*)?)[\"'`]")
+
+	// apiPath marks a path as an API endpoint rather than a page or asset.
+	apiPath = regexp.MustCompile(`(?i)(?:^|/)(?:api|graphql|rest|rpc|v[0-9]+|oauth2?|auth|admin|internal|ajax|wp-json)(?:/|$|\?)|\.(?:json|php|aspx?)(?:$|\?)`)
+
+	// ignoredHosts are URL hosts that appear in nearly every bundle as
+	// namespaces or documentation links.
+	ignoredHosts = map[string]bool{
+		"www.w3.org":    true,
+		"reactjs.org":   true,
+		"react.dev":     true,
+		"fb.me":         true,
+		"momentjs.com":  true,
+		"jquery.org":    true,
+		"feross.org":    true,
+		"github.com":    true,
+		"mths.be":       true,
+		"goo.gl":        true,
+		"bit.ly":        true,
+		"example.com":   true,
+		"localhost":     true,
+		"schema.org":    true,
+		"www.apple.com": true,
+	}
+)
+
+// Analyzer downloads and scans script files.
+type Analyzer struct {
+	scan *scanner.Scanner
+}
+
+// New creates an Analyzer using the scanner's HTTP settings.
+func New(scan *scanner.Scanner) *Analyzer {
+	return &Analyzer{scan: scan}
+}
+
+// Analyze downloads up to maxScripts script files of pg that share the
+// page's origin and scans them. Findings are sorted by kind, secrets first
+// and most severe first, then by file and position; each value is reported
+// once, at its first occurrence.
+func (a *Analyzer) Analyze(pg *models.Page) *models.ScriptAnalysis {
+	analysis := &models.ScriptAnalysis{Files: []models.ScriptFile{}}
+	if pg == nil {
+		return analysis
+	}
+	scripts := SameOrigin(pg.URL, pg.Scripts)
+	if len(scripts) > maxScripts {
+		scripts = scripts[:maxScripts]
+	}
+
+	files := make([]models.ScriptFile, len(scripts))
+	findings := make([][]models.ScriptFinding, len(scripts))
+	maps := make([]*models.SourceMap, len(scripts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, script := range scripts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, script string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			files[i], findings[i], maps[i] = a.analyze(script)
+		}(i, script)
+	}
+	wg.Wait()
+
+	analysis.Files = files
+	for _, sm := range maps {
+		if sm != nil {
+			analysis.SourceMaps = append(analysis.SourceMaps, *sm)
+		}
+	}
+	seen := make(map[string]bool)
+	count := make(map[string]int)
+	for _, list := range findings {
+		for _, f := range list {
+			if seen[f.Kind+"\x00"+f.Value] {
+				continue
+			}
+			seen[f.Kind+"\x00"+f.Value] = true
+			if f.Kind != models.ScriptSecret && count[f.Kind] >= maxPerKind {
+				continue
+			}
+			count[f.Kind]++
+			analysis.Findings = append(analysis.Findings, f)
+		}
+	}
+	sortFindings(analysis.Findings)
+	return analysis
+}
+
+// analyze downloads and scans one script and its source map.
+func (a *Analyzer) analyze(script string) (models.ScriptFile, []models.ScriptFinding, *models.SourceMap) {
+	file := models.ScriptFile{URL: script}
+	resp, err := a.scan.FetchLimited(script, maxScriptBody)
+	if err != nil {
+		file.Error = err.Error()
+		return file, nil, nil
+	}
+	file.StatusCode = resp.StatusCode
+	file.Size = resp.BodyInfo.Size
+	file.Truncated = resp.BodyInfo.Truncated
+	// Single-page apps answer missing files with the HTML shell.
+	if resp.StatusCode != 200 || resp.MediaType() == "text/html" {
+		return file, nil, nil
+	}
+
+	findings := Scan(script, resp.Body)
+	header := resp.Header.Get("SourceMap")
+	if header == "" {
+		header = resp.Header.Get("X-SourceMap")
+	}
+	ref := sourceMapRef(header, resp.Body)
+	if ref == "" {
+		return file, findings, nil
+	}
+	sm := a.fetchSourceMap(script, ref)
+	file.SourceMap = sm.URL
+	return file, findings, sm
+}
+
+// Scan searches the source of one script, named file in the findings.
+// Relative source map references are resolved against file.
+func Scan(file, source string) []models.ScriptFinding {
+	lines := newLineIndex(source)
+	var findings []models.ScriptFinding
+	add := func(kind, name, value, severity string, offset int) {
+		line, column := lines.position(offset)
+		findings = append(findings, models.ScriptFinding{
+			Kind:     kind,
+			Name:     name,
+			Value:    truncate(value),
+			File:     file,
+			Line:     line,
+			Column:   column,
+			Severity: severity,
+		})
+	}
+
+	for _, m := range sourceMapComment.FindAllStringSubmatchIndex(source, -1) {
+		ref := source[m[2]:m[3]]
+		if strings.HasPrefix(ref, "data:") {
+			ref = "inline (data: URL)"
+		} else {
+			ref = resolve(file, ref)
+		}
+		add(models.ScriptSourceMap, "", ref, "", m[2])
+	}
+
+	for _, m := range urlLiteral.FindAllStringSubmatchIndex(source, -1) {
+		value := source[m[2]:m[3]]
+		u, err := url.Parse(value)
+		if err != nil || u.Host == "" || ignoredHosts[u.Hostname()] {
+			continue
+		}
+		add(models.ScriptEndpoint, "", value, "", m[2])
+	}
+
+	for _, m := range pathLiteral.FindAllStringSubmatchIndex(source, -1) {
+		value := source[m[2]:m[3]]
+		if strings.HasPrefix(value, "//") || !strings.ContainsAny(value, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			continue
+		}
+		kind := models.ScriptPath
+		if apiPath.MatchString(value) {
+			kind = models.ScriptEndpoint
+		}
+		add(kind, "", value, "", m[2])
+	}
+
+	for _, rule := range secretRules {
+		for _, m := range rule.pattern.FindAllStringSubmatchIndex(source, -1) {
+			value := source[m[2]:m[3]]
+			if entropy(value) < rule.minEntropy {
+				continue
+			}
+			add(models.ScriptSecret, rule.name, mask(value), rule.severity, m[2])
+		}
+	}
+	return findings
+}
+
+// SameOrigin returns the scripts served from the origin of pageURL, in page
+// order.
+func SameOrigin(pageURL string, scripts []string) []string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	var same []string
+	for _, script := range scripts {
+		u, err := url.Parse(script)
+		if err == nil && u.Scheme == base.Scheme && strings.EqualFold(u.Host, base.Host) {
+			same = append(same, script)
+		}
+	}
+	return same
+}
+
+// resolve turns ref into an absolute URL relative to file.
+func resolve(file, ref string) string {
+	base, err := url.Parse(file)
+	if err != nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// kindOrder lists secrets first, then the kinds most useful for mapping the
+// application.
+var kindOrder = map[string]int{
+	models.ScriptSecret:    0,
+	models.ScriptSourceMap: 1,
+	models.ScriptEndpoint:  2,
+	models.ScriptPath:      3,
+}
+
+func sortFindings(findings []models.ScriptFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if ra, rb := models.SeverityRank(a.Severity), models.SeverityRank(b.Severity); ra != rb {
+			return ra > rb
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// truncate shortens long values for display.
+func truncate(s string) string {
+	if len(s) > maxValue {
+		return strings.ToValidUTF8(s[:maxValue], "") + "…"
+	}
+	return s
+}
+
+// lineIndex maps byte offsets to line and column numbers.
+type lineIndex []int
+
+func newLineIndex(source string) lineIndex {
+	starts := lineIndex{0}
+	for i := 0; i < len(source); i++ {
+		if source[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// position returns the 1-based line and column of offset.
+func (li lineIndex) position(offset int) (line, column int) {
+	i := sort.Search(len(li), func(i int) bool { return li[i] > offset }) - 1
+	return i + 1, offset - li[i] + 1
+}
@@ -0,0 +1,280 @@
+package jsscan
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+const (
+	// maxSourceMapBody bounds source map downloads; maps embedding their
+	// sources are often several megabytes.
+	maxSourceMapBody = 16 << 20
+	// maxSourceFiles caps the application file paths kept per map.
+	maxSourceFiles = 500
+	// bannerWindow is how much of a bundled file is searched for a version
+	// banner.
+	bannerWindow = 2048
+)
+
+var (
+	// pnpmDir is a pnpm store directory: ".pnpm/<name>@<version>", scoped
+	// names with "+" for "/", followed by peer suffixes.
+	pnpmDir = regexp.MustCompile(`(?:^|/)\.pnpm/(@?[^/@]+)@([0-9][^/_(]*)`)
+	// yarnCache is a Yarn Berry cache archive: "<name>-npm-<version>-<hash>.zip".
+	yarnCache = regexp.MustCompile(`-npm-([0-9][0-9A-Za-z.+-]*?)-[0-9a-f]{8,}\.zip/`)
+	// bannerVersion is a name followed by a version, as in license banners
+	// ("/*! jQuery v3.7.1", "@license React v18.2.0").
+	bannerVersion = regexp.MustCompile(`\b([\w.-]+)(?:\.js)?[\s@:]+v?([0-9]+\.[0-9]+\.[0-9]+[0-9A-Za-z.+-]*)`)
+	// versionAssignment is a VERSION constant, as lodash and others ship.
+	versionAssignment = regexp.MustCompile(`\bVERSION\s*=\s*["']v?([0-9]+\.[0-9]+\.[0-9]+[0-9A-Za-z.+-]*)["']`)
+	// packageVersion is the version field of a bundled package.json.
+	packageVersion = regexp.MustCompile(`"version"\s*:\s*"([0-9]+\.[0-9]+\.[0-9]+[0-9A-Za-z.+-]*)"`)
+	// sourcePrefix is the bundler scheme and namespace of a source path,
+	// e.g. "webpack://app/".
+	sourcePrefix = regexp.MustCompile(`^[a-z][a-z0-9+.-]*://[^/]*/`)
+)
+
+// rawSourceMap is the subset of the Source Map v3 format that is read.
+// Index maps nest their sections.
+type rawSourceMap struct {
+	SourceRoot     string    `json:"sourceRoot"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent"`
+	Sections       []struct {
+		Map *rawSourceMap `json:"map"`
+	} `json:"sections"`
+}
+
+// sourceMapRef returns the source map reference of a script: the SourceMap
+// header (or its X-SourceMap predecessor) if set, the last sourceMappingURL
+// comment otherwise.
+func sourceMapRef(header, source string) string {
+	if header != "" {
+		return strings.TrimSpace(header)
+	}
+	matches := sourceMapComment.FindAllStringSubmatch(source, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1][1]
+}
+
+// fetchSourceMap loads the map ref points at, relative to script. Inline
+// data: URLs are decoded instead of fetched.
+func (a *Analyzer) fetchSourceMap(script, ref string) *models.SourceMap {
+	sm := &models.SourceMap{URL: resolve(script, ref), Script: script}
+
+	var data []byte
+	if strings.HasPrefix(ref, "data:") {
+		sm.URL = "inline"
+		decoded, err := decodeDataURL(ref)
+		if err != nil {
+			sm.Error = err.Error()
+			return sm
+		}
+		data = decoded
+	} else {
+		if u, err := url.Parse(sm.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			sm.Error = "unsupported source map URL"
+			return sm
+		}
+		resp, err := a.scan.FetchLimited(sm.URL, maxSourceMapBody)
+		if err != nil {
+			sm.Error = err.Error()
+			return sm
+		}
+		sm.StatusCode = resp.StatusCode
+		if resp.StatusCode != 200 {
+			return sm
+		}
+		if resp.BodyInfo.Truncated {
+			sm.Error = fmt.Sprintf("larger than %d bytes", int64(maxSourceMapBody))
+			return sm
+		}
+		data = []byte(resp.Body)
+	}
+
+	var raw rawSourceMap
+	if err := json.Unmarshal(data, &raw); err != nil {
+		sm.Error = fmt.Sprintf("not a source map: %v", err)
+		return sm
+	}
+	analyzeSourceMap(sm, &raw)
+	return sm
+}
+
+// analyzeSourceMap fills sm from a parsed map.
+func analyzeSourceMap(sm *models.SourceMap, raw *rawSourceMap) {
+	files := make(map[string]bool)
+	packages := make(map[models.Package]int)
+
+	var walk func(m *rawSourceMap)
+	walk = func(m *rawSourceMap) {
+		for _, section := range m.Sections {
+			if section.Map != nil {
+				walk(section.Map)
+			}
+		}
+		for i, source := range m.Sources {
+			sm.Sources++
+			source = m.SourceRoot + source
+			name, depth, version := packageOf(source)
+			if name == "" {
+				if len(files) < maxSourceFiles {
+					files[cleanSource(source)] = true
+				}
+				continue
+			}
+			if version == "" && i < len(m.SourcesContent) && m.SourcesContent[i] != nil {
+				version = bundledVersion(name, source, *m.SourcesContent[i])
+			}
+			pkg := models.Package{Name: name, Version: version}
+			if d, ok := packages[pkg]; !ok || depth < d {
+				packages[pkg] = depth
+			}
+		}
+	}
+	walk(raw)
+
+	for f := range files {
+		sm.Files = append(sm.Files, f)
+	}
+	sort.Strings(sm.Files)
+	sm.Packages = sortPackages(packages)
+}
+
+// packageOf returns the npm package a source path belongs to, how many
+// node_modules directories deep it is, and its version when the path
+// reveals it (pnpm store and Yarn cache layouts).
+func packageOf(source string) (name string, depth int, version string) {
+	depth = strings.Count(source, "node_modules/")
+	i := strings.LastIndex(source, "node_modules/")
+	if i < 0 {
+		return "", 0, ""
+	}
+	parts := strings.Split(source[i+len("node_modules/"):], "/")
+	name = parts[0]
+	if strings.HasPrefix(name, "@") && len(parts) > 1 {
+		name += "/" + parts[1]
+	}
+	if name == "" || name == ".pnpm" || strings.HasPrefix(name, ".") {
+		return "", 0, ""
+	}
+
+	for _, m := range pnpmDir.FindAllStringSubmatch(source[:i], -1) {
+		if strings.ReplaceAll(m[1], "+", "/") == name {
+			version = m[2]
+		}
+	}
+	if version == "" {
+		if m := yarnCache.FindStringSubmatch(source[:i]); m != nil {
+			version = m[1]
+		}
+	}
+	return name, depth, version
+}
+
+// bundledVersion looks for a package's version in the content of one of
+// its files: a package.json version field, a license banner naming the
+// package, or a VERSION constant.
+func bundledVersion(name, source, content string) string {
+	if path.Base(source) == "package.json" {
+		if m := packageVersion.FindStringSubmatch(content); m != nil {
+			return m[1]
+		}
+		return ""
+	}
+	if len(content) > bannerWindow {
+		content = content[:bannerWindow]
+	}
+	base := path.Base(name)
+	for _, m := range bannerVersion.FindAllStringSubmatch(content, -1) {
+		if strings.EqualFold(m[1], base) || strings.EqualFold(strings.TrimSuffix(m[1], ".js"), base) {
+			return strings.TrimRight(m[2], ".")
+		}
+	}
+	if m := versionAssignment.FindStringSubmatch(content); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// cleanSource strips the bundler scheme, namespace and leading relative
+// segments from a source path.
+func cleanSource(source string) string {
+	source = sourcePrefix.ReplaceAllString(source, "")
+	for {
+		switch {
+		case strings.HasPrefix(source, "./"):
+			source = source[2:]
+		case strings.HasPrefix(source, "../"):
+			source = source[3:]
+		case strings.HasPrefix(source, "/"):
+			source = source[1:]
+		default:
+			return source
+		}
+	}
+}
+
+// sortPackages orders packages by name, the shallowest (direct dependency)
+// copy first, then by version. Versionless entries are dropped when a
+// version of the same package is known.
+func sortPackages(depths map[models.Package]int) []models.Package {
+	versioned := make(map[string]bool)
+	var pkgs []models.Package
+	for p := range depths {
+		pkgs = append(pkgs, p)
+		if p.Version != "" {
+			versioned[p.Name] = true
+		}
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		a, b := pkgs[i], pkgs[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if depths[a] != depths[b] {
+			return depths[a] < depths[b]
+		}
+		return a.Version < b.Version
+	})
+
+	kept := pkgs[:0]
+	for _, p := range pkgs {
+		if p.Version != "" || !versioned[p.Name] {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// decodeDataURL returns the payload of a base64 or percent-encoded data:
+// URL.
+func decodeDataURL(ref string) ([]byte, error) {
+	comma := strings.IndexByte(ref, ',')
+	if comma < 0 {
+		return nil, fmt.Errorf("malformed data URL")
+	}
+	meta, payload := ref[len("data:"):comma], ref[comma+1:]
+	if strings.HasSuffix(meta, ";base64") {
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data URL: %w", err)
+		}
+		return data, nil
+	}
+	decoded, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data URL: %w", err)
+	}
+	return []byte(decoded), nil
+}
@@ -0,0 +1,47 @@
+package jsscan
+
+import "testing"
+
+func TestBundledVersion(t *testing.T) {
+	tests := []struct {
+		name, source, content, want string
+	}{
+		{"jquery", "node_modules/jquery/dist/jquery.js", "/*! jQuery v3.7.1 | (c) OpenJS Foundation */", "3.7.1"},
+		{"react", "node_modules/react/cjs/react.production.min.js", "/** @license React v18.2.0\n * react.production.min.js", "18.2.0"},
+		{"chart.js", "node_modules/chart.js/dist/chart.js", "/*!\n * Chart.js v4.4.0\n */", "4.4.0"},
+		{"vue", "node_modules/vue/dist/vue.js", "/*!\n * Vue.js v2.6.14\n */", "2.6.14"},
+		{"@scope/widget", "node_modules/@scope/widget/index.js", "/* widget@1.2.3 */", "1.2.3"},
+		{"lodash", "node_modules/lodash/lodash.js", "var VERSION = '4.17.21';", "4.17.21"},
+		{"left-pad", "node_modules/left-pad/package.json", `{"name": "left-pad", "version": "1.3.0"}`, "1.3.0"},
+		// Versions of other packages in the banner are not taken.
+		{"tiny", "node_modules/tiny/index.js", "/*! built with rollup v3.29.4 */", ""},
+		{"dom", "node_modules/dom/index.js", "/*! react-dom v18.2.0 */", ""},
+	}
+
+	for _, tt := range tests {
+		if got := bundledVersion(tt.name, tt.source, tt.content); got != tt.want {
+			t.Errorf("bundledVersion(%q, %q) = %q, want %q", tt.name, tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestPackageOf(t *testing.T) {
+	tests := []struct {
+		source  string
+		name    string
+		depth   int
+		version string
+	}{
+		{"node_modules/lodash/lodash.js", "lodash", 1, ""},
+		{"node_modules/@babel/runtime/helpers/extends.js", "@babel/runtime", 1, ""},
+		{"node_modules/.pnpm/react-dom@18.2.0/node_modules/react-dom/index.js", "react-dom", 2, "18.2.0"},
+		{"src/App.tsx", "", 0, ""},
+	}
+
+	for _, tt := range tests {
+		name, depth, version := packageOf(tt.source)
+		if name != tt.name || depth != tt.depth || version != tt.version {
+			t.Errorf("packageOf(%q) = %q, %d, %q, want %q, %d, %q", tt.source, name, depth, version, tt.name, tt.depth, tt.version)
+		}
+	}
+}
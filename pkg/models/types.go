@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
 // ScanResult contains all the results from a domain scan.
 type ScanResult struct {
@@ -9,13 +14,27 @@ type ScanResult struct {
 	HTTP            *HTTPAnalysis          `json:"http,omitempty"`
 	DNS             *DNSAnalysis           `json:"dns,omitempty"`
 	TLS             *TLSAnalysis           `json:"tls,omitempty"`
+	Page            *Page                  `json:"page,omitempty"`
+	Favicon         *Favicon               `json:"favicon,omitempty"`
+	WellKnown       *WellKnown             `json:"well_known,omitempty"`
 	Technologies    *Technologies          `json:"technologies,omitempty"`
+	TrackingIDs     []TrackingID           `json:"tracking_ids,omitempty"`
 	CDN             string                 `json:"cdn,omitempty"`
+	CDNs            []CDNDetection         `json:"cdns,omitempty"`
+	Origin          *OriginAnalysis        `json:"origin,omitempty"`
 	WAF             string                 `json:"waf,omitempty"`
+	WAFDetails      *WAFAnalysis           `json:"waf_details,omitempty"`
 	CloudProvider   string                 `json:"cloud_provider,omitempty"`
 	Geolocation     *Geolocation           `json:"geolocation,omitempty"`
 	Subdomains      []string               `json:"subdomains,omitempty"`
+	SubdomainsFound int                    `json:"subdomains_found,omitempty"` // before Scanner.MaxSubdomainsDisplay
 	SecurityHeaders map[string]string      `json:"security_headers,omitempty"`
+	Vulnerabilities []Vulnerability        `json:"vulnerabilities,omitempty"`
+	Exposures       []Exposure             `json:"exposures,omitempty"`
+	Scripts         *ScriptAnalysis        `json:"scripts,omitempty"`
+	HTTPSecurity    *HTTPSecurity          `json:"http_security,omitempty"`
+	Soft404         *Soft404Baseline       `json:"soft_404,omitempty"`
+	Stages          map[string]*Stage      `json:"stages,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -27,6 +46,47 @@ type HTTPAnalysis struct {
 	ResponseTime int64             `json:"response_time_ms"`
 	RedirectURL  string            `json:"redirect_url,omitempty"`
 	ContentType  string            `json:"content_type,omitempty"`
+	Body         *BodyInfo         `json:"body,omitempty"`
+	Protocol     string            `json:"protocol,omitempty"`
+	Protocols    *ProtocolSupport  `json:"protocols,omitempty"`
+}
+
+// ProtocolSupport is the HTTP protocol support matrix of a host.
+type ProtocolSupport struct {
+	Host string `json:"host"`
+	// ALPN is the protocol the server picks when offered h2 and http/1.1.
+	ALPN   string `json:"alpn"`
+	HTTP11 bool   `json:"http1_1"`
+	HTTP2  bool   `json:"http2"`
+	// HTTP3 is advertised through Alt-Svc; QUIC holds the optional probe.
+	HTTP3  bool         `json:"http3"`
+	AltSvc []AltService `json:"alt_svc,omitempty"`
+	QUIC   *QUICProbe   `json:"quic,omitempty"`
+}
+
+// AltService is one entry of an Alt-Svc header.
+type AltService struct {
+	Protocol string `json:"protocol"`
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port"`
+	MaxAge   int    `json:"max_age,omitempty"`
+}
+
+// QUICProbe is the result of a QUIC version negotiation probe.
+type QUICProbe struct {
+	Address   string   `json:"address"`
+	Reachable bool     `json:"reachable"`
+	Versions  []string `json:"versions,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// BodyInfo describes how the response body was read and decoded.
+type BodyInfo struct {
+	Size      int64  `json:"size"`
+	Limit     int64  `json:"limit"`
+	Truncated bool   `json:"truncated"`
+	Encoding  string `json:"encoding,omitempty"`
+	Charset   string `json:"charset,omitempty"`
 }
 
 // DNSAnalysis contains DNS records.
@@ -51,17 +111,544 @@ type TLSAnalysis struct {
 	SANs         []string  `json:"sans,omitempty"`
 	SignatureAlg string    `json:"signature_algorithm"`
 	PublicKeyAlg string    `json:"public_key_algorithm"`
+	// Fingerprint is the hex SHA-256 of the leaf certificate (DER).
+	Fingerprint string `json:"fingerprint_sha256,omitempty"`
+}
+
+// Page is a structured model of the fetched HTML document.
+type Page struct {
+	URL                string    `json:"url,omitempty"`
+	Title              string    `json:"title,omitempty"`
+	Generator          string    `json:"generator,omitempty"`
+	Meta               []MetaTag `json:"meta,omitempty"`
+	Scripts            []string  `json:"scripts,omitempty"`
+	Stylesheets        []string  `json:"stylesheets,omitempty"`
+	InlineScriptHashes []string  `json:"inline_script_hashes,omitempty"`
+	Links              []string  `json:"links,omitempty"`
+	Forms              []Form    `json:"forms,omitempty"`
+	ExternalDomains    []string  `json:"external_domains,omitempty"`
+	Favicon            string    `json:"favicon,omitempty"`
+	InlineScripts      []string  `json:"-"`
+}
+
+// MetaTag is a <meta> element keyed by its name, property or http-equiv.
+type MetaTag struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// Form describes an HTML form and its named inputs.
+type Form struct {
+	Action      string   `json:"action,omitempty"`
+	Method      string   `json:"method"`
+	Inputs      []string `json:"inputs,omitempty"`
+	HasPassword bool     `json:"has_password,omitempty"`
+}
+
+// Favicon holds the site icon's hashes. MMH3 is the Shodan-style
+// http.favicon.hash value.
+type Favicon struct {
+	URL         string `json:"url"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type,omitempty"`
+	MMH3        int32  `json:"mmh3"`
+	MD5         string `json:"md5"`
+	SHA256      string `json:"sha256"`
+}
+
+// WellKnown holds the well-known files found on the site. Missing files are
+// left nil.
+type WellKnown struct {
+	Robots      *RobotsTxt           `json:"robots_txt,omitempty"`
+	Sitemap     *Sitemap             `json:"sitemap,omitempty"`
+	SecurityTxt *SecurityTxt         `json:"security_txt,omitempty"`
+	OpenID      *OpenIDConfiguration `json:"openid_configuration,omitempty"`
+	Humans      *HumansTxt           `json:"humans_txt,omitempty"`
+	Ads         *AdsTxt              `json:"ads_txt,omitempty"`
+}
+
+// RobotsTxt is a parsed robots.txt. Paths are merged across all user-agent
+// groups.
+type RobotsTxt struct {
+	URL        string   `json:"url"`
+	UserAgents []string `json:"user_agents,omitempty"`
+	Disallowed []string `json:"disallowed,omitempty"`
+	Allowed    []string `json:"allowed,omitempty"`
+	Sitemaps   []string `json:"sitemaps,omitempty"`
+}
+
+// Sitemap summarizes a sitemap.xml or sitemap index. Nested sitemaps are
+// listed, not fetched.
+type Sitemap struct {
+	URL      string   `json:"url"`
+	Index    bool     `json:"index"`
+	URLCount int      `json:"url_count"`
+	Sitemaps []string `json:"sitemaps,omitempty"`
+}
+
+// SecurityTxt is a parsed RFC 9116 security.txt file.
+type SecurityTxt struct {
+	URL                string     `json:"url"`
+	Contact            []string   `json:"contact,omitempty"`
+	Expires            *time.Time `json:"expires,omitempty"`
+	Expired            bool       `json:"expired"`
+	Encryption         []string   `json:"encryption,omitempty"`
+	Acknowledgments    []string   `json:"acknowledgments,omitempty"`
+	PreferredLanguages []string   `json:"preferred_languages,omitempty"`
+	Canonical          []string   `json:"canonical,omitempty"`
+	Policy             []string   `json:"policy,omitempty"`
+	Hiring             []string   `json:"hiring,omitempty"`
+	Signed             bool       `json:"signed"`
+	Errors             []string   `json:"errors,omitempty"`
+}
+
+// OpenIDConfiguration holds the main fields of an OpenID Connect discovery
+// document.
+type OpenIDConfiguration struct {
+	URL                   string   `json:"url"`
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint         string   `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint      string   `json:"userinfo_endpoint,omitempty"`
+	JWKSURI               string   `json:"jwks_uri,omitempty"`
+	ScopesSupported       []string `json:"scopes_supported,omitempty"`
+	GrantTypesSupported   []string `json:"grant_types_supported,omitempty"`
 }
 
-// Technologies contains detected web technologies.
+// HumansTxt is a humans.txt file. Content is capped in size.
+type HumansTxt struct {
+	URL     string `json:"url"`
+	Content string `json:"content"`
+}
+
+// AdsTxt summarizes an IAB ads.txt file.
+type AdsTxt struct {
+	URL       string   `json:"url"`
+	Records   int      `json:"records"`
+	Direct    int      `json:"direct"`
+	Reseller  int      `json:"reseller"`
+	AdSystems []string `json:"ad_systems,omitempty"`
+	Contact   string   `json:"contact,omitempty"`
+}
+
+// Technologies contains detected web technologies. The name lists are a
+// summary by category; Items holds the full detection records.
 type Technologies struct {
-	CMS         string   `json:"cms,omitempty"`
-	Frameworks  []string `json:"frameworks,omitempty"`
-	Libraries   []string `json:"libraries,omitempty"`
-	Languages   []string `json:"languages,omitempty"`
-	Analytics   []string `json:"analytics,omitempty"`
-	WebServers  []string `json:"web_servers,omitempty"`
-	Fingerprint []string `json:"fingerprint,omitempty"`
+	CMS         string       `json:"cms,omitempty"`
+	Frameworks  []string     `json:"frameworks,omitempty"`
+	Libraries   []string     `json:"libraries,omitempty"`
+	Languages   []string     `json:"languages,omitempty"`
+	Analytics   []string     `json:"analytics,omitempty"`
+	WebServers  []string     `json:"web_servers,omitempty"`
+	Fingerprint []string     `json:"fingerprint,omitempty"`
+	Items       []Technology `json:"items,omitempty"`
+}
+
+// Technology is a single detected technology.
+type Technology struct {
+	Name       string     `json:"name"`
+	Version    string     `json:"version,omitempty"`
+	Categories []string   `json:"categories,omitempty"`
+	Confidence int        `json:"confidence"`
+	Evidence   []Evidence `json:"evidence,omitempty"`
+	CPE        string     `json:"cpe,omitempty"`
+	Website    string     `json:"website,omitempty"`
+	// Package is the npm package name, when known from a source map.
+	Package string `json:"package,omitempty"`
+}
+
+// Evidence records which pattern matched where.
+type Evidence struct {
+	Source     string `json:"source"`
+	Key        string `json:"key,omitempty"`
+	Pattern    string `json:"pattern,omitempty"`
+	Match      string `json:"match,omitempty"`
+	Confidence int    `json:"confidence"`
+}
+
+// Evidence sources.
+const (
+	EvidenceHeader       = "header"
+	EvidenceCookie       = "cookie"
+	EvidenceHTML         = "html"
+	EvidenceScript       = "script"
+	EvidenceInlineScript = "inline_script"
+	EvidenceMeta         = "meta"
+	EvidenceURL          = "url"
+	EvidenceImplied      = "implied"
+	EvidenceFavicon      = "favicon"
+	EvidenceBody         = "body"
+	EvidenceBehavior     = "behavior"
+	EvidenceCNAME        = "cname"
+	EvidenceSourceMap    = "source_map"
+)
+
+// Label returns the technology name followed by its version, if known.
+func (t *Technology) Label() string {
+	if t.Version == "" {
+		return t.Name
+	}
+	return t.Name + " " + t.Version
+}
+
+// String formats the detection for reports, for example
+// "WordPress 6.4.2 (100%, meta generator + script src)".
+func (t *Technology) String() string {
+	seen := make(map[string]bool)
+	var sources []string
+	for _, e := range t.Evidence {
+		desc := e.Describe()
+		if !seen[desc] {
+			seen[desc] = true
+			sources = append(sources, desc)
+		}
+	}
+	if len(sources) == 0 {
+		return fmt.Sprintf("%s (%d%%)", t.Label(), t.Confidence)
+	}
+	return fmt.Sprintf("%s (%d%%, %s)", t.Label(), t.Confidence, strings.Join(sources, " + "))
+}
+
+// Describe returns a short human-readable description of the evidence.
+func (e *Evidence) Describe() string {
+	switch e.Source {
+	case EvidenceScript:
+		return "script src"
+	case EvidenceInlineScript:
+		return "inline script"
+	case EvidenceImplied:
+		return "implied by " + e.Key
+	case EvidenceFavicon:
+		return "favicon hash"
+	case EvidenceBody:
+		return "block page"
+	case EvidenceBehavior:
+		return e.Key + " probe blocked"
+	}
+	if e.Key != "" {
+		return e.Source + " " + e.Key
+	}
+	return e.Source
+}
+
+// Vulnerability is a known vulnerability affecting a detected technology.
+type Vulnerability struct {
+	ID            string   `json:"id"`
+	Aliases       []string `json:"aliases,omitempty"`
+	Technology    string   `json:"technology"`
+	Version       string   `json:"version"`
+	CVSS          float64  `json:"cvss,omitempty"`
+	Severity      string   `json:"severity,omitempty"`
+	Summary       string   `json:"summary,omitempty"`
+	FixedVersions []string `json:"fixed_versions,omitempty"`
+	Source        string   `json:"source,omitempty"`
+}
+
+// HTTPSecurity holds the results of the HTTP method and CORS checks.
+type HTTPSecurity struct {
+	// AllowedMethods is the Allow header returned for OPTIONS.
+	AllowedMethods []string     `json:"allowed_methods,omitempty"`
+	Methods        []MethodTest `json:"methods"`
+	CORS           []CORSTest   `json:"cors"`
+	Findings       []Finding    `json:"findings,omitempty"`
+}
+
+// MethodTest is the response to one HTTP method.
+type MethodTest struct {
+	Method     string `json:"method"`
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Enabled    bool   `json:"enabled"`
+}
+
+// CORSTest is the response to one crafted Origin header.
+type CORSTest struct {
+	Name             string `json:"name"`
+	Origin           string `json:"origin"`
+	AllowOrigin      string `json:"allow_origin,omitempty"`
+	AllowCredentials bool   `json:"allow_credentials"`
+}
+
+// Finding is a security issue found by an active check.
+type Finding struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+	Evidence string `json:"evidence,omitempty"`
+}
+
+// SortFindings orders findings by severity, most severe first, keeping the
+// original order for ties.
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return SeverityRank(findings[i].Severity) > SeverityRank(findings[j].Severity)
+	})
+}
+
+// WAFAnalysis explains a WAF identification. Probes is only set when active
+// probing ran.
+type WAFAnalysis struct {
+	Detected bool       `json:"detected"`
+	Vendor   string     `json:"vendor,omitempty"`
+	Evidence []Evidence `json:"evidence,omitempty"`
+	Probes   []WAFProbe `json:"probes,omitempty"`
+}
+
+// WAFProbe is the response to one attack-looking request.
+type WAFProbe struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Blocked    bool   `json:"blocked"`
+	Error      string `json:"error,omitempty"`
+	ErrorClass string `json:"error_class,omitempty"`
+}
+
+// TrackingID is a third-party account identifier found in a page, such as a
+// Google Analytics property or a Facebook Pixel ID. Sites sharing an ID are
+// usually run by the same owner.
+type TrackingID struct {
+	Service string `json:"service"`
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+}
+
+// CDNDetection is one CDN identified in front of the site. When several are
+// found, the one the DNS name points to comes first.
+type CDNDetection struct {
+	Name     string     `json:"name"`
+	Evidence []Evidence `json:"evidence"`
+}
+
+// OriginAnalysis is the result of looking for origin servers that answer
+// directly, bypassing the CDN.
+type OriginAnalysis struct {
+	// FrontedIPs are the addresses the site currently resolves to; they are
+	// never tested as candidates.
+	FrontedIPs []string          `json:"fronted_ips,omitempty"`
+	Candidates []OriginCandidate `json:"candidates"`
+}
+
+// OriginCandidate is one address tested as a possible origin server.
+type OriginCandidate struct {
+	IP     string `json:"ip"`
+	Source string `json:"source"`
+	// Host is the MX host or subdomain the address was resolved from.
+	Host             string `json:"host,omitempty"`
+	URL              string `json:"url,omitempty"`
+	StatusCode       int    `json:"status_code,omitempty"`
+	Title            string `json:"title,omitempty"`
+	Distance         int    `json:"simhash_distance,omitempty"`
+	CertificateValid bool   `json:"certificate_valid"`
+	// CDN names the CDN the candidate answered as. CDN edges serve the
+	// site for any of their addresses and are never exposed origins.
+	CDN     string `json:"cdn,omitempty"`
+	Exposed bool   `json:"exposed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Origin candidate sources.
+const (
+	OriginSourceHistorical = "historical"
+	OriginSourceMX         = "mx"
+	OriginSourceSubdomain  = "subdomain"
+)
+
+// Soft404Baseline records how a site answers requests for paths that do not
+// exist. Path probes compare their responses against these samples.
+type Soft404Baseline struct {
+	Origin string `json:"origin"`
+	// CatchAll is set when every random path returned a 2xx status.
+	CatchAll bool            `json:"catch_all"`
+	Samples  []Soft404Sample `json:"samples"`
+}
+
+// Soft404Sample fingerprints the response to one random path.
+type Soft404Sample struct {
+	Path         string `json:"path"`
+	StatusCode   int    `json:"status_code"`
+	Length       int64  `json:"length"`
+	LengthBucket int    `json:"length_bucket"`
+	SimHash      string `json:"simhash"`
+	Title        string `json:"title,omitempty"`
+	FinalURL     string `json:"final_url"`
+	Redirected   bool   `json:"redirected"`
+}
+
+// Exposure is a sensitive file or admin interface found on the site.
+type Exposure struct {
+	Path       string `json:"path"`
+	URL        string `json:"url"`
+	Name       string `json:"name"`
+	Category   string `json:"category"`
+	Severity   string `json:"severity"`
+	StatusCode int    `json:"status_code"`
+	Evidence   string `json:"evidence,omitempty"`
+}
+
+// ScriptAnalysis holds the results of static analysis of the page's
+// same-origin script files.
+type ScriptAnalysis struct {
+	Files      []ScriptFile    `json:"files"`
+	Findings   []ScriptFinding `json:"findings,omitempty"`
+	SourceMaps []SourceMap     `json:"source_maps,omitempty"`
+}
+
+// ScriptFile is one downloaded script.
+type ScriptFile struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Size       int64  `json:"size"`
+	Truncated  bool   `json:"truncated,omitempty"`
+	SourceMap  string `json:"source_map,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// SourceMap is a script's source map and what it reveals about the original
+// sources. Files lists application sources; dependencies are summarized as
+// Packages.
+type SourceMap struct {
+	URL        string    `json:"url"`
+	Script     string    `json:"script"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Sources    int       `json:"sources"`
+	Files      []string  `json:"files,omitempty"`
+	Packages   []Package `json:"packages,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Package is an npm package bundled into a script. Version is empty when
+// the source map does not reveal it.
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// ScriptFinding is a value found in a script, located by file, line and
+// column (1-based). Secrets are masked and carry a severity.
+type ScriptFinding struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name,omitempty"`
+	Value    string `json:"value"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// Script finding kinds.
+const (
+	ScriptEndpoint  = "endpoint"
+	ScriptPath      = "path"
+	ScriptSourceMap = "source_map"
+	ScriptSecret    = "secret"
+)
+
+// Exposure severities, most severe first.
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// SeverityRank orders severities for sorting; higher is more severe and
+// unknown values rank lowest.
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 4
+	case SeverityHigh:
+		return 3
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Kinds of attributes that link related domains.
+const (
+	RelationTrackingID  = "tracking_id"
+	RelationCertificate = "certificate"
+	RelationSAN         = "san"
+	RelationFavicon     = "favicon"
+	RelationNameservers = "nameservers"
+	RelationIP          = "ip"
+)
+
+// RelatedCluster is the set of scanned domains linked to Domain, directly or
+// through other members, by shared attributes.
+type RelatedCluster struct {
+	Domain  string          `json:"domain"`
+	Scanned int             `json:"scanned"`
+	Members []RelatedDomain `json:"members,omitempty"`
+}
+
+// RelatedDomain is one member of a cluster. Distance counts the hops from
+// the queried domain; Links are the attributes shared with members one hop
+// closer.
+type RelatedDomain struct {
+	Domain   string        `json:"domain"`
+	Distance int           `json:"distance"`
+	Links    []RelatedLink `json:"links"`
+}
+
+// RelatedLink is an attribute value shared with the domain Via.
+type RelatedLink struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+	Via   string `json:"via"`
+}
+
+// Kinds of change between two scans.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// ScanDiff lists the differences between two scans, normally of the same
+// domain.
+type ScanDiff struct {
+	Domain       string    `json:"domain"`
+	OldDomain    string    `json:"old_domain,omitempty"`
+	OldTimestamp time.Time `json:"old_timestamp"`
+	NewTimestamp time.Time `json:"new_timestamp"`
+	Changes      []Change  `json:"changes"`
+}
+
+// Change is one difference. Section names the part of the scan, e.g.
+// "dns.a" or "technologies"; Item is the value or entry that changed.
+type Change struct {
+	Section string `json:"section"`
+	Kind    string `json:"kind"`
+	Item    string `json:"item"`
+	Old     string `json:"old,omitempty"`
+	New     string `json:"new,omitempty"`
+}
+
+// Stage statuses.
+const (
+	StageOK      = "ok"
+	StageFailed  = "failed"
+	StageTimeout = "timeout"
+	StageSkipped = "skipped"
+)
+
+// Stage records how one part of a scan went. Stages are keyed by module
+// name, e.g. "tls", and DNS lookups by record type, e.g. "dns.mx". A failed
+// or timed-out stage carries the error and its class ("timeout", "dns",
+// "refused", "tls", ...); a skipped stage gives the reason it did not run.
+type Stage struct {
+	Status     string `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	ErrorClass string `json:"error_class,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Reason     string `json:"reason,omitempty"`
 }
 
 // Geolocation contains location and ISP information.
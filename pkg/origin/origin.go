@@ -0,0 +1,208 @@
+// Package origin looks for origin servers that answer directly on their IP
+// address, bypassing the CDN in front of the site. Candidate addresses come
+// from historical A records supplied by the user, MX hosts and subdomains;
+// each is asked for the site's home page with the site's host name, and the
+// answer is compared with the page served through the CDN. Hosts that alias
+// a CDN and addresses that answer as a CDN edge are not origins and never
+// count as exposed.
+package origin
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/javicosvml/rankle-go/pkg/detector"
+	"github.com/javicosvml/rankle-go/pkg/dns"
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+)
+
+const (
+	// maxPageBody bounds the home page bodies read for comparison.
+	maxPageBody = 256 << 10
+	// maxCandidates caps the addresses tested per scan.
+	maxCandidates = 32
+	// concurrency bounds the lookups and requests in flight.
+	concurrency = 4
+)
+
+// Candidate is an address that may belong to the origin server.
+type Candidate struct {
+	IP     string
+	Source string
+	Host   string
+}
+
+// Finder collects and tests origin candidates.
+type Finder struct {
+	scan     *scanner.Scanner
+	resolver *dns.Resolver
+	det      *detector.Detector
+}
+
+// New creates a Finder using the scanner's settings, the DNS resolver and
+// the detector's CDN signatures.
+func New(scan *scanner.Scanner, resolver *dns.Resolver, det *detector.Detector) *Finder {
+	return &Finder{scan: scan, resolver: resolver, det: det}
+}
+
+// Candidates gathers addresses from historical A records, the MX hosts in
+// dnsAnalysis and subdomains, in that order of preference. Addresses the
+// site currently resolves to, duplicates, non-public addresses and hosts
+// whose CNAME points at a CDN are dropped, and at most maxCandidates are
+// returned.
+func (f *Finder) Candidates(dnsAnalysis *models.DNSAnalysis, subdomains, historical []string) []Candidate {
+	skip := make(map[string]bool)
+	if dnsAnalysis != nil {
+		for _, ip := range append(append([]string{}, dnsAnalysis.A...), dnsAnalysis.AAAA...) {
+			skip[ip] = true
+		}
+	}
+
+	var candidates []Candidate
+	add := func(c Candidate) {
+		ip := net.ParseIP(c.IP)
+		if ip == nil || skip[ip.String()] || !isPublic(ip) || len(candidates) >= maxCandidates {
+			return
+		}
+		c.IP = ip.String()
+		skip[c.IP] = true
+		candidates = append(candidates, c)
+	}
+
+	for _, ip := range historical {
+		add(Candidate{IP: strings.TrimSpace(ip), Source: models.OriginSourceHistorical})
+	}
+
+	var hosts []Candidate
+	if dnsAnalysis != nil {
+		for _, mx := range dnsAnalysis.MX {
+			// MX entries are stored as "host (priority: N)".
+			if fields := strings.Fields(mx); len(fields) > 0 {
+				hosts = append(hosts, Candidate{Source: models.OriginSourceMX, Host: fields[0]})
+			}
+		}
+	}
+	for _, sub := range subdomains {
+		if !strings.HasPrefix(sub, "*.") {
+			hosts = append(hosts, Candidate{Source: models.OriginSourceSubdomain, Host: sub})
+		}
+	}
+
+	for i, r := range f.resolveAll(hosts) {
+		// A host aliasing a CDN resolves to CDN edges, which serve the
+		// site's page for its Host header like the origin would.
+		if r.cname != "" && len(f.det.DetectCDNs(nil, []string{r.cname})) > 0 {
+			continue
+		}
+		for _, ip := range r.ips {
+			add(Candidate{IP: ip, Source: hosts[i].Source, Host: hosts[i].Host})
+		}
+	}
+	return candidates
+}
+
+// resolved holds the addresses and canonical name of a candidate host.
+type resolved struct {
+	ips   []string
+	cname string
+}
+
+// resolveAll looks up every host concurrently; the result is indexed like
+// hosts.
+func (f *Finder) resolveAll(hosts []Candidate) []resolved {
+	results := make([]resolved, len(hosts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if ips, err := f.resolver.LookupIP(name); err == nil {
+				results[i].ips = ips
+			}
+			if cname, err := f.resolver.LookupCNAME(name); err == nil {
+				results[i].cname = cname
+			}
+		}(i, host.Host)
+	}
+	wg.Wait()
+	return results
+}
+
+// Check fetches siteURL through the CDN and then from every candidate
+// directly. A candidate is flagged as an exposed origin when it serves the
+// same page. fronted lists the site's current addresses, for the report.
+func (f *Finder) Check(siteURL string, fronted []string, candidates []Candidate) (*models.OriginAnalysis, error) {
+	reference, err := f.scan.FetchLimited(siteURL, maxPageBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the CDN-fronted page: %w", err)
+	}
+
+	analysis := &models.OriginAnalysis{
+		FrontedIPs: fronted,
+		Candidates: make([]models.OriginCandidate, len(candidates)),
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, c := range candidates {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, c Candidate) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			analysis.Candidates[i] = f.check(reference, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	return analysis, nil
+}
+
+// check requests the reference page from one candidate, over HTTPS first
+// and plain HTTP if that fails. A candidate whose answer carries CDN headers
+// is a CDN edge serving the site, not its origin.
+func (f *Finder) check(reference *scanner.Response, c Candidate) models.OriginCandidate {
+	result := models.OriginCandidate{IP: c.IP, Source: c.Source, Host: c.Host}
+
+	targets := []string{reference.URL}
+	if u, err := url.Parse(reference.URL); err == nil && u.Scheme == "https" {
+		u.Scheme = "http"
+		targets = append(targets, u.String())
+	}
+
+	var resp *scanner.DirectResponse
+	var err error
+	for _, target := range targets {
+		if resp, err = f.scan.FetchDirect(target, c.IP, maxPageBody); err == nil {
+			result.URL = target
+			break
+		}
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.StatusCode = resp.StatusCode
+	result.Title = scanner.Title(resp.Body)
+	result.CertificateValid = resp.CertificateValid
+	if cdns := f.det.DetectCDNs(resp.Headers(), nil); len(cdns) > 0 {
+		result.CDN = cdns[0].Name
+		return result
+	}
+	result.Exposed, result.Distance = scanner.Compare(reference, resp.Response)
+	return result
+}
+
+// isPublic reports whether ip is routable on the internet.
+func isPublic(ip net.IP) bool {
+	return !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsMulticast()
+}
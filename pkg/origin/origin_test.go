@@ -0,0 +1,47 @@
+package origin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/pkg/detector"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+)
+
+const home = `<html><head><title>Example Shop</title></head><body>Welcome to the shop</body></html>`
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      http.Header
+		wantCDN     string
+		wantExposed bool
+	}{
+		{"origin", http.Header{"Server": {"nginx"}}, "", true},
+		{"cdn edge", http.Header{"Server": {"cloudflare"}, "Cf-Ray": {"8a1b2c3d4e5f-AMS"}}, "Cloudflare", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, values := range tt.header {
+					w.Header()[key] = values
+				}
+				_, _ = w.Write([]byte(home))
+			}))
+			defer srv.Close()
+
+			f := New(scanner.New(nil), nil, detector.New())
+			reference := &scanner.Response{URL: srv.URL + "/", StatusCode: http.StatusOK, Header: http.Header{}, Body: home}
+			got := f.check(reference, Candidate{IP: "127.0.0.1", Source: "subdomain", Host: "shop.example.com"})
+
+			if got.Error != "" {
+				t.Fatalf("check() error: %s", got.Error)
+			}
+			if got.CDN != tt.wantCDN || got.Exposed != tt.wantExposed {
+				t.Errorf("check() = cdn %q, exposed %v, want cdn %q, exposed %v", got.CDN, got.Exposed, tt.wantCDN, tt.wantExposed)
+			}
+		})
+	}
+}
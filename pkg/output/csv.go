@@ -0,0 +1,125 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// csvHeader names the columns of the CSV format.
+var csvHeader = []string{"domain", "section", "name", "value", "detail"}
+
+// csvWriter writes one row per finding, so scans of many domains can be
+// concatenated and filtered in a spreadsheet. Rows follow report order.
+// Cells come from the scanned site, so those a spreadsheet would run as a
+// formula are escaped.
+type csvWriter struct{}
+
+func (csvWriter) Extension() string { return "csv" }
+
+func (csvWriter) Write(w io.Writer, result *models.ScanResult) error {
+	cw := csv.NewWriter(w)
+	row := func(section, name, value, detail string) {
+		_ = cw.Write([]string{csvCell(result.Domain), section, csvCell(name), csvCell(value), csvCell(detail)})
+	}
+	value := func(section, name, v string) {
+		if v != "" {
+			row(section, name, v, "")
+		}
+	}
+	_ = cw.Write(csvHeader)
+
+	if result.HTTP != nil {
+		row("http", "status_code", strconv.Itoa(result.HTTP.StatusCode), "")
+		value("http", "server", result.HTTP.Server)
+		value("http", "protocol", result.HTTP.Protocol)
+		row("http", "response_time_ms", strconv.FormatInt(result.HTTP.ResponseTime, 10), "")
+	}
+	if result.Page != nil {
+		value("page", "title", result.Page.Title)
+	}
+	if result.Technologies != nil {
+		for _, tech := range result.Technologies.Items {
+			row("technology", tech.Name, tech.Version, strings.Join(tech.Categories, ";"))
+		}
+	}
+	for _, id := range result.TrackingIDs {
+		row("tracking_id", id.Service, id.ID, id.Type)
+	}
+	for _, v := range result.Vulnerabilities {
+		row("vulnerability", v.ID, strings.TrimSpace(v.Technology+" "+v.Version), strconv.FormatFloat(v.CVSS, 'f', 1, 64))
+	}
+	for _, cdn := range result.CDNs {
+		row("cdn", cdn.Name, "", "")
+	}
+	value("waf", "name", result.WAF)
+	value("cloud_provider", "name", result.CloudProvider)
+	if result.DNS != nil {
+		records := []struct {
+			name   string
+			values []string
+		}{
+			{"a", result.DNS.A},
+			{"aaaa", result.DNS.AAAA},
+			{"cname", result.DNS.CNAME},
+			{"mx", result.DNS.MX},
+			{"ns", result.DNS.NS},
+			{"txt", result.DNS.TXT},
+		}
+		for _, r := range records {
+			for _, value := range r.values {
+				row("dns", r.name, value, "")
+			}
+		}
+	}
+	if result.TLS != nil {
+		value("tls", "version", result.TLS.Version)
+		value("tls", "subject", result.TLS.Subject)
+		value("tls", "issuer", result.TLS.Issuer)
+		row("tls", "not_after", result.TLS.NotAfter.Format("2006-01-02"), "")
+		value("tls", "fingerprint_sha256", result.TLS.Fingerprint)
+	}
+	for _, name := range sortedKeys(result.SecurityHeaders) {
+		row("security_header", name, result.SecurityHeaders[name], "")
+	}
+	for _, e := range result.Exposures {
+		row("exposure", e.Path, strconv.Itoa(e.StatusCode), e.Severity)
+	}
+	for _, subdomain := range result.Subdomains {
+		row("subdomain", subdomain, "", "")
+	}
+	for _, name := range sortedKeys(result.Stages) {
+		stage := result.Stages[name]
+		detail := stage.Error
+		if detail == "" {
+			detail = stage.Reason
+		}
+		row("stage", name, stage.Status, detail)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell prefixes s with a single quote when it starts with a character
+// that makes spreadsheets read a cell as a formula.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// sortedKeys returns the keys of m, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"nginx", "nginx"},
+		{"1.2.3", "1.2.3"},
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+1+1", "'+1+1"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+	}
+
+	for _, tt := range tests {
+		if got := csvCell(tt.in); got != tt.want {
+			t.Errorf("csvCell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	result := &models.ScanResult{
+		Domain: "example.com",
+		Page:   &models.Page{Title: "=cmd|' /C calc'!A0"},
+	}
+
+	var buf bytes.Buffer
+	if err := (csvWriter{}).Write(&buf, result); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"example.com", "page", "title", "'=cmd|' /C calc'!A0", ""}
+	if len(rows) != 2 || len(rows[1]) != len(want) {
+		t.Fatalf("rows = %q", rows)
+	}
+	for i := range want {
+		if rows[1][i] != want[i] {
+			t.Errorf("column %s = %q, want %q", rows[0][i], rows[1][i], want[i])
+		}
+	}
+}
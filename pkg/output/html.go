@@ -0,0 +1,29 @@
+package output
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+//go:embed templates/report.html
+var embeddedTemplates embed.FS
+
+// htmlReport is the template of the HTML format. It ships with the binary,
+// so failing to parse it is a packaging bug.
+var htmlReport = template.Must(template.New("report.html").Funcs(template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+}).ParseFS(embeddedTemplates, "templates/report.html"))
+
+// htmlWriter writes a self-contained HTML report.
+type htmlWriter struct{}
+
+func (htmlWriter) Extension() string { return "html" }
+
+func (htmlWriter) Write(w io.Writer, result *models.ScanResult) error {
+	return htmlReport.Execute(w, result)
+}
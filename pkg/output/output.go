@@ -1,10 +1,10 @@
 package output
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,10 +13,11 @@ import (
 
 const (
 	maxSubdomainsDisplay = 50
-	lineWidth           = 80
-	sectionWidth        = 40
-	filePermissions     = 0644
-	dirPermissions      = 0755
+	lineWidth            = 80
+	sectionWidth         = 40
+	filePermissions      = 0644
+	dirPermissions       = 0755
+	maxSummaryIDs        = 5
 )
 
 // Formatter handles output formatting.
@@ -29,7 +30,12 @@ func New() *Formatter {
 
 // PrintBanner displays the application banner.
 func (f *Formatter) PrintBanner() {
-	banner := `
+	fmt.Println(f.Banner())
+}
+
+// Banner returns the application banner.
+func (f *Formatter) Banner() string {
+	return `
 ╔═══════════════════════════════════════════════════════════════════════════╗
 ║                                                                           ║
 ║   ██████╗  █████╗ ███╗   ██╗██╗  ██╗██╗     ███████╗                     ║
@@ -47,102 +53,230 @@ func (f *Formatter) PrintBanner() {
 ║                                                                           ║
 ╚═══════════════════════════════════════════════════════════════════════════╝
 `
-	fmt.Println(banner)
 }
 
 // PrintSummary displays a summary of scan results.
 func (f *Formatter) PrintSummary(result *models.ScanResult) {
-	fmt.Println("\n" + strings.Repeat("=", lineWidth))
-	fmt.Println("📊 SCAN SUMMARY")
-	fmt.Println(strings.Repeat("=", lineWidth))
-	fmt.Printf("\n🎯 Domain:          %s\n", result.Domain)
-	fmt.Printf("🕐 Timestamp:       %s\n", result.Timestamp.Format(time.RFC1123))
+	WriteSummary(os.Stdout, result)
+}
+
+// WriteSummary writes the console summary of a scan to w.
+func WriteSummary(w io.Writer, result *models.ScanResult) {
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", lineWidth))
+	fmt.Fprintln(w, "📊 SCAN SUMMARY")
+	fmt.Fprintln(w, strings.Repeat("=", lineWidth))
+	fmt.Fprintf(w, "\n🎯 Domain:          %s\n", result.Domain)
+	fmt.Fprintf(w, "🕐 Timestamp:       %s\n", result.Timestamp.Format(time.RFC1123))
 
 	if result.HTTP != nil {
-		fmt.Printf("\n🌐 HTTP Status:     %d\n", result.HTTP.StatusCode)
-		fmt.Printf("⚡ Response Time:   %dms\n", result.HTTP.ResponseTime)
+		fmt.Fprintf(w, "\n🌐 HTTP Status:     %d\n", result.HTTP.StatusCode)
+		fmt.Fprintf(w, "⚡ Response Time:   %dms\n", result.HTTP.ResponseTime)
 		if result.HTTP.Server != "" {
-			fmt.Printf("🖥️  Server:          %s\n", result.HTTP.Server)
+			fmt.Fprintf(w, "🖥️  Server:          %s\n", result.HTTP.Server)
 		}
 	}
 
+	if result.HTTP != nil && result.HTTP.Protocols != nil {
+		fmt.Fprintf(w, "🚦 Protocols:       %s\n", strings.Join(supportedProtocols(result.HTTP.Protocols), ", "))
+	}
+
+	if result.Page != nil && result.Page.Title != "" {
+		fmt.Fprintf(w, "📄 Title:           %s\n", result.Page.Title)
+	}
+
+	if result.Favicon != nil {
+		fmt.Fprintf(w, "🖼️  Favicon Hash:    %d\n", result.Favicon.MMH3)
+	}
+
 	if result.DNS != nil && len(result.DNS.A) > 0 {
-		fmt.Printf("\n🔍 IP Address:      %s\n", result.DNS.A[0])
+		fmt.Fprintf(w, "\n🔍 IP Address:      %s\n", result.DNS.A[0])
 	}
 
 	if result.Technologies != nil {
 		if result.Technologies.CMS != "" {
-			fmt.Printf("📦 CMS:             %s\n", result.Technologies.CMS)
+			fmt.Fprintf(w, "📦 CMS:             %s\n", describeTechnology(result.Technologies, result.Technologies.CMS))
 		}
 		if len(result.Technologies.Libraries) > 0 {
-			fmt.Printf("📚 Libraries:       %s\n", strings.Join(result.Technologies.Libraries, ", "))
+			fmt.Fprintf(w, "📚 Libraries:       %s\n", strings.Join(technologyLabels(result.Technologies, result.Technologies.Libraries), ", "))
+		}
+	}
+
+	if services := thirdPartyServices(result.Technologies); len(services) > 0 {
+		distinct := make(map[string]bool)
+		for _, names := range services {
+			for _, name := range names {
+				distinct[name] = true
+			}
 		}
+		fmt.Fprintf(w, "🧩 Third Parties:   %d services\n", len(distinct))
+	}
+
+	if len(result.TrackingIDs) > 0 {
+		fmt.Fprintf(w, "🔗 Tracking IDs:    %s\n", strings.Join(trackingIDList(result.TrackingIDs, maxSummaryIDs), ", "))
+	}
+
+	if len(result.Vulnerabilities) > 0 {
+		fmt.Fprintf(w, "🩹 Known CVEs:      %d (highest CVSS %.1f)\n",
+			len(result.Vulnerabilities), result.Vulnerabilities[0].CVSS)
+	}
+
+	if result.HTTPSecurity != nil && len(result.HTTPSecurity.Findings) > 0 {
+		fmt.Fprintf(w, "🧪 HTTP Security:   %d issues (most severe: %s)\n",
+			len(result.HTTPSecurity.Findings), result.HTTPSecurity.Findings[0].Severity)
+	}
+
+	if len(result.Exposures) > 0 {
+		fmt.Fprintf(w, "🗝️  Exposed Paths:   %d (most severe: %s)\n",
+			len(result.Exposures), result.Exposures[0].Severity)
+	}
+
+	if js := result.Scripts; js != nil {
+		fmt.Fprintf(w, "📦 Scripts:         %d analyzed, %d secrets, %d endpoints, %d source maps\n",
+			len(js.Files), countFindings(js, models.ScriptSecret), countFindings(js, models.ScriptEndpoint), len(js.SourceMaps))
+	}
+
+	if len(result.CDNs) > 0 {
+		fmt.Fprintf(w, "🌐 CDN:             %s\n", strings.Join(cdnNames(result.CDNs), ", "))
+	} else if result.CDN != "" {
+		fmt.Fprintf(w, "🌐 CDN:             %s\n", result.CDN)
 	}
 
-	if result.CDN != "" {
-		fmt.Printf("🌐 CDN:             %s\n", result.CDN)
+	if exposed := exposedOrigins(result.Origin); len(exposed) > 0 {
+		fmt.Fprintf(w, "🎯 Exposed Origins: %s\n", strings.Join(exposed, ", "))
 	}
 
 	if result.WAF != "" {
-		fmt.Printf("🛡️  WAF:             %s\n", result.WAF)
+		fmt.Fprintf(w, "🛡️  WAF:             %s\n", result.WAF)
 	}
 
 	if result.CloudProvider != "" {
-		fmt.Printf("☁️  Cloud Provider:  %s\n", result.CloudProvider)
+		fmt.Fprintf(w, "☁️  Cloud Provider:  %s\n", result.CloudProvider)
 	}
 
 	if result.Geolocation != nil {
-		fmt.Printf("\n🌍 Location:        %s, %s\n", result.Geolocation.City, result.Geolocation.Country)
+		fmt.Fprintf(w, "\n🌍 Location:        %s, %s\n", result.Geolocation.City, result.Geolocation.Country)
 		if result.Geolocation.ISP != "" {
-			fmt.Printf("🏢 ISP:             %s\n", result.Geolocation.ISP)
+			fmt.Fprintf(w, "🏢 ISP:             %s\n", result.Geolocation.ISP)
 		}
 	}
 
 	if result.TLS != nil {
-		fmt.Printf("\n🔐 TLS Version:     %s\n", result.TLS.Version)
-		fmt.Printf("📜 Certificate:     %s\n", result.TLS.Subject)
-		fmt.Printf("   Expires:         %s\n", result.TLS.NotAfter.Format("2006-01-02"))
+		fmt.Fprintf(w, "\n🔐 TLS Version:     %s\n", result.TLS.Version)
+		fmt.Fprintf(w, "📜 Certificate:     %s\n", result.TLS.Subject)
+		fmt.Fprintf(w, "   Expires:         %s\n", result.TLS.NotAfter.Format("2006-01-02"))
 	}
 
 	if len(result.Subdomains) > 0 {
-		fmt.Printf("\n🔎 Subdomains:      %d found\n", len(result.Subdomains))
+		fmt.Fprintf(w, "\n🔎 Subdomains:      %d found\n", subdomainCount(result))
 	}
 
+	if failed := failedStages(result); len(failed) > 0 {
+		labels := make([]string, 0, len(failed))
+		for _, name := range failed {
+			labels = append(labels, fmt.Sprintf("%s (%s)", name, result.Stages[name].ErrorClass))
+		}
+		fmt.Fprintf(w, "\n⚠️  Incomplete:      %s\n", strings.Join(labels, ", "))
+	}
+
+	fmt.Fprintln(w, strings.Repeat("=", lineWidth))
+}
+
+// relationLabels names link kinds in the related assets listing.
+var relationLabels = map[string]string{
+	models.RelationTrackingID:  "tracking ID",
+	models.RelationCertificate: "certificate",
+	models.RelationSAN:         "certificate name",
+	models.RelationFavicon:     "favicon mmh3",
+	models.RelationNameservers: "name servers",
+	models.RelationIP:          "IP address",
+}
+
+// PrintRelated prints the cluster of domains related to a scanned domain.
+func (f *Formatter) PrintRelated(cluster *models.RelatedCluster) {
+	fmt.Println("\n" + strings.Repeat("=", lineWidth))
+	fmt.Println("🕸️  RELATED ASSETS")
 	fmt.Println(strings.Repeat("=", lineWidth))
+	fmt.Printf("\n🎯 Domain:          %s\n", cluster.Domain)
+	fmt.Printf("📁 Scans indexed:   %d\n", cluster.Scanned)
+
+	if len(cluster.Members) == 0 {
+		fmt.Println("\nNo related domains found in the stored scan results.")
+		return
+	}
+
+	fmt.Printf("🔗 Related:         %d domains\n\n", len(cluster.Members))
+	for _, member := range cluster.Members {
+		fmt.Printf("  %s (%d hop", member.Domain, member.Distance)
+		if member.Distance > 1 {
+			fmt.Print("s")
+		}
+		fmt.Println(")")
+		for _, link := range member.Links {
+			fmt.Printf("    - %s %s, shared with %s\n", relationLabels[link.Kind], link.Value, link.Via)
+		}
+	}
 }
 
-// SaveJSON saves results as JSON file.
-func (f *Formatter) SaveJSON(result *models.ScanResult, outputPath string) error {
-	// Create output directory if it doesn't exist
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+// changeMarks prefixes changes in the diff listing.
+var changeMarks = map[string]string{
+	models.ChangeAdded:   "+",
+	models.ChangeRemoved: "-",
+	models.ChangeChanged: "~",
+}
+
+// PrintDiff prints the changes between two scans, grouped by section.
+func (f *Formatter) PrintDiff(diff *models.ScanDiff) {
+	fmt.Println("\n" + strings.Repeat("=", lineWidth))
+	fmt.Println("🔀 SCAN DIFF")
+	fmt.Println(strings.Repeat("=", lineWidth))
+	if diff.OldDomain != "" {
+		fmt.Printf("\n🎯 Domain:          %s -> %s\n", diff.OldDomain, diff.Domain)
+	} else {
+		fmt.Printf("\n🎯 Domain:          %s\n", diff.Domain)
 	}
+	fmt.Printf("🕐 Old scan:        %s\n", diff.OldTimestamp.Format(time.RFC1123))
+	fmt.Printf("🕐 New scan:        %s\n", diff.NewTimestamp.Format(time.RFC1123))
 
-	// Marshal to JSON with indentation
-	data, err := json.MarshalIndent(result, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+	if len(diff.Changes) == 0 {
+		fmt.Println("\nNo changes.")
+		return
 	}
 
-	// Write to file
-	if err := os.WriteFile(outputPath, data, filePermissions); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	fmt.Printf("🔀 Changes:         %d\n", len(diff.Changes))
+	section := ""
+	for _, c := range diff.Changes {
+		if c.Section != section {
+			fmt.Printf("\n  %s\n", c.Section)
+			section = c.Section
+		}
+		switch {
+		case c.Kind == models.ChangeChanged && c.Item == c.New:
+			fmt.Printf("    %s %s (was %s)\n", changeMarks[c.Kind], c.Item, c.Old)
+		case c.Kind == models.ChangeChanged:
+			fmt.Printf("    %s %s: %s -> %s\n", changeMarks[c.Kind], c.Item, orNone(c.Old), orNone(c.New))
+		case c.New != "" && c.New != c.Item:
+			fmt.Printf("    %s %s %s\n", changeMarks[c.Kind], c.Item, c.New)
+		case c.Old != "" && c.Old != c.Item:
+			fmt.Printf("    %s %s %s\n", changeMarks[c.Kind], c.Item, c.Old)
+		default:
+			fmt.Printf("    %s %s\n", changeMarks[c.Kind], c.Item)
+		}
 	}
+}
 
-	fmt.Printf("\n✅ JSON report saved: %s\n", outputPath)
-	return nil
+// SaveJSON saves results as JSON file.
+func (f *Formatter) SaveJSON(result *models.ScanResult, outputPath string) error {
+	return Save(FormatJSON, outputPath, result)
 }
 
 // SaveText saves results as human-readable text file.
 func (f *Formatter) SaveText(result *models.ScanResult, outputPath string) error {
-	// Create output directory if it doesn't exist
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, dirPermissions); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
+	return Save(FormatText, outputPath, result)
+}
 
-	// Build text report
+// TextReport renders results as the human-readable report SaveText writes.
+// Sections without data are left out.
+func (f *Formatter) TextReport(result *models.ScanResult) string {
 	var sb strings.Builder
 	sb.WriteString(strings.Repeat("=", lineWidth) + "\n")
 	sb.WriteString("RANKLE - Web Infrastructure Reconnaissance Report\n")
@@ -163,6 +297,25 @@ func (f *Formatter) SaveText(result *models.ScanResult, outputPath string) error
 		if result.HTTP.ContentType != "" {
 			sb.WriteString(fmt.Sprintf("Content-Type:   %s\n", result.HTTP.ContentType))
 		}
+		if body := result.HTTP.Body; body != nil {
+			size := fmt.Sprintf("%d bytes", body.Size)
+			if body.Truncated {
+				size += " (truncated)"
+			}
+			sb.WriteString(fmt.Sprintf("Body Size:      %s\n", size))
+			if body.Charset != "" {
+				sb.WriteString(fmt.Sprintf("Charset:        %s\n", body.Charset))
+			}
+		}
+		if result.HTTP.Protocol != "" {
+			sb.WriteString(fmt.Sprintf("Protocol:       %s\n", result.HTTP.Protocol))
+		}
+		if p := result.HTTP.Protocols; p != nil {
+			sb.WriteString(fmt.Sprintf("ALPN:           %s\n", p.ALPN))
+			sb.WriteString(fmt.Sprintf("HTTP/1.1:       %t\n", p.HTTP11))
+			sb.WriteString(fmt.Sprintf("HTTP/2:         %t\n", p.HTTP2))
+			sb.WriteString(fmt.Sprintf("HTTP/3:         %s\n", describeHTTP3(p)))
+		}
 		sb.WriteString("\n")
 	}
 
@@ -188,35 +341,327 @@ func (f *Formatter) SaveText(result *models.ScanResult, outputPath string) error
 		sb.WriteString("\n")
 	}
 
+	// Page Section
+	if result.Page != nil {
+		sb.WriteString("PAGE\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		if result.Page.Title != "" {
+			sb.WriteString(fmt.Sprintf("Title:          %s\n", result.Page.Title))
+		}
+		if result.Page.Generator != "" {
+			sb.WriteString(fmt.Sprintf("Generator:      %s\n", result.Page.Generator))
+		}
+		if result.Page.Favicon != "" {
+			sb.WriteString(fmt.Sprintf("Favicon:        %s\n", result.Page.Favicon))
+		}
+		sb.WriteString(fmt.Sprintf("Scripts:        %d (%d inline)\n",
+			len(result.Page.Scripts), len(result.Page.InlineScriptHashes)))
+		sb.WriteString(fmt.Sprintf("Stylesheets:    %d\n", len(result.Page.Stylesheets)))
+		sb.WriteString(fmt.Sprintf("Forms:          %d\n", len(result.Page.Forms)))
+		if len(result.Page.ExternalDomains) > 0 {
+			sb.WriteString(fmt.Sprintf("External:       %s\n", strings.Join(result.Page.ExternalDomains, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Favicon Section
+	if result.Favicon != nil {
+		sb.WriteString("FAVICON\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		sb.WriteString(fmt.Sprintf("URL:            %s (%d bytes)\n", result.Favicon.URL, result.Favicon.Size))
+		sb.WriteString(fmt.Sprintf("MMH3:           %d\n", result.Favicon.MMH3))
+		sb.WriteString(fmt.Sprintf("MD5:            %s\n", result.Favicon.MD5))
+		sb.WriteString(fmt.Sprintf("SHA256:         %s\n", result.Favicon.SHA256))
+		sb.WriteString("\n")
+	}
+
+	// Well-Known Files Section
+	if wk := result.WellKnown; wk != nil {
+		sb.WriteString("WELL-KNOWN FILES\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		if wk.Robots != nil {
+			sb.WriteString(fmt.Sprintf("robots.txt:     %d disallowed, %d allowed, %d sitemaps\n",
+				len(wk.Robots.Disallowed), len(wk.Robots.Allowed), len(wk.Robots.Sitemaps)))
+			for _, path := range wk.Robots.Disallowed {
+				sb.WriteString(fmt.Sprintf("  - Disallow: %s\n", path))
+			}
+		}
+		if wk.Sitemap != nil {
+			if wk.Sitemap.Index {
+				sb.WriteString(fmt.Sprintf("Sitemap:        index of %d sitemaps (%s)\n", len(wk.Sitemap.Sitemaps), wk.Sitemap.URL))
+			} else {
+				sb.WriteString(fmt.Sprintf("Sitemap:        %d URLs (%s)\n", wk.Sitemap.URLCount, wk.Sitemap.URL))
+			}
+		}
+		if sec := wk.SecurityTxt; sec != nil {
+			status := "valid"
+			if len(sec.Errors) > 0 {
+				status = strings.Join(sec.Errors, "; ")
+			}
+			sb.WriteString(fmt.Sprintf("security.txt:   %s (signed: %t)\n", status, sec.Signed))
+			for _, contact := range sec.Contact {
+				sb.WriteString(fmt.Sprintf("  - Contact: %s\n", contact))
+			}
+			if sec.Expires != nil {
+				sb.WriteString(fmt.Sprintf("  - Expires: %s\n", sec.Expires.Format("2006-01-02")))
+			}
+		}
+		if wk.OpenID != nil {
+			sb.WriteString(fmt.Sprintf("OpenID issuer:  %s\n", wk.OpenID.Issuer))
+		}
+		if wk.Humans != nil {
+			sb.WriteString(fmt.Sprintf("humans.txt:     %s\n", wk.Humans.URL))
+		}
+		if wk.Ads != nil {
+			sb.WriteString(fmt.Sprintf("ads.txt:        %d records (%d direct, %d reseller), %d ad systems\n",
+				wk.Ads.Records, wk.Ads.Direct, wk.Ads.Reseller, len(wk.Ads.AdSystems)))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Technologies Section
 	if result.Technologies != nil {
 		sb.WriteString("DETECTED TECHNOLOGIES\n")
 		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
 		if result.Technologies.CMS != "" {
-			sb.WriteString(fmt.Sprintf("CMS:            %s\n", result.Technologies.CMS))
+			sb.WriteString(fmt.Sprintf("CMS:            %s\n", describeTechnology(result.Technologies, result.Technologies.CMS)))
 		}
 		if len(result.Technologies.Libraries) > 0 {
-			sb.WriteString(fmt.Sprintf("Libraries:      %s\n", strings.Join(result.Technologies.Libraries, ", ")))
+			sb.WriteString(fmt.Sprintf("Libraries:      %s\n", strings.Join(technologyLabels(result.Technologies, result.Technologies.Libraries), ", ")))
 		}
 		if len(result.Technologies.Frameworks) > 0 {
-			sb.WriteString(fmt.Sprintf("Frameworks:     %s\n", strings.Join(result.Technologies.Frameworks, ", ")))
+			sb.WriteString(fmt.Sprintf("Frameworks:     %s\n", strings.Join(technologyLabels(result.Technologies, result.Technologies.Frameworks), ", ")))
+		}
+		if len(result.Technologies.Items) > 0 {
+			sb.WriteString("Detections:\n")
+			for _, item := range result.Technologies.Items {
+				sb.WriteString(fmt.Sprintf("  - %s\n", item.String()))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Third-Party Services Section
+	if services := thirdPartyServices(result.Technologies); len(services) > 0 || len(result.TrackingIDs) > 0 {
+		sb.WriteString("THIRD-PARTY SERVICES\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		for _, category := range thirdPartyCategories {
+			if names := services[category]; len(names) > 0 {
+				sb.WriteString(fmt.Sprintf("%-16s%s\n", category+":", strings.Join(names, ", ")))
+			}
+		}
+		if len(result.TrackingIDs) > 0 {
+			sb.WriteString("Tracking IDs:\n")
+			for _, id := range result.TrackingIDs {
+				sb.WriteString(fmt.Sprintf("  - %s %s (%s)\n", id.Service, id.ID, id.Type))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Vulnerabilities Section
+	if len(result.Vulnerabilities) > 0 {
+		sb.WriteString(fmt.Sprintf("KNOWN VULNERABILITIES (%d)\n", len(result.Vulnerabilities)))
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		for _, v := range result.Vulnerabilities {
+			sb.WriteString(fmt.Sprintf("  - %s [%s %.1f] %s %s", v.ID, v.Severity, v.CVSS, v.Technology, v.Version))
+			if len(v.FixedVersions) > 0 {
+				sb.WriteString(fmt.Sprintf(" (fixed in %s)", strings.Join(v.FixedVersions, ", ")))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	// HTTP Security Section
+	if sec := result.HTTPSecurity; sec != nil {
+		sb.WriteString("HTTP SECURITY\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		if len(sec.AllowedMethods) > 0 {
+			sb.WriteString(fmt.Sprintf("Allow:          %s\n", strings.Join(sec.AllowedMethods, ", ")))
+		}
+		for _, m := range sec.Methods {
+			sb.WriteString(fmt.Sprintf("  - %-9s HTTP %d (enabled: %t)\n", m.Method, m.StatusCode, m.Enabled))
+		}
+		for _, t := range sec.CORS {
+			allow := t.AllowOrigin
+			if allow == "" {
+				allow = "-"
+			}
+			sb.WriteString(fmt.Sprintf("  - CORS %-9s %s -> %s (credentials: %t)\n", t.Name, t.Origin, allow, t.AllowCredentials))
+		}
+		if len(sec.Findings) > 0 {
+			sb.WriteString("Findings:\n")
+			for _, f := range sec.Findings {
+				sb.WriteString(fmt.Sprintf("  - [%s] %s\n      %s\n", strings.ToUpper(f.Severity), f.Title, f.Evidence))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Soft-404 Baseline Section
+	if result.Soft404 != nil {
+		sb.WriteString("SOFT-404 BASELINE\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		sb.WriteString(fmt.Sprintf("Catch-all:      %t\n", result.Soft404.CatchAll))
+		for _, sample := range result.Soft404.Samples {
+			sb.WriteString(fmt.Sprintf("  - %s -> HTTP %d, %d bytes, simhash %s", sample.Path, sample.StatusCode, sample.Length, sample.SimHash))
+			if sample.Redirected {
+				sb.WriteString(fmt.Sprintf(", redirected to %s", sample.FinalURL))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	// Exposures Section
+	if len(result.Exposures) > 0 {
+		sb.WriteString(fmt.Sprintf("EXPOSED PATHS (%d)\n", len(result.Exposures)))
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		for _, e := range result.Exposures {
+			sb.WriteString(fmt.Sprintf("  - [%s] %s %s (HTTP %d)", strings.ToUpper(e.Severity), e.Path, e.Name, e.StatusCode))
+			if e.Evidence != "" {
+				sb.WriteString(fmt.Sprintf(": %s", e.Evidence))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	// Script Analysis Section
+	if js := result.Scripts; js != nil {
+		sb.WriteString(fmt.Sprintf("JAVASCRIPT ANALYSIS (%d files)\n", len(js.Files)))
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		for _, file := range js.Files {
+			if file.Error != "" {
+				sb.WriteString(fmt.Sprintf("  - %s: %s\n", file.URL, file.Error))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("  - %s (HTTP %d, %d bytes", file.URL, file.StatusCode, file.Size))
+			if file.Truncated {
+				sb.WriteString(", truncated")
+			}
+			sb.WriteString(")\n")
+		}
+		for _, sm := range js.SourceMaps {
+			if sm.Error != "" {
+				sb.WriteString(fmt.Sprintf("  Source map %s: %s\n", sm.URL, sm.Error))
+				continue
+			}
+			if sm.StatusCode != 0 && sm.StatusCode != 200 {
+				sb.WriteString(fmt.Sprintf("  Source map %s: HTTP %d\n", sm.URL, sm.StatusCode))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("  Source map %s: %d sources, %d application files, %d packages\n",
+				sm.URL, sm.Sources, len(sm.Files), len(sm.Packages)))
+			for _, file := range sm.Files {
+				sb.WriteString(fmt.Sprintf("    file    %s\n", file))
+			}
+			for _, pkg := range sm.Packages {
+				if pkg.Version != "" {
+					sb.WriteString(fmt.Sprintf("    package %s@%s\n", pkg.Name, pkg.Version))
+				} else {
+					sb.WriteString(fmt.Sprintf("    package %s\n", pkg.Name))
+				}
+			}
+		}
+		for _, f := range js.Findings {
+			location := fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
+			if f.Kind == models.ScriptSecret {
+				sb.WriteString(fmt.Sprintf("  [%s] %s %s at %s\n", strings.ToUpper(f.Severity), f.Name, f.Value, location))
+			} else {
+				sb.WriteString(fmt.Sprintf("  %-11s %s at %s\n", f.Kind, f.Value, location))
+			}
 		}
 		sb.WriteString("\n")
 	}
 
 	// Infrastructure Section
-	sb.WriteString("INFRASTRUCTURE\n")
-	sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
-	if result.CDN != "" {
-		sb.WriteString(fmt.Sprintf("CDN:            %s\n", result.CDN))
+	if len(result.CDNs) > 0 || result.CDN != "" || result.WAF != "" || result.CloudProvider != "" {
+		sb.WriteString("INFRASTRUCTURE\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		if len(result.CDNs) > 0 {
+			for i, cdn := range result.CDNs {
+				label := "CDN:"
+				if i > 0 {
+					label = ""
+				}
+				descs := make([]string, 0, len(cdn.Evidence))
+				for _, e := range cdn.Evidence {
+					descs = append(descs, e.Describe())
+				}
+				sb.WriteString(fmt.Sprintf("%-16s%s (%s)\n", label, cdn.Name, strings.Join(descs, ", ")))
+			}
+		} else if result.CDN != "" {
+			sb.WriteString(fmt.Sprintf("CDN:            %s\n", result.CDN))
+		}
+		if result.WAF != "" {
+			sb.WriteString(fmt.Sprintf("WAF:            %s\n", result.WAF))
+		}
+		if result.CloudProvider != "" {
+			sb.WriteString(fmt.Sprintf("Cloud:          %s\n", result.CloudProvider))
+		}
+		sb.WriteString("\n")
 	}
-	if result.WAF != "" {
-		sb.WriteString(fmt.Sprintf("WAF:            %s\n", result.WAF))
+
+	// Origin Exposure Section
+	if o := result.Origin; o != nil {
+		sb.WriteString("ORIGIN EXPOSURE\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		if len(o.FrontedIPs) > 0 {
+			sb.WriteString(fmt.Sprintf("Fronted IPs:    %s\n", strings.Join(o.FrontedIPs, ", ")))
+		}
+		sb.WriteString(fmt.Sprintf("Candidates:     %d\n", len(o.Candidates)))
+		for _, c := range o.Candidates {
+			source := c.Source
+			if c.Host != "" {
+				source += " " + c.Host
+			}
+			if c.Error != "" {
+				sb.WriteString(fmt.Sprintf("  - %s (%s): unreachable\n", c.IP, source))
+				continue
+			}
+			if c.CDN != "" {
+				sb.WriteString(fmt.Sprintf("  - %s (%s): HTTP %d -> %s edge, not an origin\n", c.IP, source, c.StatusCode, c.CDN))
+				continue
+			}
+			status := "different page"
+			if c.Exposed {
+				status = "EXPOSED ORIGIN"
+			}
+			sb.WriteString(fmt.Sprintf("  - %s (%s): HTTP %d, simhash distance %d, valid certificate: %t -> %s\n",
+				c.IP, source, c.StatusCode, c.Distance, c.CertificateValid, status))
+		}
+		sb.WriteString("\n")
 	}
-	if result.CloudProvider != "" {
-		sb.WriteString(fmt.Sprintf("Cloud:          %s\n", result.CloudProvider))
+
+	// WAF Probe Section
+	if w := result.WAFDetails; w != nil {
+		sb.WriteString("WAF PROBE\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		sb.WriteString(fmt.Sprintf("Detected:       %t\n", w.Detected))
+		if w.Vendor != "" {
+			sb.WriteString(fmt.Sprintf("Vendor:         %s\n", w.Vendor))
+		}
+		for _, p := range w.Probes {
+			if p.Error != "" {
+				sb.WriteString(fmt.Sprintf("  - %-9s error: %s (blocked: %t)\n", p.Name, p.Error, p.Blocked))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("  - %-9s HTTP %d (blocked: %t)\n", p.Name, p.StatusCode, p.Blocked))
+		}
+		if len(w.Evidence) > 0 {
+			sb.WriteString("Evidence:\n")
+			for _, e := range w.Evidence {
+				sb.WriteString(fmt.Sprintf("  - %s", e.Describe()))
+				if e.Match != "" {
+					sb.WriteString(fmt.Sprintf(": %s", e.Match))
+				}
+				sb.WriteString("\n")
+			}
+		}
+		sb.WriteString("\n")
 	}
-	sb.WriteString("\n")
 
 	// TLS Section
 	if result.TLS != nil {
@@ -244,15 +689,29 @@ func (f *Formatter) SaveText(result *models.ScanResult, outputPath string) error
 
 	// Subdomains Section
 	if len(result.Subdomains) > 0 {
-		sb.WriteString(fmt.Sprintf("SUBDOMAINS (%d found)\n", len(result.Subdomains)))
+		sb.WriteString(fmt.Sprintf("SUBDOMAINS (%d found)\n", subdomainCount(result)))
 		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
-		for i, subdomain := range result.Subdomains {
-			if i >= maxSubdomainsDisplay {
-				remaining := len(result.Subdomains) - maxSubdomainsDisplay
-				sb.WriteString(fmt.Sprintf("... and %d more\n", remaining))
+		shown := 0
+		for _, subdomain := range result.Subdomains {
+			if shown >= maxSubdomainsDisplay {
 				break
 			}
 			sb.WriteString(fmt.Sprintf("  - %s\n", subdomain))
+			shown++
+		}
+		if remaining := subdomainCount(result) - shown; remaining > 0 {
+			sb.WriteString(fmt.Sprintf("... and %d more\n", remaining))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Failed Stages Section
+	if failed := failedStages(result); len(failed) > 0 {
+		sb.WriteString("INCOMPLETE STAGES\n")
+		sb.WriteString(strings.Repeat("-", sectionWidth) + "\n")
+		for _, name := range failed {
+			stage := result.Stages[name]
+			sb.WriteString(fmt.Sprintf("%-15s %-8s %s\n", name, stage.Status, stage.Error))
 		}
 		sb.WriteString("\n")
 	}
@@ -260,11 +719,172 @@ func (f *Formatter) SaveText(result *models.ScanResult, outputPath string) error
 	sb.WriteString(strings.Repeat("=", lineWidth) + "\n")
 	sb.WriteString("Generated by Rankle - https://github.com/javicosvml/rankle-go\n")
 
-	// Write to file
-	if err := os.WriteFile(outputPath, []byte(sb.String()), filePermissions); err != nil{
-		return fmt.Errorf("failed to write file: %w", err)
+	return sb.String()
+}
+
+// failedStages returns the names of the stages that failed or timed out,
+// sorted.
+func failedStages(result *models.ScanResult) []string {
+	var names []string
+	for name, stage := range result.Stages {
+		if stage.Status == models.StageFailed || stage.Status == models.StageTimeout {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// orNone shows empty values in change listings.
+func orNone(s string) string {
+	if s == "" {
+		return "none"
 	}
+	return s
+}
 
-	fmt.Printf("\n✅ Text report saved: %s\n", outputPath)
+// findTechnology returns the detection record for a technology name.
+func findTechnology(tech *models.Technologies, name string) *models.Technology {
+	for i := range tech.Items {
+		if tech.Items[i].Name == name {
+			return &tech.Items[i]
+		}
+	}
 	return nil
 }
+
+// describeTechnology formats a technology with version, confidence and
+// evidence, falling back to the bare name.
+func describeTechnology(tech *models.Technologies, name string) string {
+	if item := findTechnology(tech, name); item != nil {
+		return item.String()
+	}
+	return name
+}
+
+// technologyLabels returns names with their detected versions appended.
+func technologyLabels(tech *models.Technologies, names []string) []string {
+	labels := make([]string, 0, len(names))
+	for _, name := range names {
+		if item := findTechnology(tech, name); item != nil {
+			labels = append(labels, item.Label())
+			continue
+		}
+		labels = append(labels, name)
+	}
+	return labels
+}
+
+// thirdPartyCategories are the fingerprint categories listed as third-party
+// services, in report order.
+var thirdPartyCategories = []string{
+	"Analytics", "Tag managers", "A/B Testing", "Live chat", "Marketing automation",
+	"Payment processors", "Cookie compliance", "Advertising", "Retargeting",
+	"Issue trackers", "RUM", "Widgets",
+}
+
+// thirdPartyServices groups detected technologies by third-party category.
+// A technology is listed under each of its categories.
+func thirdPartyServices(tech *models.Technologies) map[string][]string {
+	if tech == nil {
+		return nil
+	}
+	services := make(map[string][]string)
+	for _, item := range tech.Items {
+		for _, category := range item.Categories {
+			for _, tp := range thirdPartyCategories {
+				if category == tp {
+					services[category] = append(services[category], item.Name)
+				}
+			}
+		}
+	}
+	return services
+}
+
+// trackingIDList formats up to limit tracking IDs, noting how many were left
+// out.
+func trackingIDList(ids []models.TrackingID, limit int) []string {
+	var list []string
+	for i, id := range ids {
+		if i == limit {
+			list = append(list, fmt.Sprintf("+%d more", len(ids)-limit))
+			break
+		}
+		list = append(list, id.ID)
+	}
+	return list
+}
+
+// countFindings counts the script findings of one kind.
+func countFindings(js *models.ScriptAnalysis, kind string) int {
+	n := 0
+	for _, f := range js.Findings {
+		if f.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// cdnNames lists the detected CDNs, outermost first.
+func cdnNames(cdns []models.CDNDetection) []string {
+	names := make([]string, len(cdns))
+	for i, cdn := range cdns {
+		names[i] = cdn.Name
+	}
+	return names
+}
+
+// exposedOrigins lists the candidate addresses that served the site's page.
+func exposedOrigins(o *models.OriginAnalysis) []string {
+	if o == nil {
+		return nil
+	}
+	var ips []string
+	for _, c := range o.Candidates {
+		if c.Exposed {
+			ips = append(ips, c.IP)
+		}
+	}
+	return ips
+}
+
+// subdomainCount returns how many subdomains the scan found, including
+// those past the display limit. Reports saved before the count was
+// recorded only have the list.
+func subdomainCount(result *models.ScanResult) int {
+	return max(result.SubdomainsFound, len(result.Subdomains))
+}
+
+// supportedProtocols lists the HTTP versions a host supports.
+func supportedProtocols(p *models.ProtocolSupport) []string {
+	var protocols []string
+	if p.HTTP11 {
+		protocols = append(protocols, "HTTP/1.1")
+	}
+	if p.HTTP2 {
+		protocols = append(protocols, "HTTP/2")
+	}
+	if p.HTTP3 {
+		protocols = append(protocols, "HTTP/3 ("+describeHTTP3(p)+")")
+	}
+	if len(protocols) == 0 {
+		protocols = append(protocols, "unknown")
+	}
+	return protocols
+}
+
+// describeHTTP3 summarizes HTTP/3 advertisement and the QUIC probe result.
+func describeHTTP3(p *models.ProtocolSupport) string {
+	switch {
+	case !p.HTTP3:
+		return "not advertised"
+	case p.QUIC == nil:
+		return "advertised via Alt-Svc"
+	case p.QUIC.Reachable:
+		return "confirmed over QUIC (" + strings.Join(p.QUIC.Versions, ", ") + ")"
+	default:
+		return "advertised, QUIC probe failed: " + p.QUIC.Error
+	}
+}
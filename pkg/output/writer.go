@@ -0,0 +1,145 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// Writer renders a scan result in one output format. Formats are looked up
+// by name in a registry; programs using rankle as a library add their own
+// with Register.
+type Writer interface {
+	// Write renders result to w.
+	Write(w io.Writer, result *models.ScanResult) error
+	// Extension is the file name extension of the format, without the dot.
+	Extension() string
+}
+
+// Built-in format names.
+const (
+	FormatSummary = "summary"
+	FormatText    = "text"
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+	FormatHTML    = "html"
+)
+
+var (
+	writersMu sync.RWMutex
+	writers   = make(map[string]Writer)
+)
+
+func init() {
+	Register(FormatSummary, summaryWriter{})
+	Register(FormatText, textWriter{})
+	Register(FormatJSON, jsonWriter{})
+	Register(FormatCSV, csvWriter{})
+	Register(FormatHTML, htmlWriter{})
+}
+
+// Register makes a format available under name. It panics if name is empty
+// or already registered, or if w is nil.
+func Register(name string, w Writer) {
+	writersMu.Lock()
+	defer writersMu.Unlock()
+	if name == "" || w == nil {
+		panic("output: Register needs a name and a writer")
+	}
+	if _, dup := writers[name]; dup {
+		panic("output: Register called twice for format " + name)
+	}
+	writers[name] = w
+}
+
+// Lookup returns the writer of the format called name.
+func Lookup(name string) (Writer, error) {
+	writersMu.RLock()
+	defer writersMu.RUnlock()
+	w, ok := writers[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q (available: %s)", name, strings.Join(formats(), ", "))
+	}
+	return w, nil
+}
+
+// Formats returns the names of the registered formats, sorted.
+func Formats() []string {
+	writersMu.RLock()
+	defer writersMu.RUnlock()
+	return formats()
+}
+
+func formats() []string {
+	names := make([]string, 0, len(writers))
+	for name := range writers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save writes result to the file at path in the format called name,
+// creating the file's directory if needed.
+func Save(name, path string, result *models.ScanResult) error {
+	w, err := Lookup(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filePermissions)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := w.Write(file, result); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s report: %w", name, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// summaryWriter writes the console summary.
+type summaryWriter struct{}
+
+func (summaryWriter) Write(w io.Writer, result *models.ScanResult) error {
+	WriteSummary(w, result)
+	return nil
+}
+
+func (summaryWriter) Extension() string { return "txt" }
+
+// textWriter writes the full text report.
+type textWriter struct{}
+
+func (textWriter) Write(w io.Writer, result *models.ScanResult) error {
+	_, err := io.WriteString(w, New().TextReport(result))
+	return err
+}
+
+func (textWriter) Extension() string { return "txt" }
+
+// jsonWriter writes the result as indented JSON.
+type jsonWriter struct{}
+
+func (jsonWriter) Write(w io.Writer, result *models.ScanResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func (jsonWriter) Extension() string { return "json" }
@@ -0,0 +1,285 @@
+// Package page parses fetched HTML into a structured page model.
+package page
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"html"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+const (
+	// maxLinks caps the number of anchor targets kept per page.
+	maxLinks = 500
+	// maxInlineScripts caps the number of inline script bodies kept in memory.
+	maxInlineScripts = 200
+)
+
+// parser accumulates page elements while walking the token stream.
+type parser struct {
+	page     *models.Page
+	base     *url.URL
+	host     string
+	seen     map[string]bool
+	external map[string]bool
+	form     *models.Form
+}
+
+// Parse tokenizes body and extracts the page model. pageURL is the final URL
+// the document was fetched from and is used to resolve relative references.
+func Parse(body, pageURL string) *models.Page {
+	p := &parser{
+		page:     &models.Page{URL: pageURL},
+		seen:     make(map[string]bool),
+		external: make(map[string]bool),
+	}
+	if base, err := url.Parse(pageURL); err == nil {
+		p.base = base
+		p.host = strings.ToLower(base.Hostname())
+	}
+
+	z := newTokenizer(body)
+	for {
+		t, ok := z.next()
+		if !ok {
+			break
+		}
+		switch t.typ {
+		case startTagToken:
+			p.startTag(&t, z)
+		case endTagToken:
+			if t.name == "form" {
+				p.closeForm()
+			}
+		}
+	}
+	p.closeForm()
+
+	p.page.ExternalDomains = make([]string, 0, len(p.external))
+	for domain := range p.external {
+		p.page.ExternalDomains = append(p.page.ExternalDomains, domain)
+	}
+	sort.Strings(p.page.ExternalDomains)
+
+	return p.page
+}
+
+// startTag dispatches a start tag to the matching extractor.
+func (p *parser) startTag(t *token, z *tokenizer) {
+	switch t.name {
+	case "base":
+		if href, ok := t.attr("href"); ok {
+			if ref := p.resolve(href); ref != "" {
+				if base, err := url.Parse(ref); err == nil {
+					p.base = base
+				}
+			}
+		}
+	case "title":
+		text := readText(z)
+		if p.page.Title == "" {
+			p.page.Title = collapseSpace(html.UnescapeString(text))
+		}
+	case "meta":
+		p.meta(t)
+	case "script":
+		p.script(t, z)
+	case "link":
+		p.link(t)
+	case "a":
+		if href, ok := t.attr("href"); ok && len(p.page.Links) < maxLinks {
+			if ref := p.resolve(href); ref != "" && p.once("a:"+ref) {
+				p.page.Links = append(p.page.Links, ref)
+			}
+		}
+	case "img", "iframe", "source", "embed", "video", "audio":
+		if src, ok := t.attr("src"); ok {
+			p.resolve(src)
+		}
+	case "form":
+		p.openForm(t)
+	case "input", "select", "textarea", "button":
+		p.input(t)
+	}
+}
+
+// meta records a <meta> element and the generator, if present.
+func (p *parser) meta(t *token) {
+	content, ok := t.attr("content")
+	if !ok {
+		return
+	}
+
+	name, _ := t.attr("name")
+	if name == "" {
+		name, _ = t.attr("property")
+	}
+	if name == "" {
+		name, _ = t.attr("http-equiv")
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return
+	}
+
+	p.page.Meta = append(p.page.Meta, models.MetaTag{Name: name, Content: content})
+	if name == "generator" && p.page.Generator == "" {
+		p.page.Generator = strings.TrimSpace(content)
+	}
+}
+
+// script records external script sources and hashes inline scripts.
+func (p *parser) script(t *token, z *tokenizer) {
+	if src, ok := t.attr("src"); ok && strings.TrimSpace(src) != "" {
+		if ref := p.resolve(src); ref != "" && p.once("script:"+ref) {
+			p.page.Scripts = append(p.page.Scripts, ref)
+		}
+		readText(z)
+		return
+	}
+
+	code := readText(z)
+	if strings.TrimSpace(code) == "" {
+		return
+	}
+
+	// CSP-style hash, so values can be compared against script-src policies.
+	sum := sha256.Sum256([]byte(code))
+	hash := "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+	if p.once("inline:" + hash) {
+		p.page.InlineScriptHashes = append(p.page.InlineScriptHashes, hash)
+		if len(p.page.InlineScripts) < maxInlineScripts {
+			p.page.InlineScripts = append(p.page.InlineScripts, code)
+		}
+	}
+}
+
+// link records stylesheets and the favicon.
+func (p *parser) link(t *token) {
+	href, ok := t.attr("href")
+	if !ok || strings.TrimSpace(href) == "" {
+		return
+	}
+	rel, _ := t.attr("rel")
+	rels := strings.Fields(strings.ToLower(rel))
+
+	ref := p.resolve(href)
+	if ref == "" {
+		return
+	}
+
+	for _, r := range rels {
+		switch r {
+		case "stylesheet":
+			if p.once("style:" + ref) {
+				p.page.Stylesheets = append(p.page.Stylesheets, ref)
+			}
+		case "icon":
+			if p.page.Favicon == "" {
+				p.page.Favicon = ref
+			}
+		}
+	}
+}
+
+// openForm starts collecting a form's inputs.
+func (p *parser) openForm(t *token) {
+	p.closeForm()
+
+	method, _ := t.attr("method")
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "" {
+		method = "GET"
+	}
+
+	action, _ := t.attr("action")
+	p.form = &models.Form{Action: p.resolve(action), Method: method}
+}
+
+// input adds a named field to the current form.
+func (p *parser) input(t *token) {
+	if p.form == nil {
+		return
+	}
+	if typ, _ := t.attr("type"); strings.EqualFold(typ, "password") {
+		p.form.HasPassword = true
+	}
+	if name, ok := t.attr("name"); ok && name != "" {
+		p.form.Inputs = append(p.form.Inputs, name)
+	}
+}
+
+// closeForm stores the current form, if any.
+func (p *parser) closeForm() {
+	if p.form == nil {
+		return
+	}
+	p.page.Forms = append(p.page.Forms, *p.form)
+	p.form = nil
+}
+
+// resolve turns ref into an absolute URL and records foreign hosts. It
+// returns an empty string for references that are not fetchable URLs.
+func (p *parser) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return ""
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if p.base != nil {
+		u = p.base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+
+	if host := strings.ToLower(u.Hostname()); host != "" && !sameSite(host, p.host) {
+		p.external[host] = true
+	}
+	return u.String()
+}
+
+// once reports whether key is seen for the first time.
+func (p *parser) once(key string) bool {
+	if p.seen[key] {
+		return false
+	}
+	p.seen[key] = true
+	return true
+}
+
+// readText consumes the text content of a raw-text element.
+func readText(z *tokenizer) string {
+	if z.rawTag == "" {
+		return ""
+	}
+	t, ok := z.next()
+	if !ok || t.typ != textToken {
+		return ""
+	}
+	return t.data
+}
+
+// sameSite reports whether host is the page host, one of its subdomains, or
+// a parent domain of it.
+func sameSite(host, pageHost string) bool {
+	if pageHost == "" {
+		return false
+	}
+	return host == pageHost ||
+		strings.HasSuffix(host, "."+pageHost) ||
+		strings.HasSuffix(pageHost, "."+host)
+}
+
+// collapseSpace trims s and folds runs of whitespace into single spaces.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,49 @@
+package page
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	body := `<!DOCTYPE html>
+<html><head>
+<title>  Example
+  Shop </title>
+<meta name="generator" content="WordPress 6.4.2">
+<link rel="stylesheet" href="/style.css">
+<link rel="icon" href="/favicon.png">
+<script src="https://cdn.example.net/jquery.min.js"></script>
+<script>window.x = 1;</script>
+</head><body>
+<a href="/about">About</a>
+<form action="/login" method="post"><input name="user"><input type="password" name="pass"></form>
+</body></html>`
+
+	p := Parse(body, "https://example.com/")
+
+	if p.Title != "Example Shop" {
+		t.Errorf("Title = %q", p.Title)
+	}
+	if p.Generator != "WordPress 6.4.2" {
+		t.Errorf("Generator = %q", p.Generator)
+	}
+	if want := []string{"https://cdn.example.net/jquery.min.js"}; !reflect.DeepEqual(p.Scripts, want) {
+		t.Errorf("Scripts = %q, want %q", p.Scripts, want)
+	}
+	if want := []string{"https://example.com/style.css"}; !reflect.DeepEqual(p.Stylesheets, want) {
+		t.Errorf("Stylesheets = %q, want %q", p.Stylesheets, want)
+	}
+	if p.Favicon != "https://example.com/favicon.png" {
+		t.Errorf("Favicon = %q", p.Favicon)
+	}
+	if len(p.InlineScriptHashes) != 1 {
+		t.Errorf("InlineScriptHashes = %q, want one", p.InlineScriptHashes)
+	}
+	if want := []string{"cdn.example.net"}; !reflect.DeepEqual(p.ExternalDomains, want) {
+		t.Errorf("ExternalDomains = %q, want %q", p.ExternalDomains, want)
+	}
+	if len(p.Forms) != 1 || !p.Forms[0].HasPassword || p.Forms[0].Method != "POST" {
+		t.Errorf("Forms = %+v", p.Forms)
+	}
+}
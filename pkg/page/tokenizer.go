@@ -0,0 +1,273 @@
+package page
+
+import (
+	"html"
+	"strings"
+)
+
+// tokenType identifies the kind of a lexical HTML token.
+type tokenType int
+
+const (
+	textToken tokenType = iota
+	startTagToken
+	endTagToken
+	commentToken
+)
+
+// attribute is a single tag attribute. Keys are lowercased, values unescaped.
+type attribute struct {
+	key   string
+	value string
+}
+
+// token is a lexical HTML token.
+type token struct {
+	typ         tokenType
+	name        string
+	attrs       []attribute
+	data        string
+	selfClosing bool
+}
+
+// attr returns the value of the named attribute and whether it is present.
+func (t *token) attr(key string) (string, bool) {
+	for _, a := range t.attrs {
+		if a.key == key {
+			return a.value, true
+		}
+	}
+	return "", false
+}
+
+// rawTextElements hold text that is not parsed as markup until their end tag.
+var rawTextElements = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"title":    true,
+	"textarea": true,
+}
+
+// tokenizer is a small, forgiving HTML tokenizer. It does not build a tree;
+// it only splits the document into tags, text and comments, which is all
+// technology fingerprinting needs.
+type tokenizer struct {
+	src    string
+	lower  string
+	pos    int
+	rawTag string
+}
+
+// newTokenizer creates a tokenizer over src.
+func newTokenizer(src string) *tokenizer {
+	return &tokenizer{src: src, lower: asciiLower(src)}
+}
+
+// next returns the next token, or false at the end of input.
+func (z *tokenizer) next() (token, bool) {
+	if z.pos >= len(z.src) {
+		return token{}, false
+	}
+
+	if z.rawTag != "" {
+		return z.readRawText(), true
+	}
+
+	idx := strings.IndexByte(z.src[z.pos:], '<')
+	if idx < 0 {
+		t := token{typ: textToken, data: z.src[z.pos:]}
+		z.pos = len(z.src)
+		return t, true
+	}
+	if idx > 0 {
+		t := token{typ: textToken, data: z.src[z.pos : z.pos+idx]}
+		z.pos += idx
+		return t, true
+	}
+
+	return z.readMarkup(), true
+}
+
+// readRawText consumes text up to the closing tag of the current raw element.
+func (z *tokenizer) readRawText() token {
+	end := strings.Index(z.lower[z.pos:], "</"+z.rawTag)
+	var t token
+	if end < 0 {
+		t = token{typ: textToken, data: z.src[z.pos:]}
+		z.pos = len(z.src)
+	} else {
+		t = token{typ: textToken, data: z.src[z.pos : z.pos+end]}
+		z.pos += end
+	}
+	z.rawTag = ""
+	return t
+}
+
+// readMarkup consumes a construct starting with '<'.
+func (z *tokenizer) readMarkup() token {
+	rest := z.src[z.pos:]
+
+	switch {
+	case strings.HasPrefix(rest, "<!--"):
+		end := strings.Index(rest[4:], "-->")
+		if end < 0 {
+			z.pos = len(z.src)
+			return token{typ: commentToken, data: rest[4:]}
+		}
+		z.pos += 4 + end + 3
+		return token{typ: commentToken, data: rest[4 : 4+end]}
+	case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+		// Doctype, CDATA and processing instructions are skipped.
+		z.skipPast('>')
+		return token{typ: commentToken}
+	case strings.HasPrefix(rest, "</") && len(rest) > 2 && isASCIILetter(rest[2]):
+		z.pos += 2
+		name := z.readName()
+		z.skipPast('>')
+		return token{typ: endTagToken, name: name}
+	case len(rest) > 1 && isASCIILetter(rest[1]):
+		z.pos++
+		return z.readStartTag()
+	default:
+		z.pos++
+		return token{typ: textToken, data: "<"}
+	}
+}
+
+// readStartTag parses a tag name and its attributes.
+func (z *tokenizer) readStartTag() token {
+	t := token{typ: startTagToken, name: z.readName()}
+
+	for z.pos < len(z.src) {
+		z.skipSpace()
+		if z.pos >= len(z.src) {
+			break
+		}
+		c := z.src[z.pos]
+		if c == '>' {
+			z.pos++
+			break
+		}
+		if c == '/' {
+			z.pos++
+			if z.pos < len(z.src) && z.src[z.pos] == '>' {
+				t.selfClosing = true
+				z.pos++
+				break
+			}
+			continue
+		}
+
+		key := z.readAttrName()
+		if key == "" {
+			z.pos++
+			continue
+		}
+		z.skipSpace()
+		value := ""
+		if z.pos < len(z.src) && z.src[z.pos] == '=' {
+			z.pos++
+			z.skipSpace()
+			value = html.UnescapeString(z.readAttrValue())
+		}
+		t.attrs = append(t.attrs, attribute{key: key, value: value})
+	}
+
+	if rawTextElements[t.name] && !t.selfClosing {
+		z.rawTag = t.name
+	}
+	return t
+}
+
+// readName reads a lowercased tag name.
+func (z *tokenizer) readName() string {
+	start := z.pos
+	for z.pos < len(z.src) {
+		c := z.src[z.pos]
+		if isSpace(c) || c == '/' || c == '>' {
+			break
+		}
+		z.pos++
+	}
+	return z.lower[start:z.pos]
+}
+
+// readAttrName reads a lowercased attribute name.
+func (z *tokenizer) readAttrName() string {
+	start := z.pos
+	for z.pos < len(z.src) {
+		c := z.src[z.pos]
+		if isSpace(c) || c == '=' || c == '>' || c == '/' {
+			break
+		}
+		z.pos++
+	}
+	return z.lower[start:z.pos]
+}
+
+// readAttrValue reads a quoted or unquoted attribute value.
+func (z *tokenizer) readAttrValue() string {
+	if z.pos >= len(z.src) {
+		return ""
+	}
+
+	if quote := z.src[z.pos]; quote == '"' || quote == '\'' {
+		z.pos++
+		end := strings.IndexByte(z.src[z.pos:], quote)
+		if end < 0 {
+			value := z.src[z.pos:]
+			z.pos = len(z.src)
+			return value
+		}
+		value := z.src[z.pos : z.pos+end]
+		z.pos += end + 1
+		return value
+	}
+
+	start := z.pos
+	for z.pos < len(z.src) {
+		c := z.src[z.pos]
+		if isSpace(c) || c == '>' {
+			break
+		}
+		z.pos++
+	}
+	return z.src[start:z.pos]
+}
+
+// skipSpace advances past ASCII whitespace.
+func (z *tokenizer) skipSpace() {
+	for z.pos < len(z.src) && isSpace(z.src[z.pos]) {
+		z.pos++
+	}
+}
+
+// skipPast advances past the next occurrence of c, or to the end of input.
+func (z *tokenizer) skipPast(c byte) {
+	idx := strings.IndexByte(z.src[z.pos:], c)
+	if idx < 0 {
+		z.pos = len(z.src)
+		return
+	}
+	z.pos += idx + 1
+}
+
+// asciiLower lowercases ASCII letters only, so byte offsets stay aligned
+// with the original string.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
@@ -0,0 +1,137 @@
+package page
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// describe renders the tokens of src as short strings, so tables can list
+// the expected stream compactly.
+func describe(src string) []string {
+	var out []string
+	z := newTokenizer(src)
+	for {
+		t, ok := z.next()
+		if !ok {
+			return out
+		}
+		switch t.typ {
+		case textToken:
+			out = append(out, "text:"+t.data)
+		case commentToken:
+			out = append(out, "comment:"+t.data)
+		case endTagToken:
+			out = append(out, "end:"+t.name)
+		case startTagToken:
+			s := "start:" + t.name
+			for _, a := range t.attrs {
+				s += " " + a.key + "=" + a.value
+			}
+			if t.selfClosing {
+				s += " /"
+			}
+			out = append(out, s)
+		}
+	}
+}
+
+func TestTokenizer(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "empty",
+			src:  "",
+			want: nil,
+		},
+		{
+			name: "text only",
+			src:  "hello",
+			want: []string{"text:hello"},
+		},
+		{
+			name: "tags and text",
+			src:  "<p>Hi</p>",
+			want: []string{"start:p", "text:Hi", "end:p"},
+		},
+		{
+			name: "names are lowercased",
+			src:  `<DIV ID="Main"></Div>`,
+			want: []string{"start:div id=Main", "end:div"},
+		},
+		{
+			name: "quoted unquoted and bare attributes",
+			src:  `<input type=text value='a b' disabled name = "q">`,
+			want: []string{"start:input type=text value=a b disabled= name=q"},
+		},
+		{
+			name: "entities in attribute values",
+			src:  `<a href="/?a=1&amp;b=2">`,
+			want: []string{"start:a href=/?a=1&b=2"},
+		},
+		{
+			name: "self closing",
+			src:  `<br/><img src=x.png />`,
+			want: []string{"start:br /", "start:img src=x.png /"},
+		},
+		{
+			name: "comment",
+			src:  "<!-- generator: Hugo -->x",
+			want: []string{"comment: generator: Hugo ", "text:x"},
+		},
+		{
+			name: "unterminated comment",
+			src:  "<!-- open",
+			want: []string{"comment: open"},
+		},
+		{
+			name: "doctype is skipped",
+			src:  "<!DOCTYPE html><html>",
+			want: []string{"comment:", "start:html"},
+		},
+		{
+			name: "script content is raw text",
+			src:  `<script>if (a < b && "</div>") {}</script>`,
+			want: []string{"start:script", `text:if (a < b && "</div>") {}`, "end:script"},
+		},
+		{
+			name: "raw text end tag is case insensitive",
+			src:  "<title>A <b>title</b></TITLE>",
+			want: []string{"start:title", "text:A <b>title</b>", "end:title"},
+		},
+		{
+			name: "unterminated script",
+			src:  "<script>var x = 1;",
+			want: []string{"start:script", "text:var x = 1;"},
+		},
+		{
+			name: "stray less-than",
+			src:  "1 < 2",
+			want: []string{"text:1 ", "text:<", "text: 2"},
+		},
+		{
+			name: "unterminated attribute quote",
+			src:  `<a href="/x>`,
+			want: []string{"start:a href=/x>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describe(tt.src)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokens of %q\n got: %q\nwant: %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAsciiLowerKeepsOffsets(t *testing.T) {
+	src := "<TITLE>Ärger</TITLE>"
+	if got := asciiLower(src); len(got) != len(src) || !strings.HasPrefix(got, "<title>Ä") {
+		t.Errorf("asciiLower(%q) = %q", src, got)
+	}
+}
@@ -0,0 +1,67 @@
+package protocol
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// ParseAltSvc parses an Alt-Svc header value (RFC 7838), such as
+// `h3=":443"; ma=86400, h3-29=":443"`. The value "clear" and malformed
+// entries yield no services. Draft HTTP/3 identifiers are kept as sent.
+func ParseAltSvc(value string) []models.AltService {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "clear" {
+		return nil
+	}
+
+	var services []models.AltService
+	for _, entry := range splitOutsideQuotes(value, ',') {
+		params := splitOutsideQuotes(entry, ';')
+		protocol, authority, ok := strings.Cut(strings.TrimSpace(params[0]), "=")
+		if !ok {
+			continue
+		}
+		authority = strings.Trim(strings.TrimSpace(authority), `"`)
+		host, portText, err := net.SplitHostPort(authority)
+		if err != nil {
+			continue
+		}
+		port, err := strconv.Atoi(portText)
+		if err != nil || port <= 0 || port > 65535 {
+			continue
+		}
+
+		svc := models.AltService{Protocol: strings.TrimSpace(protocol), Host: host, Port: port}
+		for _, param := range params[1:] {
+			key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "ma") {
+				svc.MaxAge, _ = strconv.Atoi(strings.Trim(val, `"`))
+			}
+		}
+		services = append(services, svc)
+	}
+	return services
+}
+
+// splitOutsideQuotes splits s at sep, ignoring separators inside double
+// quotes.
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case sep:
+			if !inQuotes {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,89 @@
+// Package protocol determines which HTTP versions a host supports: HTTP/1.1
+// and HTTP/2 through TLS ALPN, HTTP/3 through the Alt-Svc header and an
+// optional QUIC probe.
+package protocol
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+const (
+	alpnHTTP11 = "http/1.1"
+	alpnHTTP2  = "h2"
+	httpsPort  = 443
+)
+
+// Analyzer builds protocol support matrices.
+type Analyzer struct {
+	config *config.Config
+}
+
+// New creates a new protocol analyzer.
+func New(cfg *config.Config) *Analyzer {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	return &Analyzer{config: cfg}
+}
+
+// Analyze negotiates ALPN with host on port 443 and parses the Alt-Svc
+// header from headers (lowercase keys, as in models.HTTPAnalysis). When
+// probeQUIC is set and HTTP/3 is advertised, the advertised UDP endpoint is
+// probed as well.
+func (a *Analyzer) Analyze(host string, headers map[string]string, probeQUIC bool) *models.ProtocolSupport {
+	support := &models.ProtocolSupport{Host: host}
+
+	if proto, err := a.negotiate(host, alpnHTTP2, alpnHTTP11); err == nil {
+		support.ALPN = proto
+	}
+	if proto, err := a.negotiate(host, alpnHTTP2); err == nil && proto == alpnHTTP2 {
+		support.HTTP2 = true
+	}
+	// Servers without ALPN still speak HTTP/1.1 after the handshake.
+	if proto, err := a.negotiate(host, alpnHTTP11); err == nil && (proto == alpnHTTP11 || proto == "") {
+		support.HTTP11 = true
+	}
+
+	support.AltSvc = ParseAltSvc(headers["alt-svc"])
+	quicHost, quicPort := host, httpsPort
+	for _, svc := range support.AltSvc {
+		if svc.Protocol == "h3" || strings.HasPrefix(svc.Protocol, "h3-") {
+			support.HTTP3 = true
+			if svc.Host != "" {
+				quicHost = svc.Host
+			}
+			quicPort = svc.Port
+			break
+		}
+	}
+
+	if probeQUIC && support.HTTP3 {
+		support.QUIC = a.probeQUIC(net.JoinHostPort(quicHost, strconv.Itoa(quicPort)))
+	}
+
+	return support
+}
+
+// negotiate performs a TLS handshake offering protos and returns the
+// protocol the server selected, or "" if it did not use ALPN.
+func (a *Analyzer) negotiate(host string, protos ...string) (string, error) {
+	dialer := &net.Dialer{Timeout: a.config.TLS.Timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, strconv.Itoa(httpsPort)), &tls.Config{
+		InsecureSkipVerify: a.config.TLS.InsecureSkipVerify,
+		ServerName:         host,
+		NextProtos:         protos,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.ConnectionState().NegotiatedProtocol, nil
+}
@@ -0,0 +1,171 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+func TestParseAltSvc(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []models.AltService
+	}{
+		{"empty", "", nil},
+		{"clear", " clear ", nil},
+		{
+			name:  "h3 with max age",
+			value: `h3=":443"; ma=86400`,
+			want:  []models.AltService{{Protocol: "h3", Port: 443, MaxAge: 86400}},
+		},
+		{
+			name:  "several entries and drafts",
+			value: `h3=":443"; ma=2592000,h3-29=":8443"; ma="3600", h2="alt.example.com:443"`,
+			want: []models.AltService{
+				{Protocol: "h3", Port: 443, MaxAge: 2592000},
+				{Protocol: "h3-29", Port: 8443, MaxAge: 3600},
+				{Protocol: "h2", Host: "alt.example.com", Port: 443},
+			},
+		},
+		{
+			name:  "commas and semicolons inside quotes",
+			value: `h3="[2001:db8::1]:443"; persist=1; x="a,b;c", h3-Q050=":443"`,
+			want: []models.AltService{
+				{Protocol: "h3", Host: "2001:db8::1", Port: 443},
+				{Protocol: "h3-Q050", Port: 443},
+			},
+		},
+		{
+			name:  "malformed entries are dropped",
+			value: `h3, h3=":0", h3=":65536", h3=":https", h3="nohost", h3=":443"; MA=bad`,
+			want:  []models.AltService{{Protocol: "h3", Port: 443}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseAltSvc(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAltSvc(%q) = %+v\nwant %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// versionNegotiation builds a Version Negotiation packet with the given
+// connection IDs.
+func versionNegotiation(dcid, scid []byte, versions ...uint32) []byte {
+	packet := []byte{0x80, 0, 0, 0, 0}
+	packet = append(packet, byte(len(dcid)))
+	packet = append(packet, dcid...)
+	packet = append(packet, byte(len(scid)))
+	packet = append(packet, scid...)
+	for _, v := range versions {
+		packet = binary.BigEndian.AppendUint32(packet, v)
+	}
+	return packet
+}
+
+func TestParseVersionNegotiation(t *testing.T) {
+	ours := []byte{1, 2, 3, 4}   // our source ID, echoed as destination
+	theirs := []byte{5, 6, 7, 8} // our destination ID, echoed as source
+
+	valid := versionNegotiation(ours, theirs, 1, 0xff00001d)
+	wrongVersion := append([]byte(nil), valid...)
+	wrongVersion[4] = 1
+
+	tests := []struct {
+		name    string
+		packet  []byte
+		want    []uint32
+		wantErr string
+	}{
+		{"valid", valid, []uint32{1, 0xff00001d}, ""},
+		{"trailing partial version ignored", append(append([]byte(nil), valid...), 0xff, 0), []uint32{1, 0xff00001d}, ""},
+		{"short header", []byte{0x80, 0, 0}, nil, "not a QUIC long-header packet"},
+		{"short header form", append([]byte{0x40}, valid[1:]...), nil, "not a QUIC long-header packet"},
+		{"not version negotiation", wrongVersion, nil, "not a QUIC version negotiation packet"},
+		{"connection IDs not swapped", versionNegotiation(theirs, ours, 1), nil, "does not match the probe"},
+		{"truncated connection ID", valid[:8], nil, "truncated version negotiation packet"},
+		{"missing source ID", valid[:10], nil, "truncated version negotiation packet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseVersionNegotiation(tt.packet, ours, theirs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("versions = %#x, want %#x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQUICVersionName(t *testing.T) {
+	tests := []struct {
+		version uint32
+		want    string
+	}{
+		{0x00000001, "QUICv1"},
+		{0x6b3343cf, "QUICv2"},
+		{0xff00001d, "draft-29"},
+		{0xff000022, "draft-34"},
+		{0x51303530, "Q050"},
+		{0x51303436, "Q046"},
+		{0x0a0a0a0a, ""},
+		{0x1a2a3a4a, ""},
+		{0xfafafafa, ""},
+		{0x00000002, "0x00000002"},
+	}
+	for _, tt := range tests {
+		if got := quicVersionName(tt.version); got != tt.want {
+			t.Errorf("quicVersionName(%#x) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestProbeQUIC(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Answer like a server: swap the connection IDs and list the versions,
+	// greasing included.
+	go func() {
+		buf := make([]byte, 1500)
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil || n < quicMinDatagram {
+			return
+		}
+		dcid := buf[6 : 6+buf[5]]
+		rest := buf[6+buf[5]:]
+		scid := rest[1 : 1+rest[0]]
+		conn.WriteTo(versionNegotiation(scid, dcid, 0x1a2a3a4a, 1, 0xff00001d), addr)
+	}()
+
+	cfg := config.Default()
+	cfg.TLS.Timeout = 2 * time.Second
+	probe := New(cfg).probeQUIC(conn.LocalAddr().String())
+	if !probe.Reachable || probe.Error != "" {
+		t.Fatalf("probeQUIC() = %+v, want reachable", probe)
+	}
+	if want := []string{"QUICv1", "draft-29"}; !reflect.DeepEqual(probe.Versions, want) {
+		t.Errorf("versions = %q, want %q", probe.Versions, want)
+	}
+}
@@ -0,0 +1,142 @@
+package protocol
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+const (
+	// quicMinDatagram is the minimum size of a client Initial datagram;
+	// servers drop anything smaller (RFC 9000, section 14.1).
+	quicMinDatagram = 1200
+	// quicConnIDLength is the length of the random connection IDs sent.
+	quicConnIDLength = 8
+	// quicProbeVersion is a reserved version (of the 0x?a?a?a?a form) that
+	// no server implements, forcing a Version Negotiation reply.
+	quicProbeVersion = 0x1a2a3a4a
+)
+
+// probeQUIC sends a QUIC long-header packet with an unsupported version to
+// address and parses the Version Negotiation packet servers must answer
+// with. This confirms a QUIC listener and lists its versions without a
+// full handshake, which would need a QUIC stack the standard library does
+// not have.
+func (a *Analyzer) probeQUIC(address string) *models.QUICProbe {
+	probe := &models.QUICProbe{Address: address}
+
+	conn, err := net.DialTimeout("udp", address, a.config.TLS.Timeout)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	defer conn.Close()
+
+	dcid := make([]byte, quicConnIDLength)
+	scid := make([]byte, quicConnIDLength)
+	if _, err := rand.Read(dcid); err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	if _, err := rand.Read(scid); err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(a.config.TLS.Timeout)); err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	if _, err := conn.Write(versionProbePacket(dcid, scid)); err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+
+	buf := make([]byte, 1500)
+	n, err := conn.Read(buf)
+	if err != nil {
+		probe.Error = fmt.Sprintf("no QUIC response: %v", err)
+		return probe
+	}
+
+	versions, err := parseVersionNegotiation(buf[:n], scid, dcid)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	probe.Reachable = true
+	for _, v := range versions {
+		if name := quicVersionName(v); name != "" {
+			probe.Versions = append(probe.Versions, name)
+		}
+	}
+	return probe
+}
+
+// versionProbePacket builds a padded long-header packet carrying the
+// reserved probe version.
+func versionProbePacket(dcid, scid []byte) []byte {
+	packet := make([]byte, 0, quicMinDatagram)
+	// Long header form and fixed bit set; the remaining bits are ignored
+	// for an unknown version.
+	packet = append(packet, 0xc0)
+	packet = binary.BigEndian.AppendUint32(packet, quicProbeVersion)
+	packet = append(packet, byte(len(dcid)))
+	packet = append(packet, dcid...)
+	packet = append(packet, byte(len(scid)))
+	packet = append(packet, scid...)
+	return append(packet, make([]byte, quicMinDatagram-len(packet))...)
+}
+
+// parseVersionNegotiation validates a Version Negotiation packet answering
+// our probe (connection IDs swapped) and returns its version list.
+func parseVersionNegotiation(packet, dcid, scid []byte) ([]uint32, error) {
+	if len(packet) < 7 || packet[0]&0x80 == 0 {
+		return nil, fmt.Errorf("response is not a QUIC long-header packet")
+	}
+	if binary.BigEndian.Uint32(packet[1:5]) != 0 {
+		return nil, fmt.Errorf("response is not a QUIC version negotiation packet")
+	}
+
+	rest := packet[5:]
+	for _, want := range [][]byte{dcid, scid} {
+		if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+			return nil, fmt.Errorf("truncated version negotiation packet")
+		}
+		if !bytes.Equal(rest[1:1+int(rest[0])], want) {
+			return nil, fmt.Errorf("version negotiation packet does not match the probe")
+		}
+		rest = rest[1+int(rest[0]):]
+	}
+
+	var versions []uint32
+	for len(rest) >= 4 {
+		versions = append(versions, binary.BigEndian.Uint32(rest[:4]))
+		rest = rest[4:]
+	}
+	return versions, nil
+}
+
+// quicVersionName names a QUIC version. Reserved greasing versions return
+// "".
+func quicVersionName(v uint32) string {
+	switch {
+	case v&0x0f0f0f0f == 0x0a0a0a0a:
+		return ""
+	case v == 0x00000001:
+		return "QUICv1"
+	case v == 0x6b3343cf:
+		return "QUICv2"
+	case v>>8 == 0xff0000:
+		return fmt.Sprintf("draft-%d", v&0xff)
+	case v>>24 == 'Q':
+		return string([]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)})
+	default:
+		return fmt.Sprintf("0x%08x", v)
+	}
+}
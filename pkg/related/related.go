@@ -0,0 +1,251 @@
+// Package related links domains across locally stored scan results. Two
+// domains are related when their scans share a tracking ID, a certificate,
+// a certificate name, a favicon, a name server set or an IP address; the
+// cluster of a domain follows tracking ID and certificate links
+// transitively, while the weaker links only reach one hop.
+package related
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// kindOrder is the order links are listed in, strongest evidence first.
+var kindOrder = map[string]int{
+	models.RelationTrackingID:  0,
+	models.RelationCertificate: 1,
+	models.RelationFavicon:     2,
+	models.RelationSAN:         3,
+	models.RelationNameservers: 4,
+	models.RelationIP:          5,
+}
+
+// weakKinds are values unrelated sites share often enough, such as a
+// framework's default favicon, a DNS provider's name servers or a shared
+// host, that they only link domains to the one asked about. They are not
+// followed further, so they cannot chain unrelated clusters together.
+var weakKinds = map[string]bool{
+	models.RelationFavicon:     true,
+	models.RelationNameservers: true,
+	models.RelationIP:          true,
+}
+
+// key is one attribute value.
+type key struct {
+	kind  string
+	value string
+}
+
+// Index maps attribute values to the domains whose scans contain them.
+// Only the newest scan of each domain is indexed.
+type Index struct {
+	results map[string]*models.ScanResult
+	keys    map[string][]key
+	owners  map[key][]string
+}
+
+// New creates an empty index.
+func New() *Index {
+	return &Index{results: make(map[string]*models.ScanResult)}
+}
+
+// Load adds every JSON scan result under dir, recursively, and returns the
+// number of results read. Other JSON files are skipped.
+func (ix *Index) Load(dir string) (int, error) {
+	count := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var result models.ScanResult
+		if json.Unmarshal(data, &result) != nil || result.Domain == "" {
+			return nil
+		}
+		ix.Add(&result)
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, fmt.Errorf("failed to load scan results: %w", err)
+	}
+	return count, nil
+}
+
+// Add indexes result, replacing an older scan of the same domain.
+func (ix *Index) Add(result *models.ScanResult) {
+	domain := Normalize(result.Domain)
+	if prev, ok := ix.results[domain]; ok && prev.Timestamp.After(result.Timestamp) {
+		return
+	}
+	ix.results[domain] = result
+	ix.keys = nil
+}
+
+// Len returns the number of indexed domains.
+func (ix *Index) Len() int {
+	return len(ix.results)
+}
+
+// Cluster returns the domains linked to domain, nearest first. The result
+// is empty when domain has not been scanned or shares nothing. Weak links
+// are only taken from domain itself, and members reached only through weak
+// links are not expanded.
+func (ix *Index) Cluster(domain string) *models.RelatedCluster {
+	ix.build()
+	domain = Normalize(domain)
+	cluster := &models.RelatedCluster{Domain: domain, Scanned: len(ix.results)}
+	if _, ok := ix.results[domain]; !ok {
+		return cluster
+	}
+
+	distance := map[string]int{domain: 0}
+	layer := []string{domain}
+	for len(layer) > 0 {
+		links := make(map[string][]models.RelatedLink)
+		strong := make(map[string]bool)
+		for _, from := range layer {
+			for _, k := range ix.keys[from] {
+				if weakKinds[k.kind] && from != domain {
+					continue
+				}
+				for _, to := range ix.owners[k] {
+					if d, seen := distance[to]; seen && d <= distance[from] {
+						continue
+					}
+					distance[to] = distance[from] + 1
+					links[to] = append(links[to], models.RelatedLink{Kind: k.kind, Value: k.value, Via: from})
+					if !weakKinds[k.kind] {
+						strong[to] = true
+					}
+				}
+			}
+		}
+
+		var reached []string
+		for to := range links {
+			reached = append(reached, to)
+		}
+		sort.Strings(reached)
+		layer = nil
+		for _, to := range reached {
+			sortLinks(links[to])
+			cluster.Members = append(cluster.Members, models.RelatedDomain{
+				Domain:   to,
+				Distance: distance[to],
+				Links:    links[to],
+			})
+			if strong[to] {
+				layer = append(layer, to)
+			}
+		}
+	}
+	return cluster
+}
+
+// build computes the attribute maps after results changed.
+func (ix *Index) build() {
+	if ix.keys != nil {
+		return
+	}
+	ix.keys = make(map[string][]key, len(ix.results))
+	ix.owners = make(map[key][]string)
+	for _, domain := range sortedDomains(ix.results) {
+		seen := make(map[key]bool)
+		for _, k := range attributes(domain, ix.results[domain]) {
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			ix.keys[domain] = append(ix.keys[domain], k)
+			ix.owners[k] = append(ix.owners[k], domain)
+		}
+	}
+}
+
+// attributes lists the linkable values of one scan. The domain itself is
+// listed as a certificate name so that it links to certificates covering
+// it. IP addresses of CDN-fronted sites belong to the CDN and are left out.
+func attributes(domain string, r *models.ScanResult) []key {
+	keys := []key{{models.RelationSAN, domain}}
+	for _, id := range r.TrackingIDs {
+		keys = append(keys, key{models.RelationTrackingID, id.Service + " " + id.ID})
+	}
+	if r.TLS != nil {
+		if r.TLS.Fingerprint != "" {
+			keys = append(keys, key{models.RelationCertificate, r.TLS.Fingerprint})
+		}
+		for _, san := range r.TLS.SANs {
+			keys = append(keys, key{models.RelationSAN, Normalize(san)})
+		}
+	}
+	if r.Favicon != nil && r.Favicon.SHA256 != "" {
+		keys = append(keys, key{models.RelationFavicon, strconv.Itoa(int(r.Favicon.MMH3))})
+	}
+	if r.DNS != nil {
+		if len(r.DNS.NS) > 0 {
+			ns := make([]string, len(r.DNS.NS))
+			for i, n := range r.DNS.NS {
+				ns[i] = Normalize(n)
+			}
+			sort.Strings(ns)
+			keys = append(keys, key{models.RelationNameservers, strings.Join(ns, ", ")})
+		}
+		if r.CDN == "" {
+			for _, ip := range append(append([]string{}, r.DNS.A...), r.DNS.AAAA...) {
+				keys = append(keys, key{models.RelationIP, ip})
+			}
+		}
+	}
+	return keys
+}
+
+// Normalize lowercases a domain and strips a URL scheme, port, path and
+// trailing dot.
+func Normalize(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if i := strings.Index(domain, "://"); i != -1 {
+		domain = domain[i+3:]
+	}
+	if i := strings.IndexAny(domain, "/:"); i != -1 {
+		domain = domain[:i]
+	}
+	return strings.TrimSuffix(domain, ".")
+}
+
+// sortLinks orders links by kind, value and via.
+func sortLinks(links []models.RelatedLink) {
+	sort.Slice(links, func(i, j int) bool {
+		a, b := links[i], links[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.Value != b.Value {
+			return a.Value < b.Value
+		}
+		return a.Via < b.Via
+	})
+}
+
+func sortedDomains(results map[string]*models.ScanResult) []string {
+	domains := make([]string, 0, len(results))
+	for domain := range results {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	return domains
+}
@@ -0,0 +1,74 @@
+package related
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+func scan(domain string, ids []string, favicon int32, ns ...string) *models.ScanResult {
+	r := &models.ScanResult{Domain: domain, Timestamp: time.Unix(1700000000, 0)}
+	for _, id := range ids {
+		r.TrackingIDs = append(r.TrackingIDs, models.TrackingID{Service: "Google Tag Manager", ID: id})
+	}
+	if favicon != 0 {
+		r.Favicon = &models.Favicon{MMH3: favicon, SHA256: "x"}
+	}
+	if len(ns) > 0 {
+		r.DNS = &models.DNSAnalysis{NS: ns}
+	}
+	return r
+}
+
+func TestCluster(t *testing.T) {
+	ix := New()
+	// a and b share a tag manager ID, b and c another one: a strong chain.
+	ix.Add(scan("a.example", []string{"GTM-AAAA"}, 0, "ns1.dns.example", "ns2.dns.example"))
+	ix.Add(scan("b.example", []string{"GTM-AAAA", "GTM-BBBB"}, 116323821))
+	ix.Add(scan("c.example", []string{"GTM-BBBB"}, 0))
+	// d shares only a default favicon with b, e only the DNS provider with a.
+	ix.Add(scan("d.example", nil, 116323821))
+	ix.Add(scan("e.example", []string{"GTM-EEEE"}, 0, "ns2.dns.example", "ns1.dns.example"))
+	ix.Add(scan("f.example", []string{"GTM-EEEE"}, 0))
+
+	tests := []struct {
+		domain string
+		want   map[string]int
+	}{
+		// Strong links are followed; d is only reachable through b's
+		// favicon and e through a's name servers, which stop at one hop.
+		{"a.example", map[string]int{"b.example": 1, "c.example": 2, "e.example": 1}},
+		// From d, the favicon reaches b, but b is not expanded.
+		{"d.example", map[string]int{"b.example": 1}},
+		{"b.example", map[string]int{"a.example": 1, "c.example": 1, "d.example": 1}},
+		{"unknown.example", map[string]int{}},
+	}
+
+	for _, tt := range tests {
+		got := make(map[string]int)
+		for _, m := range ix.Cluster(tt.domain).Members {
+			got[m.Domain] = m.Distance
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Cluster(%q) = %v, want %v", tt.domain, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Example.COM", "example.com"},
+		{"https://example.com:8443/path", "example.com"},
+		{"example.com.", "example.com"},
+		{" www.example.com ", "www.example.com"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Package rules is a small ordered rule engine shared by the CDN, WAF and
+// hosting provider detectors. Rules are plain data evaluated against named
+// input fields; the result depends only on the input and the order of the
+// rule list, never on map iteration.
+package rules
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+const (
+	// confidence is given to every rule hit; rules are specific enough that
+	// a single hit identifies the vendor.
+	confidence = 100
+	// maxMatch caps the matched text kept as evidence.
+	maxMatch = 80
+)
+
+// Input holds the values a rule set is evaluated against, keyed by field
+// name. Header fields are named "header:<lowercase name>".
+type Input map[string][]string
+
+// Field names used by the built-in rule sets. Evidence from a field is
+// reported with the field name as its source.
+const (
+	FieldCookie   = models.EvidenceCookie
+	FieldBody     = models.EvidenceBody
+	FieldCNAME    = models.EvidenceCNAME
+	FieldHostname = "hostname"
+	FieldISP      = "isp"
+)
+
+// HeaderField returns the input field for a response header.
+func HeaderField(name string) string {
+	return "header:" + strings.ToLower(name)
+}
+
+// Headers builds an input from headers with lowercase keys, as stored in
+// models.HTTPAnalysis.
+func Headers(headers map[string]string) Input {
+	in := make(Input, len(headers))
+	for name, value := range headers {
+		in.Add(HeaderField(name), value)
+	}
+	return in
+}
+
+// Add appends values to field.
+func (in Input) Add(field string, values ...string) {
+	in[field] = append(in[field], values...)
+}
+
+// Matcher tests one input field. A nil Pattern matches any value, i.e. the
+// field being present.
+type Matcher struct {
+	Field   string
+	Pattern *regexp.Regexp
+}
+
+// Rule identifies one product. Among matching rules, higher Priority comes
+// first; equal priorities keep the order of the rule list.
+type Rule struct {
+	Name     string
+	Priority int
+	Matchers []Matcher
+}
+
+// Match is a rule that matched, with one evidence item per matching field
+// value.
+type Match struct {
+	Name     string
+	Priority int
+	Evidence []models.Evidence
+}
+
+// Header matches a response header, on presence when expr is empty.
+func Header(name, expr string) Matcher {
+	return Field(HeaderField(name), expr)
+}
+
+// Field matches any value of field against expr, on presence when expr is
+// empty.
+func Field(field, expr string) Matcher {
+	m := Matcher{Field: field}
+	if expr != "" {
+		m.Pattern = regexp.MustCompile(expr)
+	}
+	return m
+}
+
+// Fields builds one matcher per expression on the same field.
+func Fields(field string, exprs ...string) []Matcher {
+	matchers := make([]Matcher, len(exprs))
+	for i, expr := range exprs {
+		matchers[i] = Field(field, expr)
+	}
+	return matchers
+}
+
+// Suffix matches host names in field equal to or under one of domains.
+func Suffix(field string, domains ...string) Matcher {
+	quoted := make([]string, len(domains))
+	for i, d := range domains {
+		quoted[i] = regexp.QuoteMeta(strings.ToLower(d))
+	}
+	return Field(field, `(?i)(?:^|\.)(?:`+strings.Join(quoted, "|")+`)\.?$`)
+}
+
+// Evaluate runs every rule against in and returns the matches ordered by
+// priority, then by position in rules.
+func Evaluate(rules []Rule, in Input) []Match {
+	var matches []Match
+	for _, rule := range rules {
+		var evidence []models.Evidence
+		for _, m := range rule.Matchers {
+			for _, value := range in[m.Field] {
+				if e, ok := m.match(value); ok {
+					evidence = append(evidence, e)
+					break
+				}
+			}
+		}
+		if len(evidence) > 0 {
+			matches = append(matches, Match{Name: rule.Name, Priority: rule.Priority, Evidence: evidence})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Priority > matches[j].Priority
+	})
+	return matches
+}
+
+// First returns the name of the highest-priority match, or "".
+func First(rules []Rule, in Input) string {
+	if matches := Evaluate(rules, in); len(matches) > 0 {
+		return matches[0].Name
+	}
+	return ""
+}
+
+// match tests one value and describes the hit.
+func (m Matcher) match(value string) (models.Evidence, bool) {
+	matched := value
+	if m.Pattern != nil {
+		loc := m.Pattern.FindStringIndex(value)
+		if loc == nil {
+			return models.Evidence{}, false
+		}
+		matched = value[loc[0]:loc[1]]
+	}
+
+	e := models.Evidence{Source: m.Field, Match: trim(matched), Confidence: confidence}
+	if m.Pattern != nil {
+		e.Pattern = m.Pattern.String()
+	}
+	switch {
+	case strings.HasPrefix(m.Field, "header:"):
+		e.Source = models.EvidenceHeader
+		e.Key = strings.TrimPrefix(m.Field, "header:")
+		e.Match = trim(value)
+	case m.Field == FieldCookie, m.Field == FieldCNAME, m.Field == FieldHostname:
+		e.Key = value
+		e.Match = ""
+	}
+	return e, true
+}
+
+// trim normalizes whitespace and shortens matched text for display.
+func trim(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > maxMatch {
+		s = strings.ToValidUTF8(s[:maxMatch], "") + "…"
+	}
+	return s
+}
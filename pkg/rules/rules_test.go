@@ -0,0 +1,98 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+var testRules = []Rule{
+	{Name: "Low", Matchers: []Matcher{Header("x-low", "")}},
+	{Name: "Edge", Priority: 10, Matchers: []Matcher{
+		Suffix(FieldCNAME, "edge.example"),
+		Header("server", `(?i)^EdgeServer`),
+		Field(FieldCookie, `^edge_`),
+	}},
+	{Name: "Other", Priority: 10, Matchers: []Matcher{Field(FieldBody, `blocked by other`)}},
+}
+
+func TestEvaluateOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Input
+		want []string
+	}{
+		{"no input", Input{}, nil},
+		{"single rule", Headers(map[string]string{"x-low": "1"}), []string{"Low"}},
+		{
+			name: "priority before list order",
+			in:   Input{HeaderField("x-low"): {"1"}, FieldBody: {"... blocked by other ..."}, FieldCNAME: {"a.edge.example."}},
+			want: []string{"Edge", "Other", "Low"},
+		},
+		{"suffix needs a label boundary", Input{FieldCNAME: {"notedge.example"}}, nil},
+		{"suffix is case insensitive", Input{FieldCNAME: {"CDN.Edge.Example"}}, []string{"Edge"}},
+		{"header pattern", Headers(map[string]string{"server": "edgeserver/2"}), []string{"Edge"}},
+		{"header pattern miss", Headers(map[string]string{"server": "nginx (edgeserver)"}), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, m := range Evaluate(testRules, tt.in) {
+				got = append(got, m.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateEvidence(t *testing.T) {
+	in := Input{
+		FieldCNAME:  {"a.edge.example"},
+		FieldCookie: {"session", "edge_id", "edge_other"},
+	}
+	in.Add(HeaderField("Server"), "EdgeServer 1.0")
+
+	matches := Evaluate(testRules, in)
+	if len(matches) != 1 {
+		t.Fatalf("Evaluate() = %d matches, want 1", len(matches))
+	}
+	want := []models.Evidence{
+		{Source: models.EvidenceCNAME, Key: "a.edge.example", Pattern: `(?i)(?:^|\.)(?:edge\.example)\.?$`, Confidence: confidence},
+		{Source: models.EvidenceHeader, Key: "server", Match: "EdgeServer 1.0", Pattern: `(?i)^EdgeServer`, Confidence: confidence},
+		{Source: models.EvidenceCookie, Key: "edge_id", Pattern: `^edge_`, Confidence: confidence},
+	}
+	if !reflect.DeepEqual(matches[0].Evidence, want) {
+		t.Errorf("evidence = %+v\nwant %+v", matches[0].Evidence, want)
+	}
+}
+
+func TestFirst(t *testing.T) {
+	if got := First(testRules, Input{HeaderField("x-low"): {"1"}, FieldBody: {"blocked by other"}}); got != "Other" {
+		t.Errorf("First() = %q, want Other", got)
+	}
+	if got := First(testRules, Input{}); got != "" {
+		t.Errorf("First() = %q, want empty", got)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	long := ""
+	for i := 0; i < 100; i++ {
+		long += "ab"
+	}
+	tests := []struct {
+		in, want string
+	}{
+		{"  a \n\t b  ", "a b"},
+		{long, long[:maxMatch] + "…"},
+	}
+	for _, tt := range tests {
+		if got := trim(tt.in); got != tt.want {
+			t.Errorf("trim(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/detector"
+	"github.com/javicosvml/rankle-go/pkg/exposure"
+	"github.com/javicosvml/rankle-go/pkg/httpsec"
+	"github.com/javicosvml/rankle-go/pkg/jsscan"
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/origin"
+	"github.com/javicosvml/rankle-go/pkg/page"
+	"github.com/javicosvml/rankle-go/pkg/protocol"
+	"github.com/javicosvml/rankle-go/pkg/tls"
+	"github.com/javicosvml/rankle-go/pkg/waf"
+	"github.com/javicosvml/rankle-go/pkg/wellknown"
+)
+
+// Module names.
+const (
+	ModuleHTTP           = "http"
+	ModuleTech           = "tech"
+	ModuleFavicon        = "favicon"
+	ModuleJS             = "js"
+	ModuleVulns          = "vulns"
+	ModuleWellKnown      = "well-known"
+	ModuleSensitiveFiles = "sensitive-files"
+	ModuleHTTPSecurity   = "http-security"
+	ModuleWAFProbe       = "waf-probe"
+	ModuleProtocols      = "protocols"
+	ModuleDNS            = "dns"
+	ModuleTLS            = "tls"
+	ModuleSubdomains     = "subdomains"
+	ModuleOrigin         = "origin"
+	ModuleCloud          = "cloud"
+)
+
+// Module is one stage of a scan.
+type Module struct {
+	Name        string
+	Description string
+	// Title names the module in messages, e.g. "HTTP analysis failed".
+	Title string
+	// Needs lists the modules whose results this one uses; selecting it
+	// selects them, and skipping one of them skips it.
+	Needs []string
+	// Active modules send requests beyond normal browsing. They run by
+	// default only when their configuration switch is on.
+	Active bool
+
+	enabled func(cfg *config.Config) bool
+	run     func(s *state) error
+}
+
+// registry lists every module in the order they run.
+var registry = []Module{
+	{
+		Name:        ModuleHTTP,
+		Title:       "HTTP analysis",
+		Description: "Home page, security headers and passive WAF check",
+		run:         runHTTP,
+	},
+	{
+		Name:        ModuleTech,
+		Title:       "Technology detection",
+		Description: "Technologies, third parties and tracking IDs",
+		Needs:       []string{ModuleHTTP},
+		run:         runTech,
+	},
+	{
+		Name:        ModuleFavicon,
+		Title:       "Favicon fetch",
+		Description: "Favicon hash and the product it identifies",
+		Needs:       []string{ModuleHTTP},
+		run:         runFavicon,
+	},
+	{
+		Name:        ModuleJS,
+		Title:       "Script analysis",
+		Description: "Endpoints and secrets in scripts and source maps",
+		Needs:       []string{ModuleHTTP},
+		Active:      true,
+		enabled:     func(cfg *config.Config) bool { return cfg.Scanner.ScriptAnalysis },
+		run:         runJS,
+	},
+	{
+		Name:        ModuleVulns,
+		Title:       "Vulnerability check",
+		Description: "Known vulnerabilities of detected versions",
+		Needs:       []string{ModuleTech},
+		run:         runVulns,
+	},
+	{
+		Name:        ModuleWellKnown,
+		Title:       "Well-known file probe",
+		Description: "robots.txt, security.txt and other well-known files",
+		Needs:       []string{ModuleHTTP},
+		Active:      true,
+		enabled:     func(cfg *config.Config) bool { return cfg.Scanner.WellKnown },
+		run:         runWellKnown,
+	},
+	{
+		Name:        ModuleSensitiveFiles,
+		Title:       "Sensitive file check",
+		Description: "Exposed sensitive files and admin panels",
+		Needs:       []string{ModuleHTTP},
+		Active:      true,
+		enabled:     func(cfg *config.Config) bool { return cfg.Scanner.SensitiveFiles },
+		run:         runSensitiveFiles,
+	},
+	{
+		Name:        ModuleHTTPSecurity,
+		Title:       "HTTP method and CORS check",
+		Description: "HTTP method and CORS checks",
+		Needs:       []string{ModuleHTTP},
+		Active:      true,
+		enabled:     func(cfg *config.Config) bool { return cfg.Scanner.HTTPSecurity },
+		run:         runHTTPSecurity,
+	},
+	{
+		Name:        ModuleWAFProbe,
+		Title:       "WAF probe",
+		Description: "Active WAF identification",
+		Needs:       []string{ModuleHTTP},
+		Active:      true,
+		enabled:     func(cfg *config.Config) bool { return cfg.Scanner.WAFProbe },
+		run:         runWAFProbe,
+	},
+	{
+		Name:        ModuleProtocols,
+		Title:       "Protocol check",
+		Description: "HTTP/1.1, HTTP/2 and HTTP/3 support",
+		Needs:       []string{ModuleHTTP},
+		run:         runProtocols,
+	},
+	{
+		Name:        ModuleDNS,
+		Title:       "DNS analysis",
+		Description: "DNS records and CDN detection",
+		run:         runDNS,
+	},
+	{
+		Name:        ModuleTLS,
+		Title:       "TLS analysis",
+		Description: "TLS certificate",
+		run:         runTLS,
+	},
+	{
+		Name:        ModuleSubdomains,
+		Title:       "Subdomain discovery",
+		Description: "Subdomains from Certificate Transparency (crt.sh)",
+		run:         runSubdomains,
+	},
+	{
+		Name:        ModuleOrigin,
+		Title:       "Origin check",
+		Description: "Origin servers reachable around the CDN",
+		Needs:       []string{ModuleHTTP, ModuleDNS},
+		Active:      true,
+		enabled:     func(cfg *config.Config) bool { return cfg.Scanner.OriginCheck },
+		run:         runOrigin,
+	},
+	{
+		Name:        ModuleCloud,
+		Title:       "Geolocation",
+		Description: "Reverse DNS and cloud provider of the first IP",
+		Needs:       []string{ModuleDNS},
+		run:         runCloud,
+	},
+}
+
+// Registry returns every module in run order.
+func Registry() []Module {
+	return append([]Module(nil), registry...)
+}
+
+// Find returns the module called name.
+func Find(name string) (Module, bool) {
+	for _, m := range registry {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Module{}, false
+}
+
+// Select returns the modules cfg chooses, in run order. Scanner.Modules
+// replaces the default set, which is every passive module plus the active
+// ones switched on; the modules they need are added. Scanner.SkipModules
+// then removes modules along with those needing them. Module names in
+// Scanner.FailOn are checked as well.
+func Select(cfg *config.Config) ([]Module, error) {
+	known := make(map[string]Module, len(registry))
+	for _, m := range registry {
+		known[m.Name] = m
+	}
+	var unknown []string
+	check := func(list []string) []string {
+		var clean []string
+		for _, name := range list {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if _, ok := known[name]; !ok {
+				unknown = append(unknown, name)
+			}
+			clean = append(clean, name)
+		}
+		return clean
+	}
+	chosen, skipped := check(cfg.Scanner.Modules), check(cfg.Scanner.SkipModules)
+	for _, name := range cfg.Scanner.FailOn {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != FailOnAny {
+			check([]string{name})
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown module: %s (available: %s)",
+			strings.Join(unknown, ", "), strings.Join(names(registry), ", "))
+	}
+
+	selected := make(map[string]bool)
+	if len(chosen) == 0 {
+		for _, m := range registry {
+			if !m.Active || m.enabled(cfg) {
+				selected[m.Name] = true
+			}
+		}
+	} else {
+		var add func(name string)
+		add = func(name string) {
+			if selected[name] {
+				return
+			}
+			selected[name] = true
+			for _, need := range known[name].Needs {
+				add(need)
+			}
+		}
+		for _, name := range chosen {
+			add(name)
+		}
+	}
+
+	removed := make(map[string]bool)
+	for _, name := range skipped {
+		removed[name] = true
+	}
+	// Needs point to earlier modules, so one pass in run order suffices.
+	var modules []Module
+	for _, m := range registry {
+		for _, need := range m.Needs {
+			if removed[need] {
+				removed[m.Name] = true
+			}
+		}
+		if selected[m.Name] && !removed[m.Name] {
+			modules = append(modules, m)
+		}
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no modules left to run")
+	}
+	return modules, nil
+}
+
+// names returns the names of modules.
+func names(modules []Module) []string {
+	list := make([]string, len(modules))
+	for i, m := range modules {
+		list[i] = m.Name
+	}
+	return list
+}
+
+func runHTTP(s *state) error {
+	httpAnalysis, resp, err := s.scan.AnalyzeHTTP(s.domain)
+	if err != nil {
+		return err
+	}
+	s.resp = resp
+	s.result.HTTP = httpAnalysis
+	s.result.WAF = s.det.DetectWAF(httpAnalysis.Headers, resp)
+	s.result.SecurityHeaders = securityHeaders(httpAnalysis.Headers)
+	s.detectCDNs(nil)
+
+	body, err := s.scan.ReadBody(resp)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+	httpAnalysis.Body = &body.Info
+	if body.Info.Truncated {
+		s.log.Warn("response body truncated", "limit", body.Info.Limit)
+	}
+	s.body = body.Content
+	s.result.Page = page.Parse(body.Content, resp.Request.URL.String())
+	return nil
+}
+
+func runTech(s *state) error {
+	s.result.Technologies = s.det.DetectTechnologies(s.body, s.result.HTTP.Headers, s.result.Page)
+	s.result.TrackingIDs = s.det.ExtractTrackingIDs(s.body)
+	return nil
+}
+
+func runFavicon(s *state) error {
+	favicon, err := s.scan.FetchFavicon(s.result.Page, s.result.Page.URL)
+	if err != nil {
+		return err
+	}
+	s.result.Favicon = favicon
+	s.det.DetectFavicon(favicon, s.result.Technologies)
+	return nil
+}
+
+func runJS(s *state) error {
+	s.result.Scripts = jsscan.New(s.scan).Analyze(s.result.Page)
+	s.det.AddPackages(s.result.Scripts.SourceMaps, s.result.Technologies)
+	s.log.Info("scripts analyzed", "scripts", len(s.result.Scripts.Files),
+		"findings", len(s.result.Scripts.Findings), "source_maps", len(s.result.Scripts.SourceMaps))
+	return nil
+}
+
+func runVulns(s *state) error {
+	s.result.Vulnerabilities = s.vulnDB.Match(s.result.Technologies.Items)
+	return nil
+}
+
+func runWellKnown(s *state) error {
+	s.result.WellKnown = wellknown.New(s.scan).Probe(s.origin())
+	s.soft404()
+	return nil
+}
+
+func runSensitiveFiles(s *state) error {
+	checker := exposure.New(s.scan)
+	for _, path := range s.cfg.Scanner.SensitivePathFiles {
+		if err := checker.Load(strings.TrimSpace(path)); err != nil {
+			return err
+		}
+	}
+	s.result.Exposures = checker.Check(s.origin())
+	s.log.Info("sensitive files checked", "exposed", len(s.result.Exposures))
+	s.soft404()
+	return nil
+}
+
+func runHTTPSecurity(s *state) error {
+	s.result.HTTPSecurity = httpsec.New(s.scan).Check(s.origin(), s.resp.Request.URL.Hostname())
+	s.log.Info("HTTP methods and CORS tested", "findings", len(s.result.HTTPSecurity.Findings))
+	s.soft404()
+	return nil
+}
+
+func runWAFProbe(s *state) error {
+	analysis, err := waf.New(s.scan).Probe(s.origin())
+	if err != nil {
+		return err
+	}
+	s.result.WAFDetails = analysis
+	if analysis.Detected {
+		s.result.WAF = detector.WAFName(analysis.Vendor)
+	}
+	return nil
+}
+
+func runProtocols(s *state) error {
+	s.result.HTTP.Protocols = protocol.New(s.cfg).Analyze(s.resp.Request.URL.Hostname(), s.result.HTTP.Headers, s.cfg.Scanner.QUICProbe)
+	return nil
+}
+
+func runDNS(s *state) error {
+	analysis, lookups, err := s.resolver.Analyze(s.domain)
+	for rtype, st := range lookups {
+		s.result.Stages[ModuleDNS+"."+rtype] = st
+	}
+	if err != nil {
+		return err
+	}
+	s.result.DNS = analysis
+	s.detectCDNs(analysis.CNAME)
+	return nil
+}
+
+func runTLS(s *state) error {
+	analysis, err := tls.New(s.cfg).Analyze(s.domain)
+	if err != nil {
+		return err
+	}
+	s.result.TLS = analysis
+	return nil
+}
+
+func runSubdomains(s *state) error {
+	subdomains, err := s.resolver.EnumerateSubdomains(s.domain)
+	if err != nil {
+		return err
+	}
+	s.result.SubdomainsFound = len(subdomains)
+	if len(subdomains) > s.cfg.Scanner.MaxSubdomainsDisplay {
+		s.result.Subdomains = subdomains[:s.cfg.Scanner.MaxSubdomainsDisplay]
+	} else {
+		s.result.Subdomains = subdomains
+	}
+	s.log.Info("subdomains found", "count", len(subdomains))
+	return nil
+}
+
+func runOrigin(s *state) error {
+	// Without a CDN the site is served from its origin already.
+	if len(s.result.CDNs) == 0 {
+		return skip("no CDN detected")
+	}
+	// Subdomains are candidates when that module ran; origin does not need
+	// it, so MX hosts and historical IPs are still tested without it.
+	if st := s.result.Stages[ModuleSubdomains]; st == nil || st.Status != models.StageOK {
+		s.log.Info("origin check without subdomain candidates", "reason", "subdomains did not succeed")
+	}
+	finder := origin.New(s.scan, s.resolver, s.det)
+	candidates := finder.Candidates(s.result.DNS, s.result.Subdomains, s.cfg.Scanner.HistoricalIPs)
+	fronted := append(append([]string{}, s.result.DNS.A...), s.result.DNS.AAAA...)
+	analysis, err := finder.Check(s.resp.Request.URL.String(), fronted, candidates)
+	if err != nil {
+		return err
+	}
+	s.result.Origin = analysis
+	s.log.Info("origin candidates tested", "count", len(analysis.Candidates))
+	return nil
+}
+
+func runCloud(s *state) error {
+	if len(s.result.DNS.A) == 0 {
+		return skip("no A record")
+	}
+	ip := s.result.DNS.A[0]
+
+	// Reverse DNS lookup
+	if hostnames, err := s.resolver.ReverseLookup(ip); err == nil && len(hostnames) > 0 {
+		s.result.CloudProvider = s.det.DetectCloudProvider(ip, hostnames[0], "")
+	}
+
+	// Note: a geolocation API call would go here; for now only the IP is
+	// stored.
+	s.result.Geolocation = &models.Geolocation{
+		IP: ip,
+	}
+	return nil
+}
+
+// securityHeaderKeys are the response headers kept in SecurityHeaders.
+var securityHeaderKeys = []string{
+	"strict-transport-security",
+	"content-security-policy",
+	"x-frame-options",
+	"x-content-type-options",
+	"x-xss-protection",
+	"referrer-policy",
+	"permissions-policy",
+}
+
+// securityHeaders picks the security headers out of the response headers.
+func securityHeaders(headers map[string]string) map[string]string {
+	found := make(map[string]string)
+	for _, key := range securityHeaderKeys {
+		if value, exists := headers[key]; exists {
+			found[key] = value
+		}
+	}
+	return found
+}
@@ -0,0 +1,138 @@
+package runner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+)
+
+func TestSelect(t *testing.T) {
+	passive := []string{
+		ModuleHTTP, ModuleTech, ModuleFavicon, ModuleVulns, ModuleProtocols,
+		ModuleDNS, ModuleTLS, ModuleSubdomains, ModuleCloud,
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(cfg *config.Config)
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "default set is passive modules",
+			want: passive,
+		},
+		{
+			name:  "switch adds active module in registry order",
+			setup: func(cfg *config.Config) { cfg.Scanner.WAFProbe = true },
+			want: []string{
+				ModuleHTTP, ModuleTech, ModuleFavicon, ModuleVulns, ModuleWAFProbe, ModuleProtocols,
+				ModuleDNS, ModuleTLS, ModuleSubdomains, ModuleCloud,
+			},
+		},
+		{
+			name:  "chosen modules run in registry order",
+			setup: func(cfg *config.Config) { cfg.Scanner.Modules = []string{"tls", "dns"} },
+			want:  []string{ModuleDNS, ModuleTLS},
+		},
+		{
+			name:  "needs are added transitively",
+			setup: func(cfg *config.Config) { cfg.Scanner.Modules = []string{"vulns"} },
+			want:  []string{ModuleHTTP, ModuleTech, ModuleVulns},
+		},
+		{
+			name:  "naming an active module selects it without its switch",
+			setup: func(cfg *config.Config) { cfg.Scanner.Modules = []string{"origin"} },
+			want:  []string{ModuleHTTP, ModuleDNS, ModuleOrigin},
+		},
+		{
+			name:  "names are trimmed and case-insensitive",
+			setup: func(cfg *config.Config) { cfg.Scanner.Modules = []string{" DNS ", ""} },
+			want:  []string{ModuleDNS},
+		},
+		{
+			name:  "skip removes dependents",
+			setup: func(cfg *config.Config) { cfg.Scanner.SkipModules = []string{"http"} },
+			want:  []string{ModuleDNS, ModuleTLS, ModuleSubdomains, ModuleCloud},
+		},
+		{
+			name:  "skip removes transitive dependents",
+			setup: func(cfg *config.Config) { cfg.Scanner.SkipModules = []string{"tech"} },
+			want: []string{
+				ModuleHTTP, ModuleFavicon, ModuleProtocols,
+				ModuleDNS, ModuleTLS, ModuleSubdomains, ModuleCloud,
+			},
+		},
+		{
+			name: "skip wins over a chosen module",
+			setup: func(cfg *config.Config) {
+				cfg.Scanner.Modules = []string{"cloud", "tls"}
+				cfg.Scanner.SkipModules = []string{"dns"}
+			},
+			want: []string{ModuleTLS},
+		},
+		{
+			name: "unknown modules are listed",
+			setup: func(cfg *config.Config) {
+				cfg.Scanner.Modules = []string{"dns", "whois"}
+				cfg.Scanner.SkipModules = []string{"ports"}
+			},
+			wantErr: "unknown module: whois, ports (available: http,",
+		},
+		{
+			name:    "unknown fail-on module",
+			setup:   func(cfg *config.Config) { cfg.Scanner.FailOn = []string{"any", "dnss"} },
+			wantErr: "unknown module: dnss",
+		},
+		{
+			name: "nothing left",
+			setup: func(cfg *config.Config) {
+				cfg.Scanner.Modules = []string{"tls"}
+				cfg.Scanner.SkipModules = []string{"tls"}
+			},
+			wantErr: "no modules left to run",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			if tt.setup != nil {
+				tt.setup(cfg)
+			}
+
+			modules, err := Select(cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Select() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select() error: %v", err)
+			}
+			if got := names(modules); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Select removes skipped dependents in one pass, which relies on every
+// module needing only modules registered before it.
+func TestRegistryNeedsComeFirst(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, m := range registry {
+		for _, need := range m.Needs {
+			if !seen[need] {
+				t.Errorf("module %s needs %s, which is not registered before it", m.Name, need)
+			}
+		}
+		if seen[m.Name] {
+			t.Errorf("module %s is registered twice", m.Name)
+		}
+		seen[m.Name] = true
+	}
+}
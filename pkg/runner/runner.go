@@ -0,0 +1,213 @@
+// Package runner runs a scan as an ordered list of modules: the home page
+// request, technology detection, DNS, TLS, subdomain discovery and the
+// opt-in active checks. Which modules run is chosen with
+// Scanner.Modules and Scanner.SkipModules; how each went is recorded in
+// ScanResult.Stages so absent sections can be told apart from failed ones.
+package runner
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/detector"
+	"github.com/javicosvml/rankle-go/pkg/dns"
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+	"github.com/javicosvml/rankle-go/pkg/stage"
+	"github.com/javicosvml/rankle-go/pkg/vuln"
+)
+
+// MetadataModules is the ScanResult.Metadata key listing the modules run.
+const MetadataModules = "modules"
+
+// FailOnAny in a failure policy matches every stage.
+const FailOnAny = "any"
+
+// Runner runs the selected modules against a domain.
+type Runner struct {
+	cfg     *config.Config
+	modules []Module
+	det     *detector.Detector
+	vulnDB  *vuln.Database
+	// Logger receives module progress and failures. It defaults to
+	// slog.Default().
+	Logger *slog.Logger
+}
+
+// New creates a Runner for the modules cfg selects, with the fingerprint
+// files and vulnerability feeds of cfg loaded.
+func New(cfg *config.Config) (*Runner, error) {
+	modules, err := Select(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	det := detector.New()
+	for _, path := range cfg.Scanner.FingerprintFiles {
+		if err := det.LoadFingerprints(strings.TrimSpace(path)); err != nil {
+			return nil, err
+		}
+	}
+	logger := slog.Default()
+	for _, warning := range det.Fingerprints().Warnings() {
+		logger.Warn("skipped fingerprint pattern", "pattern", warning)
+	}
+
+	vulnDB := vuln.New()
+	for _, path := range cfg.Scanner.VulnerabilityFeeds {
+		if err := vulnDB.Load(strings.TrimSpace(path)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Runner{
+		cfg:     cfg,
+		modules: modules,
+		det:     det,
+		vulnDB:  vulnDB,
+		Logger:  logger,
+	}, nil
+}
+
+// Modules returns the names of the modules that run, in order.
+func (r *Runner) Modules() []string {
+	return names(r.modules)
+}
+
+// Run scans domain, which may be given as a URL. Every registered module
+// gets an entry in result.Stages: a module that fails is reported on
+// Logger and leaves its sections empty while the others still run, and
+// modules not selected or whose needs failed are skipped.
+func (r *Runner) Run(domain string) (*models.ScanResult, error) {
+	scan := scanner.New(r.cfg)
+	result, err := scan.Scan(domain)
+	if err != nil {
+		return nil, err
+	}
+	result.Metadata[MetadataModules] = r.Modules()
+	result.Stages = make(map[string]*models.Stage)
+	for _, m := range registry {
+		result.Stages[m.Name] = stage.Skipped("not selected")
+	}
+
+	s := &state{
+		cfg:      r.cfg,
+		log:      r.Logger,
+		scan:     scan,
+		resolver: dns.New(r.cfg),
+		det:      r.det,
+		vulnDB:   r.vulnDB,
+		result:   result,
+		domain:   result.Domain,
+	}
+	for _, m := range r.modules {
+		log := r.Logger.With("domain", s.domain, "module", m.Name)
+		if need := s.unmet(m); need != "" {
+			result.Stages[m.Name] = stage.Skipped(need + " did not succeed")
+			log.Debug("module skipped", "reason", result.Stages[m.Name].Reason)
+			continue
+		}
+		log.Info("module started", "title", m.Title)
+		start := time.Now()
+		s.log = log
+		err := m.run(s)
+		var reason skip
+		if errors.As(err, &reason) {
+			result.Stages[m.Name] = stage.Skipped(string(reason))
+			log.Debug("module skipped", "reason", string(reason))
+			continue
+		}
+		st := stage.Done(start, err)
+		result.Stages[m.Name] = st
+		if stage.Failed(st) {
+			log.Warn("module failed", "status", st.Status, "error_class", st.ErrorClass, "error", st.Error)
+		} else {
+			log.Debug("module done", "duration_ms", st.DurationMS)
+		}
+	}
+	return result, nil
+}
+
+// Failures returns the names of the failed or timed-out stages of result
+// that the policy names, sorted. The policy lists modules, which cover
+// their sub-stages such as "dns.mx", or "any".
+func Failures(result *models.ScanResult, policy []string) []string {
+	var failed []string
+	for name, st := range result.Stages {
+		if !stage.Failed(st) {
+			continue
+		}
+		for _, p := range policy {
+			p = strings.ToLower(strings.TrimSpace(p))
+			if p == FailOnAny || p == name || strings.HasPrefix(name, p+".") {
+				failed = append(failed, name)
+				break
+			}
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+// skip is returned by a module that had nothing to do; the string is the
+// reason.
+type skip string
+
+func (s skip) Error() string { return string(s) }
+
+// state is what modules share during one run.
+type state struct {
+	cfg      *config.Config
+	log      *slog.Logger // tagged with the running module
+	scan     *scanner.Scanner
+	resolver *dns.Resolver
+	det      *detector.Detector
+	vulnDB   *vuln.Database
+	result   *models.ScanResult
+	domain   string
+
+	// resp is the home page response and body its content.
+	resp *http.Response
+	body string
+}
+
+// unmet returns the first module m needs that did not succeed, or "".
+func (s *state) unmet(m Module) string {
+	for _, need := range m.Needs {
+		if st := s.result.Stages[need]; st == nil || st.Status != models.StageOK {
+			return need
+		}
+	}
+	return ""
+}
+
+// detectCDNs lists the CDNs seen in the home page headers, when it was
+// fetched, and in the CNAME chain.
+func (s *state) detectCDNs(cnames []string) {
+	var headers map[string]string
+	if s.result.HTTP != nil {
+		headers = s.result.HTTP.Headers
+	}
+	s.result.CDNs = s.det.DetectCDNs(headers, cnames)
+	s.result.CDN = ""
+	if len(s.result.CDNs) > 0 {
+		s.result.CDN = s.result.CDNs[0].Name
+	}
+}
+
+// origin returns the scheme and host of the home page.
+func (s *state) origin() string {
+	return s.resp.Request.URL.Scheme + "://" + s.resp.Request.URL.Host
+}
+
+// soft404 records the soft-404 baseline the path probes compare against.
+func (s *state) soft404() {
+	if s.result.Soft404 == nil {
+		s.result.Soft404 = s.scan.Soft404Baseline(s.origin())
+	}
+}
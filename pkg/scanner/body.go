@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/internal/brotli"
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// acceptEncoding lists the content codings the scanner can decode itself.
+const acceptEncoding = "gzip, deflate, br"
+
+// Body is a size-bounded, UTF-8 decoded response body.
+type Body struct {
+	Content string
+	Info    models.BodyInfo
+}
+
+// ReadBody reads at most HTTP.MaxBodySize bytes of the decompressed response
+// body and converts it to UTF-8. The body is always closed.
+func (s *Scanner) ReadBody(resp *http.Response) (*Body, error) {
+	data, info, err := readLimited(resp, s.config.HTTP.MaxBodySize)
+	if err != nil {
+		return nil, err
+	}
+
+	content, charset := decodeCharset(data, resp.Header.Get("Content-Type"))
+	info.Charset = charset
+
+	return &Body{Content: content, Info: info}, nil
+}
+
+// readLimited returns at most limit bytes of the decompressed response body,
+// without any charset conversion. A limit of zero or less reads everything.
+// The body is always closed.
+func readLimited(resp *http.Response, limit int64) ([]byte, models.BodyInfo, error) {
+	info := models.BodyInfo{Limit: limit}
+	if resp == nil {
+		return nil, info, fmt.Errorf("response is nil")
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = resp.Body
+	if !resp.Uncompressed {
+		encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+		decoded, err := decompress(reader, encoding)
+		if err != nil {
+			return nil, info, err
+		}
+		reader = decoded
+		info.Encoding = encoding
+	}
+
+	if limit > 0 {
+		reader = io.LimitReader(reader, limit+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, info, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if limit > 0 && int64(len(data)) > limit {
+		data = data[:limit]
+		info.Truncated = true
+	}
+	info.Size = int64(len(data))
+
+	return data, info, nil
+}
+
+// decompress wraps r with decoders for the given Content-Encoding list,
+// applied in reverse order of their listing.
+func decompress(r io.Reader, encoding string) (io.Reader, error) {
+	if encoding == "" {
+		return r, nil
+	}
+
+	codings := strings.Split(encoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.TrimSpace(codings[i])
+		switch coding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode gzip body: %w", err)
+			}
+			r = gz
+		case "deflate":
+			r = newDeflateReader(r)
+		case "br":
+			r = brotli.NewReader(r)
+		default:
+			return nil, fmt.Errorf("unsupported content encoding: %s", coding)
+		}
+	}
+
+	return r, nil
+}
+
+// newDeflateReader handles both zlib-wrapped (RFC 1950) and raw (RFC 1951)
+// deflate streams, since servers disagree on what "deflate" means.
+func newDeflateReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err == nil && isZlibHeader(header[0], header[1]) {
+		if zr, err := zlib.NewReader(br); err == nil {
+			return zr
+		}
+	}
+	return flate.NewReader(br)
+}
+
+// isZlibHeader reports whether cmf and flg form a valid zlib stream header.
+func isZlibHeader(cmf, flg byte) bool {
+	const deflateMethod = 8
+	return cmf&0x0f == deflateMethod && (uint16(cmf)<<8|uint16(flg))%31 == 0
+}
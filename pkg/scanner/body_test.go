@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+)
+
+const bodyText = "<html><body>brotli body, brotli body</body></html>"
+
+// brotliBody is bodyText compressed with the reference brotli encoder.
+var brotliBody = []byte{
+	0x1b, 0x31, 0x00, 0xe8, 0x8d, 0x54, 0xb5, 0x7f, 0x84, 0x4a, 0x6f, 0x10, 0x31, 0x39, 0x3a,
+	0xcd, 0x92, 0xc6, 0xf6, 0x06, 0x36, 0xe0, 0xc0, 0xa5, 0x50, 0x02, 0x1c, 0xe8, 0x58, 0xe8,
+	0x02, 0x1f, 0x23, 0x2e, 0x8e, 0x33, 0x14, 0xa9, 0x7d, 0xf2, 0xc0, 0x10, 0x02,
+}
+
+func gzipped(data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func zlibbed(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func deflated(data []byte) []byte {
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func bodyResponse(encoding string, body []byte) *http.Response {
+	resp := &http.Response{Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(body))}
+	if encoding != "" {
+		resp.Header.Set("Content-Encoding", encoding)
+	}
+	return resp
+}
+
+func TestReadLimited(t *testing.T) {
+	text := []byte(bodyText)
+	n := int64(len(text))
+
+	tests := []struct {
+		name          string
+		encoding      string
+		body          []byte
+		limit         int64
+		want          string
+		wantTruncated bool
+		wantErr       string
+	}{
+		{"identity", "", text, 0, bodyText, false, ""},
+		{"limit equals size", "", text, n, bodyText, false, ""},
+		{"one byte over the limit", "", text, n - 1, bodyText[:n-1], true, ""},
+		{"gzip", "gzip", gzipped(text), 0, bodyText, false, ""},
+		{"x-gzip", "x-gzip", gzipped(text), 0, bodyText, false, ""},
+		{"zlib deflate", "deflate", zlibbed(text), 0, bodyText, false, ""},
+		{"raw deflate", "deflate", deflated(text), 0, bodyText, false, ""},
+		{"brotli", "br", brotliBody, 0, bodyText, false, ""},
+		{"limit applies after decoding", "gzip", gzipped(text), 10, bodyText[:10], true, ""},
+		{"stacked codings", "gzip, identity, deflate", zlibbed(gzipped(text)), 0, bodyText, false, ""},
+		{"unsupported coding", "compress", text, 0, "", false, "unsupported content encoding: compress"},
+		{"bad gzip", "gzip", text, 0, "", false, "failed to decode gzip body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, info, err := readLimited(bodyResponse(tt.encoding, tt.body), tt.limit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readLimited() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readLimited() error: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("data = %q, want %q", data, tt.want)
+			}
+			if info.Truncated != tt.wantTruncated {
+				t.Errorf("Truncated = %v, want %v", info.Truncated, tt.wantTruncated)
+			}
+			if info.Size != int64(len(tt.want)) {
+				t.Errorf("Size = %d, want %d", info.Size, len(tt.want))
+			}
+			if info.Encoding != tt.encoding {
+				t.Errorf("Encoding = %q, want %q", info.Encoding, tt.encoding)
+			}
+		})
+	}
+}
+
+func TestReadBody(t *testing.T) {
+	cfg := config.Default()
+	cfg.HTTP.MaxBodySize = 64
+
+	resp := bodyResponse("br", brotliBody)
+	resp.Header.Set("Content-Type", "text/html; charset=ISO-8859-1")
+	body, err := New(cfg).ReadBody(resp)
+	if err != nil {
+		t.Fatalf("ReadBody() error: %v", err)
+	}
+	if body.Content != bodyText {
+		t.Errorf("Content = %q, want %q", body.Content, bodyText)
+	}
+	if body.Info.Charset != "iso-8859-1" || body.Info.Limit != 64 || body.Info.Encoding != "br" {
+		t.Errorf("Info = %+v", body.Info)
+	}
+}
+
+func TestDecodeCharset(t *testing.T) {
+	latin1 := []byte("caf\xe9")
+	metaLatin1 := append([]byte(`<meta charset="latin1">`), latin1...)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		want        string
+		wantCharset string
+	}{
+		{"default utf-8", []byte("café"), "", "café", "utf-8"},
+		{"invalid utf-8 replaced", latin1, "text/html", "caf�", "utf-8"},
+		{"content type", latin1, "text/html; charset=ISO-8859-1", "café", "iso-8859-1"},
+		{"alias", latin1, "text/html; charset=latin1", "café", "iso-8859-1"},
+		{"meta", metaLatin1, "text/html", `<meta charset="latin1">café`, "iso-8859-1"},
+		{"content type beats meta", metaLatin1, "text/html; charset=utf-8", `<meta charset="latin1">caf` + "�", "utf-8"},
+		{"utf-8 BOM beats content type", []byte("\xef\xbb\xbfcafé"), "text/html; charset=latin1", "café", "utf-8"},
+		{"utf-16le BOM", []byte("\xff\xfeh\x00i\x00"), "", "hi", "utf-16le"},
+		{"utf-16be BOM", []byte("\xfe\xff\x00h\x00i"), "text/html; charset=utf-8", "hi", "utf-16be"},
+		{"utf-16 label is little endian", []byte("h\x00i\x00"), "text/plain; charset=UTF-16", "hi", "utf-16le"},
+		{"windows-1252", []byte("\x93quoted\x94 \x80"), "text/html; charset=cp1252", "“quoted” €", "windows-1252"},
+		{"iso-8859-1 keeps C1 controls", []byte("\x80"), "text/html; charset=iso-8859-1", "\u0080", "iso-8859-1"},
+		{"unknown charset read as utf-8", []byte("café"), "text/html; charset=shift_jis", "café", "shift_jis"},
+		{"meta beyond sniff length ignored", append(bytes.Repeat([]byte(" "), metaSniffLength), `<meta charset="latin1">`...), "", strings.Repeat(" ", metaSniffLength) + `<meta charset="latin1">`, "utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, charset := decodeCharset(tt.data, tt.contentType)
+			if got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+			if charset != tt.wantCharset {
+				t.Errorf("charset = %q, want %q", charset, tt.wantCharset)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package scanner
+
+import (
+	"bytes"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// metaSniffLength is how much of the document is searched for a
+// <meta charset> declaration, as in the HTML prescan algorithm.
+const metaSniffLength = 1024
+
+var metaCharsetRegex = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([a-z0-9_\-:.]+)`)
+
+// charsetAliases maps common charset labels to the canonical names handled
+// by decodeCharset.
+var charsetAliases = map[string]string{
+	"utf-8":             "utf-8",
+	"utf8":              "utf-8",
+	"unicode-1-1-utf-8": "utf-8",
+	"us-ascii":          "utf-8",
+	"ascii":             "utf-8",
+	"utf-16":            "utf-16le",
+	"utf-16le":          "utf-16le",
+	"utf-16be":          "utf-16be",
+	"iso-8859-1":        "iso-8859-1",
+	"iso8859-1":         "iso-8859-1",
+	"latin1":            "iso-8859-1",
+	"l1":                "iso-8859-1",
+	"windows-1252":      "windows-1252",
+	"cp1252":            "windows-1252",
+	"x-cp1252":          "windows-1252",
+}
+
+// windows1252High maps bytes 0x80-0x9F of windows-1252 to Unicode. All
+// other bytes map to the code point of the same value, as in ISO-8859-1.
+var windows1252High = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+// decodeCharset converts data to UTF-8. The charset is taken from a byte
+// order mark, then the Content-Type header, then a <meta> declaration,
+// defaulting to UTF-8. It returns the decoded text and the charset name.
+func decodeCharset(data []byte, contentType string) (string, string) {
+	charset, bomLen := sniffBOM(data)
+	data = data[bomLen:]
+
+	if charset == "" {
+		charset = charsetFromContentType(contentType)
+	}
+	if charset == "" {
+		charset = charsetFromMeta(data)
+	}
+
+	canonical, known := charsetAliases[charset]
+	switch {
+	case known:
+		charset = canonical
+	case charset == "":
+		charset, canonical = "utf-8", "utf-8"
+	default:
+		// Undecodable charsets are reported as declared and read as UTF-8.
+		canonical = "utf-8"
+	}
+
+	var text string
+	switch canonical {
+	case "utf-16le":
+		text = decodeUTF16(data, false)
+	case "utf-16be":
+		text = decodeUTF16(data, true)
+	case "iso-8859-1":
+		text = decodeSingleByte(data, nil)
+	case "windows-1252":
+		text = decodeSingleByte(data, &windows1252High)
+	default:
+		text = string(data)
+		if !utf8.ValidString(text) {
+			text = strings.ToValidUTF8(text, string(utf8.RuneError))
+		}
+	}
+
+	return text, charset
+}
+
+// sniffBOM returns the charset indicated by a byte order mark and its length.
+func sniffBOM(data []byte) (string, int) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8", 3
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return "utf-16le", 2
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return "utf-16be", 2
+	default:
+		return "", 0
+	}
+}
+
+// charsetFromContentType extracts the charset parameter of a media type.
+func charsetFromContentType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(params["charset"]))
+}
+
+// charsetFromMeta looks for a charset declaration in the document head.
+func charsetFromMeta(data []byte) string {
+	if len(data) > metaSniffLength {
+		data = data[:metaSniffLength]
+	}
+	match := metaCharsetRegex.FindSubmatch(data)
+	if match == nil {
+		return ""
+	}
+	return strings.ToLower(string(match[1]))
+}
+
+// decodeUTF16 decodes UTF-16 text with the given byte order.
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodeSingleByte decodes an ISO-8859-1 compatible single-byte charset,
+// optionally overriding the 0x80-0x9F range.
+func decodeSingleByte(data []byte, high *[32]rune) string {
+	var sb strings.Builder
+	sb.Grow(len(data))
+	for _, b := range data {
+		if high != nil && b >= 0x80 && b <= 0x9F {
+			sb.WriteRune(high[b-0x80])
+			continue
+		}
+		sb.WriteRune(rune(b))
+	}
+	return sb.String()
+}
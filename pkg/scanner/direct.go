@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// DirectResponse is a response fetched from a specific IP address instead of
+// the address the host name resolves to.
+type DirectResponse struct {
+	*Response
+	// CertificateValid reports whether the server presented a certificate
+	// that is trusted and valid for the requested host.
+	CertificateValid bool
+}
+
+// FetchDirect requests rawURL from ip, keeping the URL's host name for the
+// Host header and TLS SNI. Certificates are not verified during the
+// handshake, since an origin may present a self-signed or mismatched one;
+// the result is reported in CertificateValid. Redirects are not followed so
+// that an origin redirecting back to the public name is not fetched through
+// the CDN again.
+func (s *Scanner) FetchDirect(rawURL, ip string, limit int64) (*DirectResponse, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	addr := net.JoinHostPort(ip, port)
+
+	dialer := &net.Dialer{Timeout: s.config.TLS.Timeout}
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			TLSClientConfig: &tls.Config{
+				ServerName:         u.Hostname(),
+				InsecureSkipVerify: true, // checked by verifyCertificate
+			},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.HTTP.ShortTimeout)
+	defer cancel()
+
+	req, err := s.newRequest(ctx, http.MethodGet, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s via %s failed: %w", rawURL, ip, err)
+	}
+
+	data, info, err := readLimited(resp, limit)
+	if err != nil {
+		return nil, err
+	}
+	body, charset := decodeCharset(data, resp.Header.Get("Content-Type"))
+	info.Charset = charset
+
+	return &DirectResponse{
+		Response: &Response{
+			URL:        rawURL,
+			RequestURL: rawURL,
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       body,
+			BodyInfo:   info,
+		},
+		CertificateValid: verifyCertificate(resp.TLS, u.Hostname()),
+	}, nil
+}
+
+// verifyCertificate checks the peer chain of state against the system roots
+// for host.
+func verifyCertificate(state *tls.ConnectionState, host string) bool {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return false
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Intermediates: intermediates,
+	})
+	return err == nil
+}
+
+// Compare reports whether two responses serve the same page: the same status
+// and either a near-identical body (simhash) or the same title. distance is
+// the Hamming distance between the body simhashes.
+func Compare(a, b *Response) (same bool, distance int) {
+	fa, fb := fingerprintResponse(a, ""), fingerprintResponse(b, "")
+	distance = hamming(fa.SimHash, fb.SimHash)
+	if fa.StatusCode != fb.StatusCode {
+		return false, distance
+	}
+	return distance <= simhashThreshold || (fa.Title != "" && fa.Title == fb.Title), distance
+}
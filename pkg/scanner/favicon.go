@@ -0,0 +1,167 @@
+package scanner
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// maxFaviconSize bounds favicon downloads; real icons are a few kilobytes.
+const maxFaviconSize = 1 << 20
+
+// FetchFavicon downloads the page's icon and hashes it. The <link rel=icon>
+// target is tried first, then /favicon.ico on the page's origin. Responses
+// that are not a successful non-HTML body are skipped, so error pages served
+// with status 200 are not hashed.
+func (s *Scanner) FetchFavicon(pg *models.Page, pageURL string) (*models.Favicon, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid page URL %q", pageURL)
+	}
+
+	var candidates []string
+	if pg != nil && pg.Favicon != "" {
+		candidates = append(candidates, pg.Favicon)
+	}
+	fallback := base.ResolveReference(&url.URL{Path: "/favicon.ico"}).String()
+	if len(candidates) == 0 || candidates[0] != fallback {
+		candidates = append(candidates, fallback)
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, "data:") {
+			continue
+		}
+		fav, err := s.fetchFavicon(candidate)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return fav, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no favicon found")
+	}
+	return nil, lastErr
+}
+
+// fetchFavicon downloads and hashes a single icon URL.
+func (s *Scanner) fetchFavicon(iconURL string) (*models.Favicon, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.HTTP.Timeout)
+	defer cancel()
+
+	req, err := s.newRequest(ctx, http.MethodGet, iconURL)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/avif,image/webp,image/*,*/*;q=0.8")
+
+	resp, err := s.do(s.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("favicon request failed: %w", err)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	data, info, err := readLimited(resp, maxFaviconSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("favicon %s returned status %d", iconURL, resp.StatusCode)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "text/html" {
+		return nil, fmt.Errorf("favicon %s returned an HTML page", iconURL)
+	}
+	if len(data) == 0 || info.Truncated {
+		return nil, fmt.Errorf("favicon %s is empty or too large", iconURL)
+	}
+
+	md5Sum := md5.Sum(data)
+	sha256Sum := sha256.Sum256(data)
+	return &models.Favicon{
+		URL:         resp.Request.URL.String(),
+		Size:        info.Size,
+		ContentType: contentType,
+		MMH3:        FaviconHash(data),
+		MD5:         hex.EncodeToString(md5Sum[:]),
+		SHA256:      hex.EncodeToString(sha256Sum[:]),
+	}, nil
+}
+
+// FaviconHash returns the Shodan-compatible favicon hash: the signed 32-bit
+// MurmurHash3 of the icon's base64 encoding, wrapped at 76 characters per
+// line with a trailing newline as Python's base64.encodebytes produces.
+func FaviconHash(data []byte) int32 {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var b strings.Builder
+	b.Grow(len(encoded) + len(encoded)/76 + 1)
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76])
+		b.WriteByte('\n')
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	b.WriteByte('\n')
+
+	return int32(murmur3(b.String(), 0))
+}
+
+// murmur3 is MurmurHash3 x86_32.
+func murmur3(data string, seed uint32) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+
+	h := seed
+	n := len(data)
+	i := 0
+	for ; i+4 <= n; i += 4 {
+		k := binary.LittleEndian.Uint32([]byte(data[i : i+4]))
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+
+		h ^= k
+		h = bits.RotateLeft32(h, 13)
+		h = h*5 + 0xe6546b64
+	}
+
+	var k uint32
+	switch n - i {
+	case 3:
+		k ^= uint32(data[i+2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(data[i+1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(data[i])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(n)
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
@@ -0,0 +1,50 @@
+package scanner
+
+import "testing"
+
+func TestMurmur3(t *testing.T) {
+	tests := []struct {
+		data string
+		seed uint32
+		want uint32
+	}{
+		{"", 0, 0},
+		{"", 1, 0x514e28b7},
+		{"", 0xffffffff, 0x81f16f39},
+		{"\x00\x00\x00\x00", 0, 0x2362f9de},
+		{"a", 0x9747b28c, 0x7fa09ea6},
+		{"abc", 0, 0xb3dd93fa},
+		{"aaaa", 0x9747b28c, 0x5a97808a},
+		{"Hello, world!", 0x9747b28c, 0x24884cba},
+		{"The quick brown fox jumps over the lazy dog", 0x9747b28c, 0x2fa826cd},
+	}
+
+	for _, tt := range tests {
+		if got := murmur3(tt.data, tt.seed); got != tt.want {
+			t.Errorf("murmur3(%q, %#x) = %#x, want %#x", tt.data, tt.seed, got, tt.want)
+		}
+	}
+}
+
+func TestFaviconHash(t *testing.T) {
+	long := make([]byte, 200)
+	for i := range long {
+		long[i] = byte(i)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want int32
+	}{
+		// Values from Python: mmh3.hash(base64.encodebytes(data)).
+		{"short", []byte("icon"), 1355950459},
+		{"wrapped at 76 characters", long, -1874651529},
+	}
+
+	for _, tt := range tests {
+		if got := FaviconHash(tt.data); got != tt.want {
+			t.Errorf("FaviconHash(%s) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// Response is a completed request with its body read and decoded. URL is
+// the final URL after redirects; RequestURL is the one requested.
+type Response struct {
+	URL        string
+	RequestURL string
+	StatusCode int
+	Header     http.Header
+	Body       string
+	BodyInfo   models.BodyInfo
+}
+
+// MediaType returns the media type of the Content-Type header, lowercased and
+// without parameters.
+func (r *Response) MediaType() string {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mediaType
+}
+
+// Headers returns the response headers keyed by lowercase name, with
+// repeated values joined, as the detectors expect them.
+func (r *Response) Headers() map[string]string {
+	headers := make(map[string]string, len(r.Header))
+	for key, values := range r.Header {
+		headers[strings.ToLower(key)] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+// Fetch performs a GET request with the scanner's headers and reads the body
+// within HTTP.MaxBodySize. Non-2xx statuses are not errors.
+func (s *Scanner) Fetch(rawURL string) (*Response, error) {
+	return s.FetchLimited(rawURL, s.config.HTTP.MaxBodySize)
+}
+
+// FetchLimited is Fetch with an explicit body size limit, for probes that
+// only need the start of a possibly large file.
+func (s *Scanner) FetchLimited(rawURL string, limit int64) (*Response, error) {
+	return s.Request(http.MethodGet, rawURL, nil, limit)
+}
+
+// Request sends a bodyless request with the scanner's headers, overridden
+// by header, and reads at most limit bytes of the response body. Requests
+// that get no response are retried within the same timeout.
+func (s *Scanner) Request(method, rawURL string, header http.Header, limit int64) (*Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.HTTP.ShortTimeout)
+	defer cancel()
+
+	req, err := s.newRequest(ctx, method, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := s.do(s.client, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s failed: %w", method, rawURL, err)
+	}
+
+	data, info, err := readLimited(resp, limit)
+	if err != nil {
+		return nil, err
+	}
+	body, charset := decodeCharset(data, resp.Header.Get("Content-Type"))
+	info.Charset = charset
+
+	return &Response{
+		URL:        resp.Request.URL.String(),
+		RequestURL: rawURL,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		BodyInfo:   info,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+)
+
+// flakyServer drops the connection of the first failures requests without
+// answering, then replies normally.
+func flakyServer(t *testing.T, failures int32) *httptest.Server {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRequestRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32
+		retries  int
+		wantErr  bool
+	}{
+		{"no failures", 0, 0, false},
+		{"recovers within retries", 2, 2, false},
+		{"retries exhausted", 2, 1, true},
+		{"retries disabled", 1, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := flakyServer(t, tt.failures)
+			cfg := config.Default()
+			cfg.HTTP.MaxRetries = tt.retries
+			cfg.HTTP.RetryDelay = time.Millisecond
+
+			resp, err := New(cfg).Fetch(srv.URL)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Fetch() = %d, want error", resp.StatusCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fetch() error: %v", err)
+			}
+			if resp.Body != "ok" {
+				t.Errorf("Fetch() body = %q, want %q", resp.Body, "ok")
+			}
+		})
+	}
+}
+
+func TestRetryStopsAtDeadline(t *testing.T) {
+	srv := flakyServer(t, 100)
+	cfg := config.Default()
+	cfg.HTTP.ShortTimeout = 50 * time.Millisecond
+	cfg.HTTP.MaxRetries = 100
+	cfg.HTTP.RetryDelay = time.Second
+
+	start := time.Now()
+	if _, err := New(cfg).Fetch(srv.URL); err == nil {
+		t.Fatal("Fetch() succeeded, want error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Fetch() took %v, retries outlived the timeout", elapsed)
+	}
+}
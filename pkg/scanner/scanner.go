@@ -4,7 +4,6 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -22,8 +21,9 @@ const (
 
 // Scanner handles the main scanning logic.
 type Scanner struct {
-	config *config.Config
-	client *http.Client
+	config    *config.Config
+	client    *http.Client
+	baselines baselineCache
 }
 
 // New creates a new Scanner with the given configuration.
@@ -36,6 +36,8 @@ func New(cfg *config.Config) *Scanner {
 		TLSClientConfig: &tls.Config{
 			InsecureSkipVerify: cfg.TLS.InsecureSkipVerify,
 		},
+		// A custom TLS config disables HTTP/2 unless asked for explicitly.
+		ForceAttemptHTTP2:   true,
 		MaxIdleConns:        maxIdleConns,
 		MaxIdleConnsPerHost: maxIdleConnsPerHost,
 		IdleConnTimeout:     idleConnTimeout,
@@ -83,20 +85,13 @@ func (s *Scanner) AnalyzeHTTP(domain string) (*models.HTTPAnalysis, *http.Respon
 	ctx, cancel := context.WithTimeout(context.Background(), s.config.HTTP.Timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := s.newRequest(ctx, http.MethodGet, url)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, err
 	}
 
-	req.Header.Set("User-Agent", s.config.HTTP.UserAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("DNT", "1")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-
 	start := time.Now()
-	resp, err := s.client.Do(req)
+	resp, err := s.do(s.client, req)
 	if err != nil {
 		return nil, nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
@@ -113,6 +108,7 @@ func (s *Scanner) AnalyzeHTTP(domain string) (*models.HTTPAnalysis, *http.Respon
 		Headers:      headers,
 		ResponseTime: responseTime,
 		ContentType:  resp.Header.Get("Content-Type"),
+		Protocol:     resp.Proto,
 	}
 
 	// Check for redirects
@@ -123,19 +119,52 @@ func (s *Scanner) AnalyzeHTTP(domain string) (*models.HTTPAnalysis, *http.Respon
 	return analysis, resp, nil
 }
 
-// GetHTMLBody reads and returns the response body as string.
-func (s *Scanner) GetHTMLBody(resp *http.Response) (string, error) {
-	if resp == nil {
-		return "", fmt.Errorf("response is nil")
+// newRequest builds a request carrying the browser-like headers the scanner
+// sends on every fetch.
+func (s *Scanner) newRequest(ctx context.Context, method, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", s.config.HTTP.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	req.Header.Set("DNT", "1")
+	req.Header.Set("Connection", "keep-alive")
+	req.Header.Set("Upgrade-Insecure-Requests", "1")
+
+	return req, nil
+}
+
+// do sends a bodyless request, retrying up to HTTP.MaxRetries times,
+// HTTP.RetryDelay apart, when no response arrives. Retries share the
+// request's deadline, so they never extend its timeout.
+func (s *Scanner) do(client *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
+	for attempt := 0; err != nil && attempt < s.config.HTTP.MaxRetries; attempt++ {
+		timer := time.NewTimer(s.config.HTTP.RetryDelay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+		resp, err = client.Do(req)
 	}
-	defer resp.Body.Close()
+	return resp, err
+}
 
-	body, err := io.ReadAll(resp.Body)
+// GetHTMLBody reads and returns the response body as a UTF-8 string,
+// bounded by HTTP.MaxBodySize. Use ReadBody to learn whether it was truncated.
+func (s *Scanner) GetHTMLBody(resp *http.Response) (string, error) {
+	body, err := s.ReadBody(resp)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return "", err
 	}
 
-	return string(body), nil
+	return body.Content, nil
 }
 
 // normalizeDomain removes protocol and port from domain.
@@ -0,0 +1,200 @@
+package scanner
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"math"
+	"math/bits"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+const (
+	// simhashThreshold is the largest Hamming distance at which two bodies
+	// count as the same page.
+	simhashThreshold = 6
+	// baselineBodyLimit bounds how much of each baseline page is read.
+	baselineBodyLimit = 256 << 10
+)
+
+var (
+	titleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	tokenRegex = regexp.MustCompile(`[\p{L}\p{N}_]+`)
+)
+
+// baselinePaths are the shapes of non-existent path requested for the
+// soft-404 baseline; %s is replaced with a random token. Sites often route
+// extensions and subdirectories differently from top-level names.
+var baselinePaths = []string{"/%s", "/%s.php", "/%s/%s.html"}
+
+// baselineCache holds one soft-404 baseline per origin.
+type baselineCache struct {
+	mu      sync.Mutex
+	entries map[string]*models.Soft404Baseline
+}
+
+// Soft404Baseline requests a few random paths that cannot exist on origin
+// (scheme and host) and fingerprints the responses. The result is computed
+// once per origin and cached. A nil result means the site could not be
+// reached.
+func (s *Scanner) Soft404Baseline(origin string) *models.Soft404Baseline {
+	origin = strings.TrimSuffix(origin, "/")
+
+	s.baselines.mu.Lock()
+	defer s.baselines.mu.Unlock()
+
+	if b, ok := s.baselines.entries[origin]; ok {
+		return b
+	}
+
+	var baseline *models.Soft404Baseline
+	for _, shape := range baselinePaths {
+		token := RandomToken()
+		path := strings.ReplaceAll(shape, "%s", token)
+		resp, err := s.FetchLimited(origin+path, baselineBodyLimit)
+		if err != nil {
+			continue
+		}
+		if baseline == nil {
+			baseline = &models.Soft404Baseline{Origin: origin, CatchAll: true}
+		}
+		sample := fingerprintResponse(resp, token)
+		sample.Path = path
+		baseline.Samples = append(baseline.Samples, sample)
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			baseline.CatchAll = false
+		}
+	}
+
+	if s.baselines.entries == nil {
+		s.baselines.entries = make(map[string]*models.Soft404Baseline)
+	}
+	s.baselines.entries[origin] = baseline
+	return baseline
+}
+
+// IsSoft404 reports whether resp is indistinguishable from the site's answer
+// to a non-existent path: same status and either the same redirect target,
+// a near-identical body (simhash), or the same title and length bucket.
+// Genuine 404s therefore match too. The baseline for the response's origin
+// is computed on first use. Without a baseline it returns false.
+func (s *Scanner) IsSoft404(resp *Response) bool {
+	if resp == nil {
+		return false
+	}
+	u, err := url.Parse(resp.RequestURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	baseline := s.Soft404Baseline(u.Scheme + "://" + u.Host)
+	if baseline == nil {
+		return false
+	}
+
+	candidate := fingerprintResponse(resp, "")
+	for _, sample := range baseline.Samples {
+		if sample.StatusCode != candidate.StatusCode {
+			continue
+		}
+		if sample.Redirected && sample.FinalURL == candidate.FinalURL {
+			return true
+		}
+		if hamming(sample.SimHash, candidate.SimHash) <= simhashThreshold {
+			return true
+		}
+		if sample.Title != "" && sample.Title == candidate.Title && sample.LengthBucket == candidate.LengthBucket {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprintResponse summarizes a response for comparison. The random
+// token is removed from the body first, since error pages often echo the
+// requested path.
+func fingerprintResponse(resp *Response, token string) models.Soft404Sample {
+	body := resp.Body
+	if token != "" {
+		body = strings.ReplaceAll(body, token, "")
+	}
+
+	sample := models.Soft404Sample{
+		StatusCode:   resp.StatusCode,
+		Length:       resp.BodyInfo.Size,
+		LengthBucket: lengthBucket(resp.BodyInfo.Size),
+		SimHash:      fmt.Sprintf("%016x", simhash(body)),
+		FinalURL:     resp.URL,
+		Redirected:   resp.URL != resp.RequestURL,
+	}
+	sample.Title = Title(body)
+	return sample
+}
+
+// Title returns the whitespace-normalized <title> of an HTML body.
+func Title(body string) string {
+	m := titleRegex.FindStringSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(m[1])), " ")
+}
+
+// lengthBucket groups body sizes on a logarithmic scale, four buckets per
+// doubling, so small variations such as timestamps land in the same bucket.
+func lengthBucket(size int64) int {
+	return int(math.Log2(float64(size)+1) * 4)
+}
+
+// simhash computes a 64-bit SimHash over the words of text.
+func simhash(text string) uint64 {
+	var weights [64]int
+	for _, token := range tokenRegex.FindAllString(strings.ToLower(text), -1) {
+		h := fnv.New64a()
+		h.Write([]byte(token))
+		sum := h.Sum64()
+		for i := 0; i < 64; i++ {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var hash uint64
+	for i, w := range weights {
+		if w > 0 {
+			hash |= 1 << i
+		}
+	}
+	return hash
+}
+
+// hamming returns the number of differing bits between two hex simhashes.
+func hamming(a, b string) int {
+	var x, y uint64
+	if _, err := fmt.Sscanf(a, "%x", &x); err != nil {
+		return 64
+	}
+	if _, err := fmt.Sscanf(b, "%x", &y); err != nil {
+		return 64
+	}
+	return bits.OnesCount64(x ^ y)
+}
+
+// RandomToken returns a random hex string for paths that cannot exist on
+// the site and for markers that must not occur in a response by chance.
+func RandomToken() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "rankle404check"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+)
+
+const gitHead = "ref: refs/heads/main\n"
+
+func TestIsSoft404(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    map[string]bool // path -> soft 404
+	}{
+		{
+			name: "catch-all 200 echoing the path",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/.git/HEAD" {
+					w.Write([]byte(gitHead))
+					return
+				}
+				fmt.Fprintf(w, "<html><head><title>Not Found</title></head><body><h1>Oops</h1>"+
+					"<p>We looked everywhere for %s but could not find it. Try the search box "+
+					"or go back to the home page.</p></body></html>", r.URL.Path)
+			},
+			want: map[string]bool{"/admin/": true, "/backup.zip": true, "/.git/HEAD": false},
+		},
+		{
+			name: "genuine 404",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/.git/HEAD" {
+					w.Write([]byte(gitHead))
+					return
+				}
+				http.NotFound(w, r)
+			},
+			want: map[string]bool{"/admin/": true, "/.git/HEAD": false},
+		},
+		{
+			name: "redirect to login",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/login":
+					w.Write([]byte("<html><title>Sign in</title><form>user password</form></html>"))
+				case "/.git/HEAD":
+					w.Write([]byte(gitHead))
+				default:
+					http.Redirect(w, r, "/login?next="+r.URL.Path, http.StatusFound)
+				}
+			},
+			want: map[string]bool{"/admin/": true, "/.env": true, "/.git/HEAD": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			s := New(config.Default())
+			for path, want := range tt.want {
+				resp, err := s.Fetch(srv.URL + path)
+				if err != nil {
+					t.Fatalf("Fetch(%s) error: %v", path, err)
+				}
+				if got := s.IsSoft404(resp); got != want {
+					t.Errorf("IsSoft404(%s) = %t, want %t", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestIsSoft404WithoutBaseline(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	cfg := config.Default()
+	cfg.HTTP.MaxRetries = 0
+	s := New(cfg)
+	if s.IsSoft404(nil) {
+		t.Error("IsSoft404(nil) = true")
+	}
+	resp := &Response{URL: url + "/x", RequestURL: url + "/x", StatusCode: 404}
+	if s.IsSoft404(resp) {
+		t.Error("IsSoft404() without a baseline = true")
+	}
+}
+
+func TestLengthBucket(t *testing.T) {
+	tests := []struct {
+		size int64
+		want int
+	}{
+		{0, 0},
+		{1, 4},
+		{3, 8},
+		{1023, 40},
+		{1100, 40},
+		{2047, 44},
+	}
+	for _, tt := range tests {
+		if got := lengthBucket(tt.size); got != tt.want {
+			t.Errorf("lengthBucket(%d) = %d, want %d", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestSimhash(t *testing.T) {
+	page := "The page you requested could not be found on this server please check the address " +
+		"or use the navigation menu to continue browsing our catalogue of products and services"
+	hash := func(s string) string { return fmt.Sprintf("%016x", simhash(s)) }
+
+	tests := []struct {
+		name    string
+		a, b    string
+		maxDist int
+		minDist int
+	}{
+		{"identical", page, page, 0, 0},
+		{"case and punctuation", page, strings.ToUpper(page) + "!!!", 0, 0},
+		{"one word differs", page, strings.Replace(page, "catalogue", "catalog", 1), simhashThreshold, 0},
+		{"different page", page, "ref: refs/heads/main", 64, simhashThreshold + 1},
+	}
+	for _, tt := range tests {
+		d := hamming(hash(tt.a), hash(tt.b))
+		if d > tt.maxDist || d < tt.minDist {
+			t.Errorf("%s: distance %d, want %d..%d", tt.name, d, tt.minDist, tt.maxDist)
+		}
+	}
+	if simhash("") != 0 {
+		t.Errorf("simhash(\"\") = %x, want 0", simhash(""))
+	}
+}
+
+func TestHamming(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0", "0", 0},
+		{"0", "f", 4},
+		{"ffffffffffffffff", "0000000000000000", 64},
+		{"zz", "0", 64},
+		{"0", "", 64},
+	}
+	for _, tt := range tests {
+		if got := hamming(tt.a, tt.b); got != tt.want {
+			t.Errorf("hamming(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTitle(t *testing.T) {
+	if got := Title("<TITLE lang=en>\n  Tom &amp;\tJerry </TITLE>"); got != "Tom & Jerry" {
+		t.Errorf("Title() = %q", got)
+	}
+	if got := Title("<p>no title</p>"); got != "" {
+		t.Errorf("Title() = %q, want empty", got)
+	}
+}
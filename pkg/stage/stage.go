@@ -0,0 +1,112 @@
+// Package stage records the outcome of each part of a scan as a
+// models.Stage: whether it succeeded, failed, timed out or was skipped, how
+// long it took, and the class of error that stopped it.
+package stage
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// Error classes.
+const (
+	ClassTimeout     = "timeout"
+	ClassCanceled    = "canceled"
+	ClassNoSuchHost  = "no_such_host"
+	ClassDNS         = "dns"
+	ClassRefused     = "refused"
+	ClassUnreachable = "unreachable"
+	ClassTLS         = "tls"
+	ClassNetwork     = "network"
+	ClassHTTP        = "http"
+	ClassParse       = "parse"
+	ClassFile        = "file"
+	ClassOther       = "other"
+)
+
+// Done returns the stage that started at start and ended with err.
+func Done(start time.Time, err error) *models.Stage {
+	st := &models.Stage{
+		Status:     models.StageOK,
+		DurationMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		st.ErrorClass = Classify(err)
+		st.Error = err.Error()
+		st.Status = models.StageFailed
+		if st.ErrorClass == ClassTimeout {
+			st.Status = models.StageTimeout
+		}
+	}
+	return st
+}
+
+// Skipped returns a stage that did not run for reason.
+func Skipped(reason string) *models.Stage {
+	return &models.Stage{Status: models.StageSkipped, Reason: reason}
+}
+
+// Failed reports whether st failed or timed out.
+func Failed(st *models.Stage) bool {
+	return st != nil && (st.Status == models.StageFailed || st.Status == models.StageTimeout)
+}
+
+// Classify names the class of err, looking through wrapped errors.
+func Classify(err error) string {
+	var (
+		netErr     net.Error
+		dnsErr     *net.DNSError
+		opErr      *net.OpError
+		urlErr     *url.Error
+		pathErr    *fs.PathError
+		syntaxErr  *json.SyntaxError
+		typeErr    *json.UnmarshalTypeError
+		recordErr  tls.RecordHeaderError
+		alertErr   tls.AlertError
+		verifyErr  *tls.CertificateVerificationError
+		unknownCA  x509.UnknownAuthorityError
+		hostErr    x509.HostnameError
+		invalidErr x509.CertificateInvalidError
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ClassCanceled
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsNotFound {
+			return ClassNoSuchHost
+		}
+		return ClassDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ClassRefused
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return ClassUnreachable
+	case errors.As(err, &recordErr), errors.As(err, &alertErr), errors.As(err, &verifyErr),
+		errors.As(err, &unknownCA), errors.As(err, &hostErr), errors.As(err, &invalidErr):
+		return ClassTLS
+	case errors.As(err, &opErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET):
+		return ClassNetwork
+	case errors.As(err, &urlErr):
+		return ClassHTTP
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return ClassParse
+	case errors.As(err, &pathErr):
+		return ClassFile
+	}
+	return ClassOther
+}
@@ -1,8 +1,10 @@
 package tls
 
 import (
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"time"
@@ -53,6 +55,8 @@ func (a *Analyzer) Analyze(domain string) (*models.TLSAnalysis, error) {
 		SignatureAlg: cert.SignatureAlgorithm.String(),
 		PublicKeyAlg: cert.PublicKeyAlgorithm.String(),
 	}
+	sum := sha256.Sum256(cert.Raw)
+	analysis.Fingerprint = hex.EncodeToString(sum[:])
 
 	return analysis, nil
 }
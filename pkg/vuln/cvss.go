@@ -0,0 +1,91 @@
+package vuln
+
+import (
+	"math"
+	"strings"
+)
+
+// CVSS v3.x base metric weights, from the FIRST specification.
+var (
+	cvssAttackVector       = map[string]float64{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2}
+	cvssAttackComplexity   = map[string]float64{"L": 0.77, "H": 0.44}
+	cvssUserInteraction    = map[string]float64{"N": 0.85, "R": 0.62}
+	cvssImpact             = map[string]float64{"H": 0.56, "L": 0.22, "N": 0}
+	cvssPrivilegesUnscoped = map[string]float64{"N": 0.85, "L": 0.62, "H": 0.27}
+	cvssPrivilegesScoped   = map[string]float64{"N": 0.85, "L": 0.68, "H": 0.5}
+)
+
+// cvssBaseScore computes the base score of a CVSS v3.0/v3.1 vector such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N". It returns false for
+// vectors it cannot score.
+func cvssBaseScore(vector string) (float64, bool) {
+	if !strings.HasPrefix(vector, "CVSS:3.") {
+		return 0, false
+	}
+
+	metrics := make(map[string]string)
+	for _, field := range strings.Split(vector, "/")[1:] {
+		if key, value, ok := strings.Cut(field, ":"); ok {
+			metrics[key] = value
+		}
+	}
+
+	scoped := metrics["S"] == "C"
+	privileges := cvssPrivilegesUnscoped
+	if scoped {
+		privileges = cvssPrivilegesScoped
+	}
+
+	av, ok1 := cvssAttackVector[metrics["AV"]]
+	ac, ok2 := cvssAttackComplexity[metrics["AC"]]
+	pr, ok3 := privileges[metrics["PR"]]
+	ui, ok4 := cvssUserInteraction[metrics["UI"]]
+	c, ok5 := cvssImpact[metrics["C"]]
+	i, ok6 := cvssImpact[metrics["I"]]
+	a, ok7 := cvssImpact[metrics["A"]]
+	if !(ok1 && ok2 && ok3 && ok4 && ok5 && ok6 && ok7) {
+		return 0, false
+	}
+
+	iss := 1 - (1-c)*(1-i)*(1-a)
+	var impact float64
+	if scoped {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * av * ac * pr * ui
+	if scoped {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp rounds to one decimal place upwards, as defined by CVSS v3.1.
+func roundUp(value float64) float64 {
+	scaled := int(math.Round(value * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+	return (math.Floor(float64(scaled)/10000) + 1) / 10
+}
+
+// severityForScore maps a CVSS v3 base score to its qualitative rating.
+func severityForScore(score float64) string {
+	switch {
+	case score >= 9.0:
+		return "CRITICAL"
+	case score >= 7.0:
+		return "HIGH"
+	case score >= 4.0:
+		return "MEDIUM"
+	case score > 0:
+		return "LOW"
+	default:
+		return "NONE"
+	}
+}
@@ -0,0 +1,73 @@
+package vuln
+
+import "testing"
+
+func TestCVSSBaseScore(t *testing.T) {
+	tests := []struct {
+		vector string
+		want   float64
+		ok     bool
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, true},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 10.0, true},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1, true},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", 6.4, true},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", 7.8, true},
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", 5.9, true},
+		{"CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", 1.6, true},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0, true},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H", 0, false},
+		{"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 0, false},
+		{"AV:N/AC:L/Au:N/C:P/I:P/A:P", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := cvssBaseScore(tt.vector)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("cvssBaseScore(%q) = %v, %v, want %v, %v", tt.vector, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRoundUp(t *testing.T) {
+	tests := []struct {
+		value, want float64
+	}{
+		{4.0, 4.0},
+		{4.02, 4.1},
+		{4.000001, 4.0},
+		{4.00001, 4.1},
+		{9.99, 10.0},
+		{0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := roundUp(tt.value); got != tt.want {
+			t.Errorf("roundUp(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSeverityForScore(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{10, "CRITICAL"},
+		{9.0, "CRITICAL"},
+		{8.9, "HIGH"},
+		{7.0, "HIGH"},
+		{6.9, "MEDIUM"},
+		{4.0, "MEDIUM"},
+		{3.9, "LOW"},
+		{0.1, "LOW"},
+		{0, "NONE"},
+	}
+
+	for _, tt := range tests {
+		if got := severityForScore(tt.score); got != tt.want {
+			t.Errorf("severityForScore(%v) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package vuln
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// nvdFeed is the subset of the NVD CVE API 2.0 / JSON 2.0 feed schema used.
+type nvdFeed struct {
+	Vulnerabilities []struct {
+		CVE struct {
+			ID           string `json:"id"`
+			Descriptions []struct {
+				Lang  string `json:"lang"`
+				Value string `json:"value"`
+			} `json:"descriptions"`
+			Metrics struct {
+				V31 []nvdMetric `json:"cvssMetricV31"`
+				V30 []nvdMetric `json:"cvssMetricV30"`
+			} `json:"metrics"`
+			Configurations []struct {
+				Nodes []struct {
+					CPEMatch []nvdCPEMatch `json:"cpeMatch"`
+				} `json:"nodes"`
+			} `json:"configurations"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+type nvdMetric struct {
+	CVSSData struct {
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+}
+
+type nvdCPEMatch struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	Criteria              string `json:"criteria"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+// osvRecord is the subset of the OSV schema used.
+type osvRecord struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Summary  string   `json:"summary"`
+	Details  string   `json:"details"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string              `json:"type"`
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+		Versions []string `json:"versions"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// retireRepository is the retire.js jsrepository.json layout.
+type retireRepository map[string]struct {
+	Vulnerabilities []struct {
+		AtOrAbove   string `json:"atOrAbove"`
+		Below       string `json:"below"`
+		Severity    string `json:"severity"`
+		Identifiers struct {
+			CVE     []string `json:"CVE"`
+			GHSA    string   `json:"githubID"`
+			Summary string   `json:"summary"`
+		} `json:"identifiers"`
+	} `json:"vulnerabilities"`
+}
+
+// parseFeed detects the feed format and normalizes its advisories.
+func parseFeed(data []byte) ([]*Advisory, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var records []osvRecord
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse OSV records: %w", err)
+		}
+		return fromOSV(records), nil
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(bytes.TrimSpace(probe["vulnerabilities"]), []byte("[")):
+		var feed nvdFeed
+		if err := json.Unmarshal(data, &feed); err != nil {
+			return nil, fmt.Errorf("failed to parse NVD feed: %w", err)
+		}
+		return fromNVD(&feed), nil
+	case probe["id"] != nil && probe["affected"] != nil:
+		var record osvRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to parse OSV record: %w", err)
+		}
+		return fromOSV([]osvRecord{record}), nil
+	default:
+		var repo retireRepository
+		if err := json.Unmarshal(data, &repo); err != nil {
+			return nil, fmt.Errorf("unrecognized vulnerability feed format: %w", err)
+		}
+		return fromRetire(repo), nil
+	}
+}
+
+// fromNVD converts NVD CVE items. Only vulnerable application CPEs with a
+// wildcard or explicit version are kept.
+func fromNVD(feed *nvdFeed) []*Advisory {
+	advisories := make([]*Advisory, 0, len(feed.Vulnerabilities))
+	for _, item := range feed.Vulnerabilities {
+		cve := item.CVE
+		adv := &Advisory{ID: cve.ID}
+		for _, d := range cve.Descriptions {
+			if d.Lang == "en" {
+				adv.Summary = d.Value
+				break
+			}
+		}
+		for _, metrics := range [][]nvdMetric{cve.Metrics.V31, cve.Metrics.V30} {
+			if len(metrics) > 0 {
+				adv.CVSS = metrics[0].CVSSData.BaseScore
+				adv.Severity = strings.ToUpper(metrics[0].CVSSData.BaseSeverity)
+				break
+			}
+		}
+
+		for _, config := range cve.Configurations {
+			for _, node := range config.Nodes {
+				for _, match := range node.CPEMatch {
+					if aff, ok := nvdAffected(match); ok {
+						adv.Affected = append(adv.Affected, aff)
+					}
+				}
+			}
+		}
+		if len(adv.Affected) > 0 {
+			advisories = append(advisories, adv)
+		}
+	}
+	return advisories
+}
+
+// nvdAffected converts one cpeMatch entry.
+func nvdAffected(match nvdCPEMatch) (Affected, bool) {
+	parts := strings.Split(match.Criteria, ":")
+	if !match.Vulnerable || len(parts) < 6 || parts[2] != "a" {
+		return Affected{}, false
+	}
+
+	aff := Affected{Product: "cpe:" + strings.ToLower(parts[3]+":"+parts[4])}
+	if version := parts[5]; version != "*" && version != "-" {
+		aff.Versions = []string{version}
+		return aff, true
+	}
+
+	r := Range{
+		Introduced:   match.VersionStartIncluding,
+		Fixed:        match.VersionEndExcluding,
+		LastAffected: match.VersionEndIncluding,
+	}
+	if match.VersionStartExcluding != "" {
+		r.Introduced = match.VersionStartExcluding
+		r.IntroducedEx = true
+	}
+	if r == (Range{}) {
+		// An unbounded wildcard would flag every version of the product.
+		return Affected{}, false
+	}
+	aff.Ranges = []Range{r}
+	return aff, true
+}
+
+// fromOSV converts OSV records. Packages are keyed by ecosystem, so npm
+// packages map to "npm:<name>".
+func fromOSV(records []osvRecord) []*Advisory {
+	advisories := make([]*Advisory, 0, len(records))
+	for _, rec := range records {
+		adv := &Advisory{ID: rec.ID, Aliases: rec.Aliases, Summary: rec.Summary}
+		if adv.Summary == "" {
+			adv.Summary = firstLine(rec.Details)
+		}
+		for _, sev := range rec.Severity {
+			if score, ok := cvssBaseScore(sev.Score); ok && score > adv.CVSS {
+				adv.CVSS = score
+				adv.Severity = severityForScore(score)
+			}
+		}
+		if adv.Severity == "" {
+			adv.Severity = strings.ToUpper(rec.DatabaseSpecific.Severity)
+		}
+
+		for _, a := range rec.Affected {
+			aff := Affected{
+				Product:  strings.ToLower(a.Package.Ecosystem + ":" + a.Package.Name),
+				Versions: a.Versions,
+			}
+			for _, r := range a.Ranges {
+				if r.Type == "GIT" {
+					continue
+				}
+				aff.Ranges = append(aff.Ranges, osvRanges(r.Events)...)
+			}
+			adv.Affected = append(adv.Affected, aff)
+		}
+		advisories = append(advisories, adv)
+	}
+	return advisories
+}
+
+// osvRanges turns an OSV event list into intervals.
+func osvRanges(events []map[string]string) []Range {
+	var ranges []Range
+	var current *Range
+	for _, event := range events {
+		switch {
+		case event["introduced"] != "":
+			if current != nil {
+				ranges = append(ranges, *current)
+			}
+			current = &Range{Introduced: event["introduced"]}
+		case event["fixed"] != "" && current != nil:
+			current.Fixed = event["fixed"]
+			ranges = append(ranges, *current)
+			current = nil
+		case event["last_affected"] != "" && current != nil:
+			current.LastAffected = event["last_affected"]
+			ranges = append(ranges, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		ranges = append(ranges, *current)
+	}
+	return ranges
+}
+
+// fromRetire converts a retire.js repository. Entries without a CVE use
+// their GitHub advisory ID.
+func fromRetire(repo retireRepository) []*Advisory {
+	var advisories []*Advisory
+	for pkg, entry := range repo {
+		for _, v := range entry.Vulnerabilities {
+			ids := append([]string{}, v.Identifiers.CVE...)
+			if v.Identifiers.GHSA != "" {
+				ids = append(ids, v.Identifiers.GHSA)
+			}
+			if len(ids) == 0 || v.Below == "" {
+				continue
+			}
+			advisories = append(advisories, &Advisory{
+				ID:       ids[0],
+				Aliases:  ids[1:],
+				Summary:  v.Identifiers.Summary,
+				Severity: strings.ToUpper(v.Severity),
+				Affected: []Affected{{
+					Product: "npm:" + strings.ToLower(pkg),
+					Ranges:  []Range{{Introduced: v.AtOrAbove, Fixed: v.Below}},
+				}},
+			})
+		}
+	}
+	return advisories
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
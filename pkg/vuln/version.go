@@ -0,0 +1,97 @@
+package vuln
+
+import (
+	"strconv"
+	"strings"
+)
+
+// compareVersions compares two dotted version strings numerically, returning
+// -1, 0 or 1. Missing components count as zero, so "1.2" equals "1.2.0". A
+// pre-release suffix ("3.0.0-rc1", "1.0b2") sorts before the release.
+func compareVersions(a, b string) int {
+	aCore, aPre := splitPrerelease(a)
+	bCore, bPre := splitPrerelease(b)
+
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		if c := compareComponent(part(aParts, i), part(bParts, i)); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// splitPrerelease separates the numeric core of a version from any suffix.
+func splitPrerelease(v string) (string, string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	for i, c := range v {
+		if c != '.' && (c < '0' || c > '9') {
+			return strings.TrimSuffix(v[:i], "."), strings.TrimLeft(v[i:], "-+.")
+		}
+	}
+	return v, ""
+}
+
+// part returns the i-th component or "0" if it is missing.
+func part(parts []string, i int) string {
+	if i < len(parts) && parts[i] != "" {
+		return parts[i]
+	}
+	return "0"
+}
+
+// compareComponent compares numeric components, falling back to string
+// order for anything that does not parse.
+func compareComponent(a, b string) int {
+	ai, aErr := strconv.Atoi(a)
+	bi, bErr := strconv.Atoi(b)
+	if aErr == nil && bErr == nil {
+		switch {
+		case ai < bi:
+			return -1
+		case ai > bi:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// Range is an affected version interval. Empty bounds are open.
+type Range struct {
+	Introduced   string `json:"introduced,omitempty"`
+	IntroducedEx bool   `json:"introduced_exclusive,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// Contains reports whether version falls inside the range.
+func (r Range) Contains(version string) bool {
+	if r.Introduced != "" && r.Introduced != "0" {
+		c := compareVersions(version, r.Introduced)
+		if c < 0 || (c == 0 && r.IntroducedEx) {
+			return false
+		}
+	}
+	if r.Fixed != "" && compareVersions(version, r.Fixed) >= 0 {
+		return false
+	}
+	if r.LastAffected != "" && compareVersions(version, r.LastAffected) > 0 {
+		return false
+	}
+	return true
+}
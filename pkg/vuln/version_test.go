@@ -0,0 +1,49 @@
+package vuln
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"v2.0.0", "2.0.0", 0},
+		{"1.10.0", "1.9.9", 1},
+		{"1.2.3", "1.2.4", -1},
+		{"3.0.0-rc1", "3.0.0", -1},
+		{"3.0.0", "3.0.0-rc1", 1},
+		{"1.0b1", "1.0b2", -1},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       Range
+		version string
+		want    bool
+	}{
+		{"open range", Range{}, "1.0.0", true},
+		{"below introduced", Range{Introduced: "2.0.0", Fixed: "2.5.0"}, "1.9.0", false},
+		{"at introduced", Range{Introduced: "2.0.0", Fixed: "2.5.0"}, "2.0.0", true},
+		{"at exclusive introduced", Range{Introduced: "2.0.0", IntroducedEx: true}, "2.0.0", false},
+		{"introduced zero", Range{Introduced: "0", Fixed: "1.0.0"}, "0.9.0", true},
+		{"at fixed", Range{Introduced: "2.0.0", Fixed: "2.5.0"}, "2.5.0", false},
+		{"at last affected", Range{LastAffected: "1.4.2"}, "1.4.2", true},
+		{"past last affected", Range{LastAffected: "1.4.2"}, "1.4.3", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.Contains(tt.version); got != tt.want {
+			t.Errorf("%s: %+v.Contains(%q) = %v, want %v", tt.name, tt.r, tt.version, got, tt.want)
+		}
+	}
+}
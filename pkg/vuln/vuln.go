@@ -0,0 +1,259 @@
+// Package vuln correlates detected technology versions with an offline
+// vulnerability dataset. No network access is needed at scan time.
+package vuln
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+//go:embed data/*.json
+var embeddedData embed.FS
+
+// productsFile maps technology names to CPE products and package names.
+const productsFile = "data/products.json"
+
+// Advisory is a vulnerability record normalized from any supported feed.
+type Advisory struct {
+	ID       string
+	Aliases  []string
+	Summary  string
+	CVSS     float64
+	Severity string
+	Affected []Affected
+	Source   string
+}
+
+// Affected lists the vulnerable versions of one product. Product keys are
+// "cpe:vendor:product" or "npm:package".
+type Affected struct {
+	Product  string
+	Ranges   []Range
+	Versions []string
+}
+
+// productMapping links a technology name to the identifiers feeds use.
+type productMapping struct {
+	CPE []string `json:"cpe"`
+	NPM []string `json:"npm"`
+}
+
+// Database is an in-memory vulnerability dataset indexed by product.
+type Database struct {
+	byProduct map[string][]*Advisory
+	products  map[string]productMapping
+	count     int
+}
+
+// New returns a database seeded with the embedded snapshot.
+func New() *Database {
+	db, err := newEmbedded()
+	if err != nil {
+		// The embedded data ships with the binary; failing to parse it is a
+		// packaging bug, not a runtime condition.
+		panic(err)
+	}
+	return db
+}
+
+// newEmbedded loads the product mapping and seed advisories.
+func newEmbedded() (*Database, error) {
+	db := &Database{
+		byProduct: make(map[string][]*Advisory),
+		products:  make(map[string]productMapping),
+	}
+
+	data, err := embeddedData.ReadFile(productsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read product mapping: %w", err)
+	}
+	if err := json.Unmarshal(data, &db.products); err != nil {
+		return nil, fmt.Errorf("failed to parse product mapping: %w", err)
+	}
+
+	entries, err := embeddedData.ReadDir("data")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded advisories: %w", err)
+	}
+	for _, entry := range entries {
+		name := "data/" + entry.Name()
+		if name == productsFile {
+			continue
+		}
+		data, err := embeddedData.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded advisories: %w", err)
+		}
+		if err := db.add(entry.Name(), data); err != nil {
+			return nil, err
+		}
+	}
+
+	return db, nil
+}
+
+// Load imports a feed snapshot from a file, or every .json file in a
+// directory. NVD JSON 2.0 feeds, OSV records (single, array or one per
+// file) and retire.js jsrepository.json are recognized automatically.
+func (db *Database) Load(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to open vulnerability feed: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return fmt.Errorf("failed to list vulnerability feeds: %w", err)
+		}
+		sort.Strings(files)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read vulnerability feed: %w", err)
+		}
+		if err := db.add(filepath.Base(file), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of advisories loaded.
+func (db *Database) Len() int {
+	return db.count
+}
+
+// add parses a feed and indexes its advisories.
+func (db *Database) add(source string, data []byte) error {
+	advisories, err := parseFeed(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	for _, adv := range advisories {
+		adv.Source = source
+		db.count++
+		indexed := make(map[string]bool)
+		for _, aff := range adv.Affected {
+			if !indexed[aff.Product] {
+				indexed[aff.Product] = true
+				db.byProduct[aff.Product] = append(db.byProduct[aff.Product], adv)
+			}
+		}
+	}
+	return nil
+}
+
+// Match returns the vulnerabilities affecting the given technologies. Only
+// technologies with a detected version are considered. Results are ordered
+// by CVSS score, highest first.
+func (db *Database) Match(techs []models.Technology) []models.Vulnerability {
+	var vulns []models.Vulnerability
+	seen := make(map[string]bool)
+
+	for _, tech := range techs {
+		if tech.Version == "" {
+			continue
+		}
+		for _, product := range db.productKeys(tech) {
+			for _, adv := range db.byProduct[product] {
+				key := adv.ID + "\x00" + tech.Name
+				if seen[key] {
+					continue
+				}
+				fixed, affected := adv.affects(product, tech.Version)
+				if !affected {
+					continue
+				}
+				seen[key] = true
+				vulns = append(vulns, models.Vulnerability{
+					ID:            adv.ID,
+					Aliases:       adv.Aliases,
+					Technology:    tech.Name,
+					Version:       tech.Version,
+					CVSS:          adv.CVSS,
+					Severity:      adv.Severity,
+					Summary:       adv.Summary,
+					FixedVersions: fixed,
+					Source:        adv.Source,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(vulns, func(i, j int) bool {
+		if vulns[i].CVSS != vulns[j].CVSS {
+			return vulns[i].CVSS > vulns[j].CVSS
+		}
+		return vulns[i].ID < vulns[j].ID
+	})
+	return vulns
+}
+
+// productKeys returns the feed identifiers for a technology, from its
+// fingerprint CPE, the embedded product mapping and the npm package found
+// in a source map.
+func (db *Database) productKeys(tech models.Technology) []string {
+	var keys []string
+	if cpe := cpeProduct(tech.CPE); cpe != "" {
+		keys = append(keys, "cpe:"+cpe)
+	}
+	mapping := db.products[tech.Name]
+	for _, cpe := range mapping.CPE {
+		keys = append(keys, "cpe:"+strings.ToLower(cpe))
+	}
+	for _, pkg := range mapping.NPM {
+		keys = append(keys, "npm:"+strings.ToLower(pkg))
+	}
+	if tech.Package != "" && !slices.Contains(mapping.NPM, tech.Package) {
+		keys = append(keys, "npm:"+strings.ToLower(tech.Package))
+	}
+	return keys
+}
+
+// affects reports whether version of product is vulnerable and lists the
+// versions that fix it, taken from the ranges that contain version.
+func (adv *Advisory) affects(product, version string) ([]string, bool) {
+	var fixed []string
+	affected := false
+	for _, aff := range adv.Affected {
+		if aff.Product != product {
+			continue
+		}
+		for _, r := range aff.Ranges {
+			if !r.Contains(version) {
+				continue
+			}
+			affected = true
+			if r.Fixed != "" && !slices.Contains(fixed, r.Fixed) {
+				fixed = append(fixed, r.Fixed)
+			}
+		}
+		for _, v := range aff.Versions {
+			if compareVersions(v, version) == 0 {
+				affected = true
+			}
+		}
+	}
+	return fixed, affected
+}
+
+// cpeProduct extracts "vendor:product" from a CPE 2.3 string.
+func cpeProduct(cpe string) string {
+	parts := strings.Split(cpe, ":")
+	if len(parts) < 5 || parts[0] != "cpe" {
+		return ""
+	}
+	return strings.ToLower(parts[3] + ":" + parts[4])
+}
@@ -0,0 +1,103 @@
+package vuln
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+func TestAdvisoryAffects(t *testing.T) {
+	adv := &Advisory{
+		ID: "CVE-0000-0001",
+		Affected: []Affected{{
+			Product: "npm:lodash",
+			Ranges: []Range{
+				{Introduced: "0", Fixed: "3.10.1"},
+				{Introduced: "4.0.0", Fixed: "4.17.21"},
+			},
+			Versions: []string{"5.0.0-beta"},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		product   string
+		version   string
+		wantFixed []string
+		wantOK    bool
+	}{
+		{"second range", "npm:lodash", "4.17.20", []string{"4.17.21"}, true},
+		{"first range", "npm:lodash", "3.9.0", []string{"3.10.1"}, true},
+		{"fixed version", "npm:lodash", "4.17.21", nil, false},
+		{"between ranges", "npm:lodash", "3.10.1", nil, false},
+		{"listed version", "npm:lodash", "5.0.0-beta", nil, true},
+		{"other product", "npm:underscore", "1.0.0", nil, false},
+	}
+
+	for _, tt := range tests {
+		fixed, ok := adv.affects(tt.product, tt.version)
+		if ok != tt.wantOK || !reflect.DeepEqual(fixed, tt.wantFixed) {
+			t.Errorf("%s: affects(%q, %q) = %q, %v, want %q, %v", tt.name, tt.product, tt.version, fixed, ok, tt.wantFixed, tt.wantOK)
+		}
+	}
+}
+
+func TestCPEProduct(t *testing.T) {
+	tests := []struct {
+		cpe, want string
+	}{
+		{"cpe:2.3:a:Nginx:nginx:*:*:*:*:*:*:*:*", "nginx:nginx"},
+		{"cpe:2.3:a:jquery", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := cpeProduct(tt.cpe); got != tt.want {
+			t.Errorf("cpeProduct(%q) = %q, want %q", tt.cpe, got, tt.want)
+		}
+	}
+}
+
+func TestMatchProductMapping(t *testing.T) {
+	// retire.js keys AngularJS as "angularjs", OSV as the npm package
+	// "angular"; both must reach the AngularJS fingerprint.
+	const retire = `{"angularjs": {"vulnerabilities": [
+		{"below": "1.8.0", "severity": "medium", "identifiers": {"CVE": ["CVE-0000-0002"], "summary": "retire.js advisory"}}
+	]}}`
+	const osv = `[{"id": "GHSA-0000-0000-0003", "affected": [{
+		"package": {"ecosystem": "npm", "name": "angular"},
+		"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.6.0"}]}]
+	}]}]`
+
+	db := New()
+	if err := db.add("jsrepository.json", []byte(retire)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.add("osv.json", []byte(osv)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		version string
+		want    []string
+	}{
+		{"1.5.8", []string{"CVE-0000-0002", "GHSA-0000-0000-0003"}},
+		{"1.7.9", []string{"CVE-0000-0002"}},
+		{"1.8.0", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, v := range db.Match([]models.Technology{{Name: "AngularJS", Version: tt.version}}) {
+			if v.Source == "jsrepository.json" || v.Source == "osv.json" {
+				got = append(got, v.ID)
+			}
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Match(AngularJS %s) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
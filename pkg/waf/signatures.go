@@ -0,0 +1,142 @@
+package waf
+
+import "github.com/javicosvml/rankle-go/pkg/rules"
+
+// signatures is the built-in WAF rule set, in priority order. Patterns are
+// specific to the vendor: a bare substring such as "f5" matches far too many
+// unrelated values. Body patterns match block pages, not product names, as
+// any page may mention a vendor.
+var signatures = []rules.Rule{
+	{
+		Name: "Cloudflare",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("cf-ray", ""), rules.Header("server", `(?i)^cloudflare`), rules.Header("cf-mitigated", "")},
+			rules.Fields(rules.FieldCookie, `^__cfduid$`, `^__cf_bm$`, `^cf_clearance$`),
+			rules.Fields(rules.FieldBody, `(?i)Attention Required! \| Cloudflare`, `cf-error-details`, `(?i)Cloudflare Ray ID:`),
+		),
+	},
+	{
+		Name: "Imperva Incapsula",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("x-iinfo", ""), rules.Header("x-cdn", `(?i)incapsula`)},
+			rules.Fields(rules.FieldCookie, `^incap_ses_`, `^visid_incap_`, `^nlbi_`),
+			rules.Fields(rules.FieldBody, `Incapsula incident ID`, `_Incapsula_Resource`),
+		),
+	},
+	{
+		Name: "Akamai Kona Site Defender",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("server", `(?i)^AkamaiGHost`), rules.Header("akamai-grn", "")},
+			rules.Fields(rules.FieldCookie, `^ak_bmsc$`, `^bm_sz$`, `^_abck$`),
+			rules.Fields(rules.FieldBody, `(?s)Access Denied.*Reference #\d+\.[0-9a-f]+\.\d+\.[0-9a-f]+`),
+		),
+	},
+	{
+		Name: "Sucuri",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("x-sucuri-id", ""), rules.Header("x-sucuri-cache", ""), rules.Header("server", `(?i)^Sucuri`)},
+			rules.Fields(rules.FieldBody, `Sucuri WebSite Firewall - (?:Access Denied|CloudProxy)`),
+		),
+	},
+	{
+		Name: "F5 BIG-IP ASM",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("server", `(?i)^big-?ip`), rules.Header("x-wa-info", `^\[`)},
+			rules.Fields(rules.FieldCookie, `^BIGipServer`, `^TS[0-9a-f]{6,8}$`, `^F5_(?:fullWT|ST|HT_shrinked)$`),
+			rules.Fields(rules.FieldBody, `The requested URL was rejected\. Please consult with your administrator\.`),
+		),
+	},
+	{
+		Name: "AWS WAF",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("x-amzn-waf-action", "")},
+			rules.Fields(rules.FieldCookie, `^aws-waf-token$`),
+			rules.Fields(rules.FieldBody, `(?s)Request blocked\..*Generated by cloudfront`),
+		),
+	},
+	{
+		Name: "Azure Web Application Firewall",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("server", `Microsoft-Azure-Application-Gateway`)},
+			rules.Fields(rules.FieldBody, `<center>Microsoft-Azure-Application-Gateway/v\d+</center>`),
+		),
+	},
+	{
+		Name:     "Fastly Next-Gen WAF",
+		Matchers: []rules.Matcher{rules.Header("x-sigsci-requestid", ""), rules.Header("x-sigsci-tags", "")},
+	},
+	{
+		Name: "ModSecurity",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("server", `(?i)mod_security|NOYB`)},
+			rules.Fields(rules.FieldBody, `This error was generated by Mod_Security`, `(?i)ModSecurity Action`, `rules of the mod_security module`),
+		),
+	},
+	{
+		Name: "Barracuda",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("server", `(?i)^barracuda`)},
+			rules.Fields(rules.FieldCookie, `^barra_counter_session$`, `^BNI__BARRACUDA_LB_COOKIE$`, `^BNI_persistence$`),
+			rules.Fields(rules.FieldBody, `(?s)You have been blocked.*Barracuda Networks,? Inc`),
+		),
+	},
+	{
+		Name: "FortiWeb",
+		Matchers: concat(
+			rules.Fields(rules.FieldCookie, `^FORTIWAFSID$`, `^cookiesession1$`),
+			rules.Fields(rules.FieldBody, `class="fgd_icon"`, `(?s)Web Page Blocked!.*Attack ID:`),
+		),
+	},
+	{
+		Name: "Citrix NetScaler",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("via", `NS-CACHE`), rules.Header("cneonction", "")},
+			rules.Fields(rules.FieldCookie, `^ns_af$`, `^citrix_ns_id$`, `^NSC_`),
+			rules.Fields(rules.FieldBody, `NS Transaction ID:`),
+		),
+	},
+	{
+		Name: "Radware AppWall",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("x-sl-compstate", "")},
+			rules.Fields(rules.FieldBody, `(?s)Unauthorized Activity Has Been Detected.*Case Number`),
+		),
+	},
+	{
+		Name: "Reblaze",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("server", `(?i)^Reblaze Secure Web Gateway`)},
+			rules.Fields(rules.FieldCookie, `^rbzid$`),
+		),
+	},
+	{
+		Name: "DDoS-Guard",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("server", `(?i)^ddos-guard`)},
+			rules.Fields(rules.FieldCookie, `^__ddg[0-9_]`),
+		),
+	},
+	{
+		Name:     "Wallarm",
+		Matchers: []rules.Matcher{rules.Header("server", `(?i)nginx-wallarm`)},
+	},
+	{
+		Name:     "Wordfence",
+		Matchers: rules.Fields(rules.FieldBody, `Generated by Wordfence`, `This response was generated by Wordfence`),
+	},
+	{
+		Name: "TransparentEdge",
+		Matchers: concat(
+			[]rules.Matcher{rules.Header("server", `(?i)transparentedge`)},
+			rules.Fields(rules.FieldBody, `(?i)<title>[^<]*transparent ?edge[^<]*</title>`),
+		),
+	},
+}
+
+func concat(groups ...[]rules.Matcher) []rules.Matcher {
+	var all []rules.Matcher
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	return all
+}
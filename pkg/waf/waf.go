@@ -0,0 +1,177 @@
+// Package waf identifies Web Application Firewalls, passively from response
+// headers and cookies, and actively by sending a few harmless requests that
+// look like attacks and comparing the answers with a benign baseline, in the
+// manner of wafw00f.
+package waf
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/rules"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+	"github.com/javicosvml/rankle-go/pkg/stage"
+)
+
+const (
+	// maxProbeBody bounds the block page bodies read.
+	maxProbeBody = 64 << 10
+
+	// behaviorConfidence is given to blocked probes: blocking shows that a
+	// filter exists but not whose it is.
+	behaviorConfidence = 50
+)
+
+// Identify matches the signatures against a response. headers uses
+// lowercase keys; cookies are the names set by the response. Matches are
+// returned in signature order.
+func Identify(headers map[string]string, cookies []string, body string) []rules.Match {
+	in := rules.Headers(headers)
+	in.Add(rules.FieldCookie, cookies...)
+	if body != "" {
+		in.Add(rules.FieldBody, body)
+	}
+	return rules.Evaluate(signatures, in)
+}
+
+// CookieNames returns the names of the cookies a response sets.
+func CookieNames(header http.Header) []string {
+	resp := http.Response{Header: header}
+	var names []string
+	for _, c := range resp.Cookies() {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// probe is one attack-looking request, sent as a query parameter so the
+// path itself stays valid.
+type probe struct {
+	name  string
+	param string
+	value string
+}
+
+// probes are recognizable to any WAF but harmless to the application: they
+// target a random parameter the site does not use.
+var probes = []probe{
+	{"xss", "q", `<script>alert("rankle")</script>`},
+	{"sqli", "id", `1' OR '1'='1' -- `},
+	{"traversal", "file", "../../../../etc/passwd"},
+	{"command", "cmd", "; cat /etc/passwd"},
+}
+
+// Prober runs the active WAF checks.
+type Prober struct {
+	scan *scanner.Scanner
+}
+
+// New creates a Prober using the scanner's HTTP client.
+func New(scan *scanner.Scanner) *Prober {
+	return &Prober{scan: scan}
+}
+
+// Probe sends a benign request and the attack-looking probes to origin. A
+// probe counts as blocked when its status differs from the baseline with a
+// 4xx or 5xx code, or the connection is reset or closed without an answer;
+// timeouts and other failures are recorded but say nothing about a
+// filter. The vendor comes from
+// header and cookie signatures on any response and from block page
+// signatures on blocked responses only, as the site's own pages may name a
+// vendor; blocking without a known signature is reported as a generic WAF.
+func (p *Prober) Probe(origin string) (*models.WAFAnalysis, error) {
+	origin = strings.TrimSuffix(origin, "/") + "/"
+
+	baseline, err := p.scan.Request(http.MethodGet, origin+"?rankle=1", nil, maxProbeBody)
+	if err != nil {
+		return nil, fmt.Errorf("WAF baseline request failed: %w", err)
+	}
+
+	analysis := &models.WAFAnalysis{}
+	responses := []response{{Response: baseline}}
+	var blocked []models.Evidence
+
+	for _, pr := range probes {
+		target := origin + "?" + url.Values{pr.param: {pr.value}}.Encode()
+		result := models.WAFProbe{Name: pr.name, URL: target}
+
+		resp, err := p.scan.Request(http.MethodGet, target, nil, maxProbeBody)
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+			result.ErrorClass = stage.Classify(err)
+			result.Blocked = result.ErrorClass == stage.ClassNetwork
+		default:
+			result.StatusCode = resp.StatusCode
+			result.Blocked = resp.StatusCode != baseline.StatusCode && resp.StatusCode >= 400
+			responses = append(responses, response{Response: resp, blocked: result.Blocked})
+		}
+		if result.Blocked {
+			match := result.Error
+			if match == "" {
+				match = fmt.Sprintf("HTTP %d (baseline %d)", result.StatusCode, baseline.StatusCode)
+			}
+			blocked = append(blocked, models.Evidence{Source: models.EvidenceBehavior, Key: pr.name, Match: match, Confidence: behaviorConfidence})
+		}
+		analysis.Probes = append(analysis.Probes, result)
+	}
+
+	if match := identifyAll(responses); match != nil {
+		analysis.Detected = true
+		analysis.Vendor = match.Name
+		analysis.Evidence = match.Evidence
+	}
+	if len(blocked) > 0 {
+		analysis.Detected = true
+		if analysis.Vendor == "" {
+			analysis.Vendor = "Generic"
+		}
+		analysis.Evidence = append(analysis.Evidence, blocked...)
+	}
+
+	return analysis, nil
+}
+
+// response is a probe answer and whether it was blocked.
+type response struct {
+	*scanner.Response
+	blocked bool
+}
+
+// identifyAll merges signature hits across responses and returns the
+// vendor with the most distinct evidence, preferring earlier signatures on
+// ties. Bodies are only matched for blocked responses.
+func identifyAll(responses []response) *rules.Match {
+	merged := make(map[string]*rules.Match)
+	seen := make(map[string]bool)
+
+	for _, resp := range responses {
+		body := ""
+		if resp.blocked {
+			body = resp.Body
+		}
+		for _, m := range Identify(resp.Headers(), CookieNames(resp.Header), body) {
+			if merged[m.Name] == nil {
+				merged[m.Name] = &rules.Match{Name: m.Name, Priority: m.Priority}
+			}
+			for _, e := range m.Evidence {
+				key := m.Name + "\x00" + e.Source + "\x00" + e.Key + "\x00" + e.Match
+				if !seen[key] {
+					seen[key] = true
+					merged[m.Name].Evidence = append(merged[m.Name].Evidence, e)
+				}
+			}
+		}
+	}
+
+	var best *rules.Match
+	for _, sig := range signatures {
+		if m := merged[sig.Name]; m != nil && (best == nil || len(m.Evidence) > len(best.Evidence)) {
+			best = m
+		}
+	}
+	return best
+}
@@ -0,0 +1,153 @@
+package waf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/javicosvml/rankle-go/internal/config"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+	"github.com/javicosvml/rankle-go/pkg/stage"
+)
+
+func page(status int, header http.Header, body string) *scanner.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &scanner.Response{StatusCode: status, Header: header, Body: body}
+}
+
+func TestIdentifyAll(t *testing.T) {
+	// A product page that names vendors without being protected by them.
+	marketing := `<h1>Partners</h1><p>We integrate with FortiWeb, Barracuda Networks, Inc.
+and Microsoft-Azure-Application-Gateway. The request is blocked. Transparent Edge.</p>`
+	fortiBlock := `<h2 class="fgd_icon">block</h2><h1>Web Page Blocked!</h1><p>Attack ID: 20000051</p>`
+
+	tests := []struct {
+		name      string
+		responses []response
+		want      string
+	}{
+		{
+			name: "vendor names in an unblocked page",
+			responses: []response{
+				{Response: page(200, nil, marketing)},
+				{Response: page(200, nil, marketing)},
+			},
+			want: "",
+		},
+		{
+			name: "block page of a blocked probe",
+			responses: []response{
+				{Response: page(200, nil, marketing)},
+				{Response: page(403, nil, fortiBlock), blocked: true},
+			},
+			want: "FortiWeb",
+		},
+		{
+			name: "block page text on the baseline",
+			responses: []response{
+				{Response: page(200, nil, fortiBlock)},
+				{Response: page(200, nil, "")},
+			},
+			want: "",
+		},
+		{
+			name: "headers count on any response",
+			responses: []response{
+				{Response: page(200, http.Header{"X-Sucuri-Id": {"12345"}}, "")},
+			},
+			want: "Sucuri",
+		},
+		{
+			name: "most evidence wins",
+			responses: []response{
+				{Response: page(200, http.Header{"Set-Cookie": {"TS01abcdef=1"}}, "")},
+				{Response: page(403, http.Header{"Server": {"cloudflare"}, "Cf-Ray": {"1-AMS"}},
+					"<title>Attention Required! | Cloudflare</title>"), blocked: true},
+			},
+			want: "Cloudflare",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if m := identifyAll(tt.responses); m != nil {
+				got = m.Name
+			}
+			if got != tt.want {
+				t.Errorf("identifyAll() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     func(w http.ResponseWriter, r *http.Request)
+		wantBlocked bool
+		wantClass   string
+		wantVendor  string
+	}{
+		{
+			name: "connection dropped",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err == nil {
+					conn.Close()
+				}
+			},
+			wantBlocked: true,
+			wantClass:   stage.ClassNetwork,
+			wantVendor:  "Generic",
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+			wantClass: stage.ClassTimeout,
+		},
+		{
+			name: "blocked status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			wantBlocked: true,
+			wantVendor:  "Generic",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("rankle") != "" {
+					w.Write([]byte("home"))
+					return
+				}
+				tt.handler(w, r)
+			}))
+			defer srv.Close()
+
+			cfg := config.Default()
+			cfg.HTTP.ShortTimeout = 200 * time.Millisecond
+			cfg.HTTP.MaxRetries = 0
+
+			analysis, err := New(scanner.New(cfg)).Probe(srv.URL)
+			if err != nil {
+				t.Fatalf("Probe() error: %v", err)
+			}
+			for _, p := range analysis.Probes {
+				if p.Blocked != tt.wantBlocked || p.ErrorClass != tt.wantClass {
+					t.Errorf("probe %s: blocked %t, class %q; want %t, %q", p.Name, p.Blocked, p.ErrorClass, tt.wantBlocked, tt.wantClass)
+				}
+			}
+			if analysis.Detected != (tt.wantVendor != "") || analysis.Vendor != tt.wantVendor {
+				t.Errorf("Probe() = detected %t, vendor %q; want vendor %q", analysis.Detected, analysis.Vendor, tt.wantVendor)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package wellknown
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// parseAdsTxt counts the seller records of an IAB ads.txt file and lists
+// the advertising systems they name. Files with no valid record or
+// variable are rejected.
+func parseAdsTxt(url, body string) *models.AdsTxt {
+	ads := &models.AdsTxt{URL: url}
+	systems := make(map[string]bool)
+	valid := false
+
+	for _, line := range strings.Split(body, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Variables are "name=value" lines without commas.
+		if key, value, ok := strings.Cut(line, "="); ok && !strings.Contains(line, ",") {
+			if strings.EqualFold(strings.TrimSpace(key), "contact") {
+				ads.Contact = strings.TrimSpace(value)
+			}
+			valid = true
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 {
+			continue
+		}
+		valid = true
+		ads.Records++
+		systems[strings.ToLower(strings.TrimSpace(fields[0]))] = true
+		switch strings.ToUpper(strings.TrimSpace(fields[2])) {
+		case "DIRECT":
+			ads.Direct++
+		case "RESELLER":
+			ads.Reseller++
+		}
+	}
+
+	if !valid {
+		return nil
+	}
+	for system := range systems {
+		ads.AdSystems = append(ads.AdSystems, system)
+	}
+	sort.Strings(ads.AdSystems)
+	return ads
+}
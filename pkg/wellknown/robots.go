@@ -0,0 +1,54 @@
+package wellknown
+
+import (
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// parseRobots extracts user agents, allow/disallow rules and sitemap
+// references from a robots.txt file. Paths are deduplicated across groups
+// in order of first appearance. Files with no recognized directive are
+// rejected.
+func parseRobots(url, body string) *models.RobotsTxt {
+	robots := &models.RobotsTxt{URL: url}
+	seen := make(map[string]bool)
+	add := func(list *[]string, kind, value string) {
+		if value == "" || seen[kind+value] {
+			return
+		}
+		seen[kind+value] = true
+		*list = append(*list, value)
+	}
+
+	directives := 0
+	for _, line := range strings.Split(body, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "user-agent":
+			add(&robots.UserAgents, "ua", value)
+		case "disallow":
+			add(&robots.Disallowed, "d", value)
+		case "allow":
+			add(&robots.Allowed, "a", value)
+		case "sitemap":
+			add(&robots.Sitemaps, "s", value)
+		default:
+			continue
+		}
+		directives++
+	}
+
+	if directives == 0 {
+		return nil
+	}
+	return robots
+}
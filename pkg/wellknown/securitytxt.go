@@ -0,0 +1,120 @@
+package wellknown
+
+import (
+	"strings"
+	"time"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+const (
+	pgpSignedHeader = "-----BEGIN PGP SIGNED MESSAGE-----"
+	pgpSignature    = "-----BEGIN PGP SIGNATURE-----"
+
+	// maxExpiresAhead is the longest Expires horizon RFC 9116 recommends.
+	maxExpiresAhead = 366 * 24 * time.Hour
+)
+
+// parseSecurityTxt parses an RFC 9116 security.txt file, validating the
+// required Contact and Expires fields. Problems are recorded in Errors
+// rather than rejecting the file; only a file with no recognized field at
+// all is rejected.
+func parseSecurityTxt(url, body string, now time.Time) *models.SecurityTxt {
+	sec := &models.SecurityTxt{URL: url}
+
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	if strings.HasPrefix(strings.TrimSpace(body), pgpSignedHeader) {
+		sec.Signed = true
+		body = signedContent(body)
+	}
+
+	fields := 0
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "contact":
+			sec.Contact = append(sec.Contact, value)
+		case "expires":
+			if sec.Expires != nil {
+				sec.Errors = append(sec.Errors, "Expires appears more than once")
+				break
+			}
+			expires, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				sec.Errors = append(sec.Errors, "Expires is not an RFC 3339 date: "+value)
+				break
+			}
+			sec.Expires = &expires
+		case "encryption":
+			sec.Encryption = append(sec.Encryption, value)
+		case "acknowledgments", "acknowledgements":
+			sec.Acknowledgments = append(sec.Acknowledgments, value)
+		case "preferred-languages":
+			for _, lang := range strings.Split(value, ",") {
+				if lang = strings.TrimSpace(lang); lang != "" {
+					sec.PreferredLanguages = append(sec.PreferredLanguages, lang)
+				}
+			}
+		case "canonical":
+			sec.Canonical = append(sec.Canonical, value)
+		case "policy":
+			sec.Policy = append(sec.Policy, value)
+		case "hiring":
+			sec.Hiring = append(sec.Hiring, value)
+		default:
+			continue
+		}
+		fields++
+	}
+
+	if fields == 0 {
+		return nil
+	}
+
+	if len(sec.Contact) == 0 {
+		sec.Errors = append(sec.Errors, "required Contact field is missing")
+	}
+	switch {
+	case sec.Expires == nil:
+		sec.Errors = append(sec.Errors, "required Expires field is missing or invalid")
+	case sec.Expires.Before(now):
+		sec.Expired = true
+		sec.Errors = append(sec.Errors, "file expired on "+sec.Expires.Format("2006-01-02"))
+	case sec.Expires.Sub(now) > maxExpiresAhead:
+		sec.Errors = append(sec.Errors, "Expires is more than a year ahead")
+	}
+	if !strings.HasPrefix(url, "https://") {
+		sec.Errors = append(sec.Errors, "file is not served over HTTPS")
+	}
+
+	return sec
+}
+
+// signedContent returns the cleartext of an OpenPGP cleartext-signed
+// message: the armor headers and signature block are dropped and
+// dash-escaped lines are restored.
+func signedContent(body string) string {
+	if i := strings.Index(body, pgpSignature); i >= 0 {
+		body = body[:i]
+	}
+	// Skip the "BEGIN PGP SIGNED MESSAGE" line and the armor headers, which
+	// end at the first empty line.
+	if _, rest, ok := strings.Cut(body, "\n\n"); ok {
+		body = rest
+	}
+
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "- ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,44 @@
+package wellknown
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+// sitemapDocument matches both <urlset> and <sitemapindex> roots.
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapLoc `xml:"url"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapLoc struct {
+	Loc string `xml:"loc"`
+}
+
+// parseSitemap counts the URLs of a sitemap or lists the children of a
+// sitemap index. Anything that is not a sitemap document is rejected.
+func parseSitemap(url, body string) *models.Sitemap {
+	var doc sitemapDocument
+	if err := xml.Unmarshal([]byte(body), &doc); err != nil {
+		return nil
+	}
+
+	sitemap := &models.Sitemap{URL: url}
+	switch doc.XMLName.Local {
+	case "urlset":
+		sitemap.URLCount = len(doc.URLs)
+	case "sitemapindex":
+		sitemap.Index = true
+		for _, s := range doc.Sitemaps {
+			if loc := strings.TrimSpace(s.Loc); loc != "" {
+				sitemap.Sitemaps = append(sitemap.Sitemaps, loc)
+			}
+		}
+	default:
+		return nil
+	}
+	return sitemap
+}
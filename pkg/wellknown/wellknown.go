@@ -0,0 +1,142 @@
+// Package wellknown probes the standard files sites publish at fixed paths:
+// robots.txt, sitemap.xml, security.txt, the OpenID Connect discovery
+// document, humans.txt and ads.txt.
+package wellknown
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+	"github.com/javicosvml/rankle-go/pkg/scanner"
+)
+
+// maxHumansTxt caps the humans.txt content kept in the result.
+const maxHumansTxt = 4096
+
+// Prober fetches and parses well-known files.
+type Prober struct {
+	scan *scanner.Scanner
+	now  func() time.Time
+}
+
+// New creates a Prober using the scanner's HTTP client.
+func New(scan *scanner.Scanner) *Prober {
+	return &Prober{scan: scan, now: time.Now}
+}
+
+// Probe fetches every well-known file under origin (scheme and host, no
+// trailing slash). Files that are missing or unparsable are left nil.
+func (p *Prober) Probe(origin string) *models.WellKnown {
+	origin = strings.TrimSuffix(origin, "/")
+	result := &models.WellKnown{}
+
+	if resp := p.fetchText(origin + "/robots.txt"); resp != nil {
+		result.Robots = parseRobots(resp.URL, resp.Body)
+	}
+
+	sitemapURL := origin + "/sitemap.xml"
+	if result.Robots != nil {
+		for _, u := range result.Robots.Sitemaps {
+			if sameOrigin(u, origin) {
+				sitemapURL = u
+				break
+			}
+		}
+	}
+	if resp := p.fetch(sitemapURL); resp != nil {
+		result.Sitemap = parseSitemap(resp.URL, resp.Body)
+	}
+
+	for _, path := range []string{"/.well-known/security.txt", "/security.txt"} {
+		if resp := p.fetchText(origin + path); resp != nil {
+			if sec := parseSecurityTxt(resp.URL, resp.Body, p.now()); sec != nil {
+				result.SecurityTxt = sec
+				break
+			}
+		}
+	}
+
+	if resp := p.fetch(origin + "/.well-known/openid-configuration"); resp != nil {
+		result.OpenID = parseOpenID(resp.URL, resp.Body)
+	}
+
+	if resp := p.fetchText(origin + "/humans.txt"); resp != nil && strings.TrimSpace(resp.Body) != "" {
+		content := resp.Body
+		if len(content) > maxHumansTxt {
+			content = strings.ToValidUTF8(content[:maxHumansTxt], "")
+		}
+		result.Humans = &models.HumansTxt{URL: resp.URL, Content: content}
+	}
+
+	if resp := p.fetchText(origin + "/ads.txt"); resp != nil {
+		result.Ads = parseAdsTxt(resp.URL, resp.Body)
+	}
+
+	return result
+}
+
+// sameOrigin reports whether rawURL has the scheme and host of origin.
+// Sitemaps robots.txt lists on other hosts are not fetched, so the probe
+// never sends requests beyond the site being scanned.
+func sameOrigin(rawURL, origin string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	o, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, o.Scheme) && strings.EqualFold(u.Host, o.Host)
+}
+
+// fetch returns the response for url if it succeeded with status 200 and
+// is not the site's soft-404 page.
+func (p *Prober) fetch(url string) *scanner.Response {
+	resp, err := p.scan.Fetch(url)
+	if err != nil || resp.StatusCode != http.StatusOK || p.scan.IsSoft404(resp) {
+		return nil
+	}
+	return resp
+}
+
+// fetchText is fetch for plain-text files. HTML responses are rejected too,
+// as no text file the prober reads is ever HTML.
+func (p *Prober) fetchText(url string) *scanner.Response {
+	resp := p.fetch(url)
+	if resp == nil || resp.MediaType() == "text/html" {
+		return nil
+	}
+	return resp
+}
+
+// parseOpenID decodes an OpenID Connect discovery document. Documents
+// without an issuer are rejected.
+func parseOpenID(url, body string) *models.OpenIDConfiguration {
+	var doc struct {
+		Issuer                string   `json:"issuer"`
+		AuthorizationEndpoint string   `json:"authorization_endpoint"`
+		TokenEndpoint         string   `json:"token_endpoint"`
+		UserinfoEndpoint      string   `json:"userinfo_endpoint"`
+		JWKSURI               string   `json:"jwks_uri"`
+		ScopesSupported       []string `json:"scopes_supported"`
+		GrantTypesSupported   []string `json:"grant_types_supported"`
+	}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil || doc.Issuer == "" {
+		return nil
+	}
+	return &models.OpenIDConfiguration{
+		URL:                   url,
+		Issuer:                doc.Issuer,
+		AuthorizationEndpoint: doc.AuthorizationEndpoint,
+		TokenEndpoint:         doc.TokenEndpoint,
+		UserinfoEndpoint:      doc.UserinfoEndpoint,
+		JWKSURI:               doc.JWKSURI,
+		ScopesSupported:       doc.ScopesSupported,
+		GrantTypesSupported:   doc.GrantTypesSupported,
+	}
+}
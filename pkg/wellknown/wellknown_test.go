@@ -0,0 +1,202 @@
+package wellknown
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/javicosvml/rankle-go/pkg/models"
+)
+
+func TestSameOrigin(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/sitemap_index.xml", true},
+		{"https://EXAMPLE.com/sitemaps/pages.xml", true},
+		{"http://example.com/sitemap.xml", false},
+		{"https://cdn.example.com/sitemap.xml", false},
+		{"https://example.com:8443/sitemap.xml", false},
+		{"https://attacker.example/sitemap.xml", false},
+		{"/sitemap.xml", false},
+		{"://bad", false},
+	}
+
+	for _, tt := range tests {
+		if got := sameOrigin(tt.url, "https://example.com"); got != tt.want {
+			t.Errorf("sameOrigin(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestParseSecurityTxt(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	const url = "https://example.com/.well-known/security.txt"
+
+	tests := []struct {
+		name        string
+		url         string
+		body        string
+		wantNil     bool
+		wantContact []string
+		wantExpired bool
+		wantSigned  bool
+		wantErrors  []string
+	}{
+		{
+			name:        "valid",
+			body:        "# comment\r\nContact: mailto:security@example.com\r\nExpires: 2026-06-01T00:00:00Z\r\nPreferred-Languages: en, es\r\n",
+			wantContact: []string{"mailto:security@example.com"},
+		},
+		{
+			name:        "expired",
+			body:        "Contact: mailto:security@example.com\nExpires: 2025-06-01T00:00:00Z\n",
+			wantContact: []string{"mailto:security@example.com"},
+			wantExpired: true,
+			wantErrors:  []string{"file expired on 2025-06-01"},
+		},
+		{
+			name:        "more than a year ahead",
+			body:        "Contact: mailto:security@example.com\nExpires: 2027-06-01T00:00:00Z\n",
+			wantContact: []string{"mailto:security@example.com"},
+			wantErrors:  []string{"Expires is more than a year ahead"},
+		},
+		{
+			name:       "missing contact and bad expires",
+			body:       "Policy: https://example.com/policy\nExpires: next year\n",
+			wantErrors: []string{"Expires is not an RFC 3339 date: next year", "required Contact field is missing", "required Expires field is missing or invalid"},
+		},
+		{
+			name:        "duplicate expires",
+			body:        "Contact: https://example.com/report\nExpires: 2026-06-01T00:00:00Z\nExpires: 2026-07-01T00:00:00Z\n",
+			wantContact: []string{"https://example.com/report"},
+			wantErrors:  []string{"Expires appears more than once"},
+		},
+		{
+			name:        "served over http",
+			url:         "http://example.com/security.txt",
+			body:        "Contact: mailto:security@example.com\nExpires: 2026-06-01T00:00:00Z\n",
+			wantContact: []string{"mailto:security@example.com"},
+			wantErrors:  []string{"file is not served over HTTPS"},
+		},
+		{
+			name: "pgp signed with dash-escaping",
+			body: "-----BEGIN PGP SIGNED MESSAGE-----\nHash: SHA256\n\n" +
+				"- Contact: mailto:security@example.com\nExpires: 2026-06-01T00:00:00Z\n" +
+				"-----BEGIN PGP SIGNATURE-----\n\nContact: mailto:inside-signature@example.com\n-----END PGP SIGNATURE-----\n",
+			wantContact: []string{"mailto:security@example.com"},
+			wantSigned:  true,
+		},
+		{
+			name:    "html page",
+			body:    "<!DOCTYPE html>\n<html><body>Not found</body></html>\n",
+			wantNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := tt.url
+			if u == "" {
+				u = url
+			}
+			sec := parseSecurityTxt(u, tt.body, now)
+			if tt.wantNil {
+				if sec != nil {
+					t.Fatalf("parseSecurityTxt() = %+v, want nil", sec)
+				}
+				return
+			}
+			if sec == nil {
+				t.Fatal("parseSecurityTxt() = nil")
+			}
+			if !reflect.DeepEqual(sec.Contact, tt.wantContact) {
+				t.Errorf("Contact = %q, want %q", sec.Contact, tt.wantContact)
+			}
+			if sec.Expired != tt.wantExpired {
+				t.Errorf("Expired = %v, want %v", sec.Expired, tt.wantExpired)
+			}
+			if sec.Signed != tt.wantSigned {
+				t.Errorf("Signed = %v, want %v", sec.Signed, tt.wantSigned)
+			}
+			if !reflect.DeepEqual(sec.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %q, want %q", sec.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestParseSitemap(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *models.Sitemap
+	}{
+		{
+			name: "urlset",
+			body: `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc></url>
+  <url><loc>https://example.com/about</loc></url>
+</urlset>`,
+			want: &models.Sitemap{URL: "u", URLCount: 2},
+		},
+		{
+			name: "sitemap index",
+			body: `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc> https://example.com/posts.xml </loc></sitemap>
+  <sitemap><loc></loc></sitemap>
+  <sitemap><loc>https://example.com/pages.xml</loc></sitemap>
+</sitemapindex>`,
+			want: &models.Sitemap{URL: "u", Index: true, Sitemaps: []string{"https://example.com/posts.xml", "https://example.com/pages.xml"}},
+		},
+		{"other root", `<rss><channel></channel></rss>`, nil},
+		{"not xml", "<html><body>soft 404", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSitemap("u", tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSitemap() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *models.RobotsTxt
+	}{
+		{
+			name: "groups merged and deduplicated",
+			body: "User-agent: *\nDisallow: /admin # staff only\nDisallow: /tmp\nAllow: /admin/login\n\n" +
+				"User-agent: Googlebot\nDISALLOW: /admin\nDisallow:\nAllow: /admin/login\n" +
+				"Sitemap: https://example.com/sitemap.xml\nSitemap: https://example.com/sitemap.xml\n",
+			want: &models.RobotsTxt{
+				URL:        "u",
+				UserAgents: []string{"*", "Googlebot"},
+				Disallowed: []string{"/admin", "/tmp"},
+				Allowed:    []string{"/admin/login"},
+				Sitemaps:   []string{"https://example.com/sitemap.xml"},
+			},
+		},
+		{
+			name: "same path allowed and disallowed",
+			body: "User-agent: *\nDisallow: /private\nAllow: /private\n",
+			want: &models.RobotsTxt{URL: "u", UserAgents: []string{"*"}, Disallowed: []string{"/private"}, Allowed: []string{"/private"}},
+		},
+		{"comments only", "# nothing here\n", nil},
+		{"html page", "<html><head><title>Home</title></head></html>", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRobots("u", tt.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRobots() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}